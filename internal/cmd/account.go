@@ -73,7 +73,8 @@ var accountDefaultCmd = &cobra.Command{
 	Long: `Set the default Claude Code account.
 
 The default account is used when no --account flag or HD_ACCOUNT env var
-is specified during muster or summon.
+is specified during muster or summon, and the warband doesn't select its own
+account with "account" in <warband>/settings/config.json.
 
 Examples:
   hd account default work
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/relics"
+	"github.com/deeklead/horde/internal/style"
+)
+
+var agentHeartbeatQuiet bool
+
+var agentHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat [agent-bead]",
+	Short: "Mark an agent bead as active now",
+	Long: `Record now as an agent bead's last_activity field.
+
+Raiders call this from their work loop as a liveness signal. Raid status
+uses last_activity for the worker age it shows.
+
+With no argument, the agent bead is detected from the current directory.
+
+Examples:
+  hd agents heartbeat                        # Current agent
+  hd agents heartbeat hd-horde-raider-nux    # Explicit agent bead
+  hd agents heartbeat --quiet                # No output (for loops)`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentHeartbeat,
+}
+
+func init() {
+	agentHeartbeatCmd.Flags().BoolVarP(&agentHeartbeatQuiet, "quiet", "q", false,
+		"Suppress output")
+
+	agentsCmd.AddCommand(agentHeartbeatCmd)
+}
+
+func runAgentHeartbeat(cmd *cobra.Command, args []string) error {
+	var agentBead string
+	if len(args) > 0 {
+		agentBead = args[0]
+	} else {
+		var err error
+		agentBead, err = detectAgentBeadID()
+		if err != nil {
+			return fmt.Errorf("auto-detecting agent: %w", err)
+		}
+	}
+
+	workDir, err := findLocalRelicsDir()
+	if err != nil {
+		return fmt.Errorf("not in a relics workspace: %w", err)
+	}
+
+	if err := relics.New(workDir).TouchAgent(agentBead); err != nil {
+		return err
+	}
+
+	if !agentHeartbeatQuiet {
+		fmt.Printf("%s Heartbeat recorded for %s\n", style.Bold.Render("✓"), agentBead)
+	}
+	return nil
+}
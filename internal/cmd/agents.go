@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/deeklead/horde/internal/constants"
 	"github.com/deeklead/horde/internal/lock"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/workspace"
@@ -574,9 +575,9 @@ func guessSessionFromWorkerDir(workerDir, townRoot string) string {
 
 	switch workerType {
 	case "clan":
-		return fmt.Sprintf("hd-%s-clan-%s", warband, workerName)
+		return session.CrewSessionName(warband, workerName)
 	case "raiders":
-		return fmt.Sprintf("hd-%s-%s", warband, workerName)
+		return session.RaiderSessionName(warband, workerName)
 	}
 
 	return ""
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -16,6 +17,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tui/raid"
 	"github.com/deeklead/horde/internal/workspace"
@@ -60,18 +62,19 @@ func looksLikeIssueID(s string) bool {
 
 // Raid command flags
 var (
-	raidMolecule     string
-	raidNotify       string
-	raidOwner        string
-	raidStatusJSON   bool
-	raidListJSON     bool
-	raidListStatus   string
-	raidListAll      bool
-	raidListTree     bool
-	raidInteractive  bool
-	raidStrandedJSON bool
-	raidCloseReason  string
-	raidCloseNotify  string
+	raidMolecule       string
+	raidNotify         string
+	raidOwner          string
+	raidStatusJSON     bool
+	raidListJSON       bool
+	raidListStatus     string
+	raidListAll        bool
+	raidListTree       bool
+	raidInteractive    bool
+	raidStrandedJSON   bool
+	raidCloseReason    string
+	raidCloseNotify    string
+	raidCloseAllLanded bool
 )
 
 var raidCmd = &cobra.Command{
@@ -157,6 +160,7 @@ Examples:
   hd raid list --all        # All raids (open + closed)
   hd raid list --status=closed  # Recently landed
   hd raid list --tree       # Show raid + child status tree
+  hd raid list --tree --json  # Nested raid → tracked issues as JSON
   hd raid list --json`,
 	RunE: runRaidList,
 }
@@ -220,11 +224,21 @@ Closes the raid regardless of tracked issue status. Use this to:
 
 The close is idempotent - closing an already-closed raid is a no-op.
 
+With --all-landed, closes every open raid whose tracked issues are all
+complete (the same sweep as 'hd raid check'), notifying each raid's owner
+and notify addresses.
+
 Examples:
   hd raid close hq-cv-abc
   hd raid close hq-cv-abc --reason="work done differently"
-  hd raid close hq-cv-xyz --notify warchief/`,
-	Args: cobra.ExactArgs(1),
+  hd raid close hq-cv-xyz --notify warchief/
+  hd raid close --all-landed`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if raidCloseAllLanded {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runRaidClose,
 }
 
@@ -253,6 +267,7 @@ func init() {
 	// Close flags
 	raidCloseCmd.Flags().StringVar(&raidCloseReason, "reason", "", "Reason for closing the raid")
 	raidCloseCmd.Flags().StringVar(&raidCloseNotify, "notify", "", "Agent to notify on close (e.g., warchief/)")
+	raidCloseCmd.Flags().BoolVar(&raidCloseAllLanded, "all-landed", false, "Close every open raid whose tracked issues are all complete")
 
 	// Add subcommands
 	raidCmd.AddCommand(raidCreateCmd)
@@ -284,7 +299,7 @@ func runRaidCreate(cmd *cobra.Command, args []string) error {
 	if looksLikeIssueID(name) {
 		trackedIssues = args // All args are issue IDs
 		// Get the first issue's title to use as raid name
-		if details := getIssueDetails(args[0]); details != nil && details.Title != "" {
+		if details, err := getIssueDetailsRetry(args[0]); err == nil && details.Title != "" {
 			name = details.Title
 		} else {
 			name = fmt.Sprintf("Tracking %s", args[0])
@@ -333,20 +348,17 @@ func runRaidCreate(cmd *cobra.Command, args []string) error {
 
 	// Notify address is stored in description (line 166-168) and read from there
 
-	// Add 'tracks' relations for each tracked issue
-	trackedCount := 0
+	// Add non-blocking 'tracks' relations for the tracked issues
+	tracked, failed, err := relics.NewWithRelicsDir(townRelics, townRelics).AddTracksBatch(raidID, trackedIssues)
+	if err != nil {
+		return fmt.Errorf("tracking issues: %w", err)
+	}
 	for _, issueID := range trackedIssues {
-		// Use --type=tracks for non-blocking tracking relation
-		depArgs := []string{"dep", "add", raidID, issueID, "--type=tracks"}
-		depCmd := exec.Command("rl", depArgs...)
-		depCmd.Dir = townRelics
-
-		if err := depCmd.Run(); err != nil {
+		if err, ok := failed[issueID]; ok {
 			style.PrintWarning("couldn't track %s: %v", issueID, err)
-		} else {
-			trackedCount++
 		}
 	}
+	trackedCount := len(tracked)
 
 	// Output
 	fmt.Printf("%s Created raid 🚚 %s\n\n", style.Bold.Render("✓"), raidID)
@@ -424,17 +436,14 @@ func runRaidAdd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Reopened raid %s\n", style.Bold.Render("↺"), raidID)
 	}
 
-	// Add 'tracks' relations for each issue
-	addedCount := 0
+	// Add 'tracks' relations for the issues
+	added, failed, err := relics.NewWithRelicsDir(townRelics, townRelics).AddTracksBatch(raidID, issuesToAdd)
+	if err != nil {
+		return fmt.Errorf("adding issues: %w", err)
+	}
 	for _, issueID := range issuesToAdd {
-		depArgs := []string{"dep", "add", raidID, issueID, "--type=tracks"}
-		depCmd := exec.Command("rl", depArgs...)
-		depCmd.Dir = townRelics
-
-		if err := depCmd.Run(); err != nil {
+		if err, ok := failed[issueID]; ok {
 			style.PrintWarning("couldn't add %s: %v", issueID, err)
-		} else {
-			addedCount++
 		}
 	}
 
@@ -442,9 +451,9 @@ func runRaidAdd(cmd *cobra.Command, args []string) error {
 	if reopened {
 		fmt.Println()
 	}
-	fmt.Printf("%s Added %d issue(s) to raid 🚚 %s\n", style.Bold.Render("✓"), addedCount, raidID)
-	if addedCount > 0 {
-		fmt.Printf("  Issues: %s\n", strings.Join(issuesToAdd[:addedCount], ", "))
+	fmt.Printf("%s Added %d issue(s) to raid 🚚 %s\n", style.Bold.Render("✓"), len(added), raidID)
+	if len(added) > 0 {
+		fmt.Printf("  Issues: %s\n", strings.Join(added, ", "))
 	}
 
 	return nil
@@ -474,6 +483,10 @@ func runRaidCheck(cmd *cobra.Command, args []string) error {
 }
 
 func runRaidClose(cmd *cobra.Command, args []string) error {
+	if raidCloseAllLanded {
+		return runRaidCloseAllLanded()
+	}
+
 	raidID := args[0]
 
 	townRelics, err := getTownRelicsDir()
@@ -551,6 +564,38 @@ func runRaidClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRaidCloseAllLanded closes every completable open raid and reports who
+// was notified for each.
+func runRaidCloseAllLanded() error {
+	if raidCloseReason != "" || raidCloseNotify != "" {
+		return fmt.Errorf("--all-landed cannot be combined with --reason or --notify")
+	}
+
+	townRelics, err := getTownRelicsDir()
+	if err != nil {
+		return err
+	}
+
+	closed, err := checkAndCloseCompletedRaids(townRelics)
+	if err != nil {
+		return err
+	}
+
+	if len(closed) == 0 {
+		fmt.Println("No landed raids to close.")
+		return nil
+	}
+
+	fmt.Printf("%s Closed %d landed raid(s):\n", style.Bold.Render("✓"), len(closed))
+	for _, c := range closed {
+		fmt.Printf("  🚚 %s: %s\n", c.ID, c.Title)
+		if len(c.Notified) > 0 {
+			fmt.Printf("     %s\n", style.Dim.Render("Notified: "+strings.Join(c.Notified, ", ")))
+		}
+	}
+	return nil
+}
+
 // sendCloseNotification sends a notification about raid closure.
 func sendCloseNotification(addr, raidID, title, reason string) {
 	subject := fmt.Sprintf("🚚 Raid closed: %s", title)
@@ -738,10 +783,17 @@ func isReadyIssue(t trackedIssueInfo, blockedIssues map[string]bool) bool {
 	return false // Session exists = not ready (worker is active)
 }
 
+// closedRaid describes a raid closed by checkAndCloseCompletedRaids.
+type closedRaid struct {
+	ID       string
+	Title    string
+	Notified []string // Addresses sent a completion notification
+}
+
 // checkAndCloseCompletedRaids finds open raids where all tracked issues are closed
 // and auto-closes them. Returns the list of raids that were closed.
-func checkAndCloseCompletedRaids(townRelics string) ([]struct{ ID, Title string }, error) {
-	var closed []struct{ ID, Title string }
+func checkAndCloseCompletedRaids(townRelics string) ([]closedRaid, error) {
+	var closed []closedRaid
 
 	// List all open raids
 	listArgs := []string{"list", "--type=raid", "--status=open", "--json"}
@@ -789,10 +841,10 @@ func checkAndCloseCompletedRaids(townRelics string) ([]struct{ ID, Title string
 				continue
 			}
 
-			closed = append(closed, struct{ ID, Title string }{raid.ID, raid.Title})
-
 			// Check if raid has notify address and send notification
-			notifyRaidCompletion(townRelics, raid.ID, raid.Title)
+			notified := notifyRaidCompletion(townRelics, raid.ID, raid.Title)
+
+			closed = append(closed, closedRaid{ID: raid.ID, Title: raid.Title, Notified: notified})
 		}
 	}
 
@@ -800,7 +852,8 @@ func checkAndCloseCompletedRaids(townRelics string) ([]struct{ ID, Title string
 }
 
 // notifyRaidCompletion sends notifications to owner and any notify addresses.
-func notifyRaidCompletion(townRelics, raidID, title string) {
+// Returns the addresses notified, in description order.
+func notifyRaidCompletion(townRelics, raidID, title string) []string {
 	// Get raid description to find owner and notify addresses
 	showArgs := []string{"show", raidID, "--json"}
 	showCmd := exec.Command("rl", showArgs...)
@@ -809,19 +862,20 @@ func notifyRaidCompletion(townRelics, raidID, title string) {
 	showCmd.Stdout = &stdout
 
 	if err := showCmd.Run(); err != nil {
-		return
+		return nil
 	}
 
 	var raids []struct {
 		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &raids); err != nil || len(raids) == 0 {
-		return
+		return nil
 	}
 
 	// Parse owner and notify addresses from description
 	desc := raids[0].Description
 	notified := make(map[string]bool) // Track who we've notified to avoid duplicates
+	var addrs []string
 
 	for _, line := range strings.Split(desc, "\n") {
 		var addr string
@@ -839,8 +893,10 @@ func notifyRaidCompletion(townRelics, raidID, title string) {
 			mailCmd := exec.Command("hd", mailArgs...)
 			_ = mailCmd.Run() // Best effort, ignore errors
 			notified[addr] = true
+			addrs = append(addrs, addr)
 		}
 	}
+	return addrs
 }
 
 func runRaidStatus(cmd *cobra.Command, args []string) error {
@@ -1060,6 +1116,10 @@ func runRaidList(cmd *cobra.Command, args []string) error {
 	}
 
 	if raidListJSON {
+		// Tree + JSON: emit the nested raid → tracked-issue hierarchy
+		if raidListTree {
+			return printRaidTreeJSON(townRelics, raids)
+		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(raids)
@@ -1141,6 +1201,55 @@ func printRaidTree(townRelics string, raids []struct {
 	return nil
 }
 
+// raidTreeNode is the JSON form of a raid in the tree view.
+type raidTreeNode struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Status    string             `json:"status"`
+	CreatedAt string             `json:"created_at"`
+	Completed int                `json:"completed"`
+	Total     int                `json:"total"`
+	Children  []trackedIssueInfo `json:"children"`
+}
+
+// printRaidTreeJSON outputs raids with their tracked issues as nested JSON.
+// This is the machine-readable equivalent of printRaidTree.
+func printRaidTreeJSON(townRelics string, raids []struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}) error {
+	nodes := make([]raidTreeNode, 0, len(raids))
+	for _, c := range raids {
+		tracked := getTrackedIssues(townRelics, c.ID)
+		if tracked == nil {
+			tracked = []trackedIssueInfo{}
+		}
+
+		completed := 0
+		for _, t := range tracked {
+			if t.Status == "closed" {
+				completed++
+			}
+		}
+
+		nodes = append(nodes, raidTreeNode{
+			ID:        c.ID,
+			Title:     c.Title,
+			Status:    c.Status,
+			CreatedAt: c.CreatedAt,
+			Completed: completed,
+			Total:     len(tracked),
+			Children:  tracked,
+		})
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(nodes)
+}
+
 func formatRaidStatus(status string) string {
 	switch status {
 	case "open":
@@ -1211,7 +1320,7 @@ func getTrackedIssues(townRelics, raidID string) []trackedIssueInfo {
 	}
 
 	// Single batch call to get all issue details
-	detailsMap := getIssueDetailsBatch(issueIDs)
+	detailsMap, unavailable := getIssueDetailsBatch(issueIDs)
 
 	// Get workers for these issues (only for non-closed issues)
 	openIssueIDs := make([]string, 0, len(issueIDs))
@@ -1235,6 +1344,10 @@ func getTrackedIssues(townRelics, raidID string) []trackedIssueInfo {
 			info.Status = details.Status
 			info.IssueType = details.IssueType
 			info.Assignee = details.Assignee
+		} else if unavailable[issueID] {
+			// Its warband's relics couldn't be reached; don't pass it off as external
+			info.Title = "(unavailable)"
+			info.Status = "unknown"
 		} else {
 			info.Title = "(external)"
 			info.Status = "unknown"
@@ -1262,11 +1375,13 @@ type issueDetails struct {
 }
 
 // getIssueDetailsBatch fetches details for multiple issues in a single rl show call.
-// Returns a map from issue ID to details. Missing/invalid issues are omitted from the map.
-func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
-	result := make(map[string]*issueDetails)
+// Returns a map from issue ID to details. Missing/invalid issues are omitted from the map;
+// issues whose relics database couldn't be reached are also reported in unavailable.
+func getIssueDetailsBatch(issueIDs []string) (result map[string]*issueDetails, unavailable map[string]bool) {
+	result = make(map[string]*issueDetails)
+	unavailable = make(map[string]bool)
 	if len(issueIDs) == 0 {
-		return result
+		return result, unavailable
 	}
 
 	// Build args: rl --no-daemon show id1 id2 id3 ... --json
@@ -1280,13 +1395,21 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 
 	if err := showCmd.Run(); err != nil {
 		// Batch failed - fall back to individual lookups for robustness
-		// This handles cases where some IDs are invalid/missing
+		// This handles cases where some IDs are invalid/missing. Once one
+		// database proves unavailable, later lookups aren't retried, so an
+		// outage costs one backoff rather than one per issue.
+		lookup := getIssueDetailsRetry
 		for _, id := range issueIDs {
-			if details := getIssueDetails(id); details != nil {
+			details, err := lookup(id)
+			switch {
+			case err == nil:
 				result[id] = details
+			case errors.Is(err, relics.ErrUnavailable):
+				unavailable[id] = true
+				lookup = getIssueDetails
 			}
 		}
-		return result
+		return result, unavailable
 	}
 
 	var issues []struct {
@@ -1297,7 +1420,7 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 		Assignee  string `json:"assignee"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return result
+		return result, unavailable
 	}
 
 	for _, issue := range issues {
@@ -1310,24 +1433,50 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 		}
 	}
 
-	return result
+	return result, unavailable
+}
+
+// issueLookupAttempts is how many times getIssueDetailsRetry tries a lookup
+// that fails with relics.ErrUnavailable.
+const issueLookupAttempts = 3
+
+// getIssueDetailsRetry is getIssueDetails, retrying with a short backoff while
+// the issue's relics database is unavailable (e.g., locked by another writer).
+func getIssueDetailsRetry(issueID string) (*issueDetails, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		var details *issueDetails
+		details, err = getIssueDetails(issueID)
+		if !errors.Is(err, relics.ErrUnavailable) || attempt == issueLookupAttempts {
+			return details, err
+		}
+		time.Sleep(time.Duration(attempt) * 200 * time.Millisecond)
+	}
 }
 
 // getIssueDetails fetches issue details by trying to show it via bd.
+// Returns relics.ErrNotFound if the issue doesn't exist, or an error wrapping
+// relics.ErrUnavailable if rl couldn't reach the database that would hold it
+// (database locked, connection refused, ...), which is worth retrying.
 // Prefer getIssueDetailsBatch for multiple issues to avoid N+1 subprocess calls.
-func getIssueDetails(issueID string) *issueDetails {
+func getIssueDetails(issueID string) (*issueDetails, error) {
 	// Use rl show with routing - it should find the issue in the right warband
 	// Use --no-daemon to ensure fresh data (avoid stale cache)
-	showCmd := exec.Command("rl", "--no-daemon", "show", issueID, "--json")
-	var stdout bytes.Buffer
+	args := []string{"--no-daemon", "show", issueID, "--json"}
+	showCmd := exec.Command("rl", args...)
+	var stdout, stderr bytes.Buffer
 	showCmd.Stdout = &stdout
+	showCmd.Stderr = &stderr
 
 	if err := showCmd.Run(); err != nil {
-		return nil
+		return nil, relics.WrapError(err, stderr.String(), args)
 	}
-	// Handle rl --no-daemon exit 0 bug: empty stdout means not found
+	// Handle rl --no-daemon exit 0 bug: empty stdout with the error on stderr
 	if stdout.Len() == 0 {
-		return nil
+		if stderr.Len() > 0 {
+			return nil, relics.WrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
+		}
+		return nil, relics.ErrNotFound
 	}
 
 	var issues []struct {
@@ -1337,8 +1486,11 @@ func getIssueDetails(issueID string) *issueDetails {
 		IssueType string `json:"issue_type"`
 		Assignee  string `json:"assignee"`
 	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
-		return nil
+	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+		return nil, fmt.Errorf("parsing rl show output: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil, relics.ErrNotFound
 	}
 
 	return &issueDetails{
@@ -1347,7 +1499,7 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status:    issues[0].Status,
 		IssueType: issues[0].IssueType,
 		Assignee:  issues[0].Assignee,
-	}
+	}, nil
 }
 
 // workerInfo holds info about a worker assigned to an issue.
@@ -1374,11 +1526,10 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 	}
 
 	// Discover warbands with relics databases
-	rigDirs, _ := filepath.Glob(filepath.Join(townRoot, "*", "raiders"))
+	locations, _ := relics.DiscoverRelicsDirs(townRoot)
 	var relicsDBS []string
-	for _, raidersDir := range rigDirs {
-		rigDir := filepath.Dir(raidersDir)
-		relicsDB := filepath.Join(rigDir, "warchief", "warband", ".relics", "relics.db")
+	for _, loc := range locations {
+		relicsDB := filepath.Join(loc.RelicsDir, "relics.db")
 		if _, err := os.Stat(relicsDB); err == nil {
 			relicsDBS = append(relicsDBS, relicsDB)
 		}
@@ -1398,7 +1549,7 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 
 	// Batch query: fetch all matching agents in one query per warband
 	query := fmt.Sprintf(
-		`SELECT id, banner_bead, last_activity FROM issues WHERE issue_type = 'agent' AND status = 'open' AND banner_bead IN (%s)`,
+		`SELECT id, banner_bead, last_activity, description FROM issues WHERE issue_type = 'agent' AND status = 'open' AND banner_bead IN (%s)`,
 		inClause)
 
 	// Query all warbands in parallel
@@ -1407,6 +1558,7 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 			ID           string `json:"id"`
 			BannerBead     string `json:"banner_bead"`
 			LastActivity string `json:"last_activity"`
+			Description  string `json:"description"`
 		}
 	}
 
@@ -1455,13 +1607,19 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 				continue
 			}
 
-			// Calculate age from last_activity
-			age := ""
-			if agent.LastActivity != "" {
-				if t, err := time.Parse(time.RFC3339, agent.LastActivity); err == nil {
-					age = formatWorkerAge(time.Since(t))
+			// Calculate age from the later of rl's last_activity and the
+			// heartbeat the raider recorded (hd agents heartbeat)
+			var last time.Time
+			heartbeat := relics.ParseAgentFields(agent.Description).LastActivity
+			for _, ts := range []string{agent.LastActivity, heartbeat} {
+				if t, err := time.Parse(time.RFC3339, ts); err == nil && t.After(last) {
+					last = t
 				}
 			}
+			age := ""
+			if !last.IsZero() {
+				age = formatWorkerAge(time.Since(last))
+			}
 
 			result[agent.BannerBead] = &workerInfo{
 				Worker: workerID,
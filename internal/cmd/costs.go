@@ -3,30 +3,35 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"os/signal"
 	"regexp"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/constants"
+	"github.com/deeklead/horde/internal/relics"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/workspace"
+	"golang.org/x/term"
 )
 
 var (
-	costsJSON    bool
-	costsToday   bool
-	costsWeek    bool
-	costsByRole  bool
-	costsByRig   bool
-	costsVerbose bool
+	costsJSON     bool
+	costsToday    bool
+	costsWeek     bool
+	costsByRole   bool
+	costsByRig    bool
+	costsByWorker bool
+	costsVerbose  bool
 
 	// Record subcommand flags
 	recordSession  string
@@ -39,6 +44,9 @@ var (
 
 	// Migrate subcommand flags
 	migrateDryRun bool
+
+	// Watch subcommand flags
+	watchInterval int
 )
 
 var costsCmd = &cobra.Command{
@@ -58,14 +66,34 @@ Examples:
   hd costs --week       # This week's costs from digest relics + today's wisps
   hd costs --by-role    # Breakdown by role (raider, witness, etc.)
   hd costs --by-warband     # Breakdown by warband
+  hd costs --by-worker  # Breakdown by worker (combine with --today/--week)
   hd costs --json       # Output as JSON
 
 Subcommands:
+  hd costs watch        # Continuously refresh live costs
   hd costs record       # Record session cost as ephemeral wisp (Stop hook)
   hd costs digest       # Aggregate wisps into daily digest bead (Shaman scout)`,
 	RunE: runCosts,
 }
 
+var costsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously display live session costs",
+	Long: `Refresh the live session cost table at a regular interval.
+
+Sessions whose cost increased since the last refresh are highlighted, and
+the header shows how much the total has grown since the last refresh and
+since watching started. Sessions that appear or end between refreshes are
+called out.
+
+Press Ctrl+C to stop.
+
+Examples:
+  hd costs watch              # Refresh every 5 seconds
+  hd costs watch -n 30        # Refresh every 30 seconds`,
+	RunE: runCostsWatch,
+}
+
 var costsRecordCmd = &cobra.Command{
 	Use:   "record",
 	Short: "Record session cost as an ephemeral wisp (called by Stop hook)",
@@ -131,8 +159,13 @@ func init() {
 	costsCmd.Flags().BoolVar(&costsWeek, "week", false, "Show this week's total from session events")
 	costsCmd.Flags().BoolVar(&costsByRole, "by-role", false, "Show breakdown by role")
 	costsCmd.Flags().BoolVar(&costsByRig, "by-warband", false, "Show breakdown by warband")
+	costsCmd.Flags().BoolVar(&costsByWorker, "by-worker", false, "Show breakdown by worker (warband/worker)")
 	costsCmd.Flags().BoolVarP(&costsVerbose, "verbose", "v", false, "Show debug output for failures")
 
+	// Add watch subcommand
+	costsCmd.AddCommand(costsWatchCmd)
+	costsWatchCmd.Flags().IntVarP(&watchInterval, "interval", "n", 5, "Refresh interval in seconds")
+
 	// Add record subcommand
 	costsCmd.AddCommand(costsRecordCmd)
 	costsRecordCmd.Flags().StringVar(&recordSession, "session", "", "Tmux session name to record")
@@ -177,9 +210,22 @@ type CostsOutput struct {
 	Total    float64            `json:"total_usd"`
 	ByRole   map[string]float64 `json:"by_role,omitempty"`
 	ByRig    map[string]float64 `json:"by_rig,omitempty"`
+	ByWorker map[string]float64 `json:"by_worker,omitempty"`
 	Period   string             `json:"period,omitempty"`
 }
 
+// workerIdentity returns the warband/worker key used for per-worker cost
+// breakdowns, or "" if the entry has no worker.
+func workerIdentity(warband, worker string) string {
+	if worker == "" {
+		return ""
+	}
+	if warband == "" {
+		return worker
+	}
+	return warband + "/" + worker
+}
+
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
@@ -194,12 +240,43 @@ func runCosts(cmd *cobra.Command, args []string) error {
 }
 
 func runLiveCosts() error {
-	t := tmux.NewTmux()
+	costs, total, err := collectLiveCosts(tmux.NewTmux())
+	if err != nil {
+		return err
+	}
+
+	var byWorker map[string]float64
+	if costsByWorker {
+		byWorker = make(map[string]float64)
+		for _, c := range costs {
+			if key := workerIdentity(c.Warband, c.Worker); key != "" {
+				byWorker[key] += c.Cost
+			}
+		}
+	}
 
+	if costsJSON {
+		return outputCostsJSON(CostsOutput{
+			Sessions: costs,
+			Total:    total,
+			ByWorker: byWorker,
+		})
+	}
+
+	if err := outputCostsHuman(costs, total); err != nil {
+		return err
+	}
+	printWorkerBreakdown(byWorker)
+	return nil
+}
+
+// collectLiveCosts scrapes costs from all running Horde tmux sessions.
+// Returns the per-session costs sorted by session name and their total.
+func collectLiveCosts(t *tmux.Tmux) ([]SessionCost, float64, error) {
 	// Get all tmux sessions
 	sessions, err := t.ListSessions()
 	if err != nil {
-		return fmt.Errorf("listing sessions: %w", err)
+		return nil, 0, fmt.Errorf("listing sessions: %w", err)
 	}
 
 	var costs []SessionCost
@@ -229,7 +306,7 @@ func runLiveCosts() error {
 		costs = append(costs, SessionCost{
 			Session: session,
 			Role:    role,
-			Warband:     warband,
+			Warband: warband,
 			Worker:  worker,
 			Cost:    cost,
 			Running: running,
@@ -242,14 +319,151 @@ func runLiveCosts() error {
 		return costs[i].Session < costs[j].Session
 	})
 
-	if costsJSON {
-		return outputCostsJSON(CostsOutput{
-			Sessions: costs,
-			Total:    total,
-		})
+	return costs, total, nil
+}
+
+func runCostsWatch(cmd *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", watchInterval)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(time.Duration(watchInterval) * time.Second)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	t := tmux.NewTmux()
+
+	var prev map[string]float64 // nil until the first tick
+	var prevTotal, startTotal float64
+
+	for {
+		costs, total, err := collectLiveCosts(t)
+
+		if isTTY {
+			fmt.Print("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+
+		timestamp := time.Now().Format("15:04:05")
+		header := fmt.Sprintf("[%s] hd costs watch (every %ds, Ctrl+C to stop)", timestamp, watchInterval)
+		if isTTY {
+			fmt.Printf("%s\n", style.Dim.Render(header))
+		} else {
+			fmt.Printf("%s\n", header)
+		}
+
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			if prev == nil {
+				startTotal = total
+				prevTotal = total
+			}
+			outputCostsWatchTick(costs, total, prev, total-prevTotal, total-startTotal)
+
+			next := make(map[string]float64, len(costs))
+			for _, c := range costs {
+				next[c.Session] = c.Cost
+			}
+			prev = next
+			prevTotal = total
+		}
+
+		select {
+		case <-sigChan:
+			if isTTY {
+				fmt.Println("\nStopped.")
+			}
+			return nil
+		case <-ticker.C:
+		}
 	}
+}
 
-	return outputCostsHuman(costs, total)
+// outputCostsWatchTick renders one refresh of hd costs watch.
+// prev holds each session's cost at the previous tick (nil on the first tick).
+// Sessions whose cost rose since prev are highlighted; sessions that appeared
+// or disappeared since prev are called out.
+func outputCostsWatchTick(costs []SessionCost, total float64, prev map[string]float64, tickDelta, sinceStart float64) {
+	fmt.Printf("\n%s Live Session Costs  %s\n\n", style.Bold.Render("💰"),
+		style.Dim.Render(fmt.Sprintf("Δ last tick %s · Δ since start %s", formatCostDelta(tickDelta), formatCostDelta(sinceStart))))
+
+	if len(costs) == 0 {
+		fmt.Println(style.Dim.Render("No Horde sessions found"))
+	} else {
+		fmt.Printf("%-25s %-10s %-15s %10s %10s %8s\n",
+			"Session", "Role", "Warband/Worker", "Cost", "Δ", "Status")
+		fmt.Println(strings.Repeat("─", 86))
+
+		for _, c := range costs {
+			statusIcon := style.Success.Render("●")
+			if !c.Running {
+				statusIcon = style.Dim.Render("○")
+			}
+
+			rigWorker := c.Warband
+			if c.Worker != "" && c.Worker != c.Warband {
+				if rigWorker != "" {
+					rigWorker += "/" + c.Worker
+				} else {
+					rigWorker = c.Worker
+				}
+			}
+
+			delta := ""
+			jumped := false
+			if prev != nil {
+				if before, ok := prev[c.Session]; !ok {
+					delta = "new"
+				} else if c.Cost != before {
+					delta = formatCostDelta(c.Cost - before)
+					jumped = c.Cost > before
+				}
+			}
+
+			line := fmt.Sprintf("%-25s %-10s %-15s %10s %10s",
+				c.Session,
+				c.Role,
+				rigWorker,
+				fmt.Sprintf("$%.2f", c.Cost),
+				delta)
+			if jumped {
+				line = style.Warning.Render(line)
+			}
+			fmt.Printf("%s %8s\n", line, statusIcon)
+		}
+
+		fmt.Println(strings.Repeat("─", 86))
+	}
+
+	// Sessions that ended since the last tick
+	current := make(map[string]bool, len(costs))
+	for _, c := range costs {
+		current[c.Session] = true
+	}
+	var ended []string
+	for session := range prev {
+		if !current[session] {
+			ended = append(ended, session)
+		}
+	}
+	sort.Strings(ended)
+	for _, session := range ended {
+		fmt.Println(style.Dim.Render(fmt.Sprintf("%s ended (last seen $%.2f)", session, prev[session])))
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), fmt.Sprintf("$%.2f", total))
+}
+
+// formatCostDelta formats a cost change with an explicit sign (e.g., "+$0.42").
+func formatCostDelta(d float64) string {
+	if d < 0 {
+		return fmt.Sprintf("-$%.2f", -d)
+	}
+	return fmt.Sprintf("+$%.2f", d)
 }
 
 func runCostsFromLedger() error {
@@ -290,6 +504,7 @@ func runCostsFromLedger() error {
 	var total float64
 	byRole := make(map[string]float64)
 	byRig := make(map[string]float64)
+	byWorker := make(map[string]float64)
 
 	for _, entry := range entries {
 		total += entry.CostUSD
@@ -297,6 +512,9 @@ func runCostsFromLedger() error {
 		if entry.Warband != "" {
 			byRig[entry.Warband] += entry.CostUSD
 		}
+		if key := workerIdentity(entry.Warband, entry.Worker); key != "" {
+			byWorker[key] += entry.CostUSD
+		}
 	}
 
 	// Build output
@@ -310,6 +528,9 @@ func runCostsFromLedger() error {
 	if costsByRig {
 		output.ByRig = byRig
 	}
+	if costsByWorker {
+		output.ByWorker = byWorker
+	}
 
 	// Set period label
 	if costsToday {
@@ -347,11 +568,6 @@ type SessionPayload struct {
 	EndedAt   string  `json:"ended_at"`
 }
 
-// EventListItem represents an event from rl list (minimal fields).
-type EventListItem struct {
-	ID string `json:"id"`
-}
-
 // querySessionEvents queries relics for session.ended events and converts them to CostEntry.
 // It queries both encampment-level relics and all warband-level relics to find all session events.
 // Errors from individual locations are logged (if verbose) but don't fail the query.
@@ -366,18 +582,13 @@ func querySessionEvents() []CostEntry {
 	// Collect all relics locations to query
 	relicsLocations := []string{townRoot}
 
-	// Load warbands to find all warband relics locations
-	rigsConfigPath := filepath.Join(townRoot, constants.DirWarchief, constants.FileRigsJSON)
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
-	if err == nil && rigsConfig != nil {
-		for rigName := range rigsConfig.Warbands {
-			rigPath := filepath.Join(townRoot, rigName)
-			// Verify warband has a relics database
-			rigRelicsPath := filepath.Join(rigPath, constants.DirRelics)
-			if _, statErr := os.Stat(rigRelicsPath); statErr == nil {
-				relicsLocations = append(relicsLocations, rigPath)
-			}
-		}
+	// Add every registered warband that has a relics database
+	rigLocations, err := relics.DiscoverRelicsDirs(townRoot)
+	if err != nil && costsVerbose {
+		fmt.Fprintf(os.Stderr, "[costs] discovering warband relics failed: %v\n", err)
+	}
+	for _, loc := range rigLocations {
+		relicsLocations = append(relicsLocations, loc.Path)
 	}
 
 	// Query each relics location and merge results
@@ -407,70 +618,30 @@ func querySessionEvents() []CostEntry {
 	return allEntries
 }
 
+// eventsUnavailable reports whether err means there is no relics database
+// to read events from, which callers treat as having no events.
+func eventsUnavailable(err error) bool {
+	return errors.Is(err, relics.ErrNotInstalled) || errors.Is(err, relics.ErrUnavailable) || errors.Is(err, relics.ErrNotFound)
+}
+
 // querySessionEventsFromLocation queries a single relics location for session.ended events.
 func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
-	// Step 1: Get list of event IDs
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
-	}
-
-	listCmd := exec.Command("rl", listArgs...)
-	listCmd.Dir = location
-	listOutput, err := listCmd.Output()
-	if err != nil {
-		// If rl fails (e.g., no relics database), return empty list
-		return nil, nil
-	}
-
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return nil, fmt.Errorf("parsing event list: %w", err)
-	}
-
-	if len(listItems) == 0 {
-		return nil, nil
-	}
-
-	// Step 2: Get full details for all events using rl show
-	// (bd list doesn't include event_kind, actor, payload)
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("rl", showArgs...)
-	showCmd.Dir = location
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing event details: %w", err)
-	}
-
 	var entries []CostEntry
-	for _, event := range events {
-		// Filter for session.ended events only
+	err := relics.New(location).ForEachEvent(func(event *relics.Issue) bool {
 		if event.EventKind != "session.ended" {
-			continue
+			return true
 		}
 
 		// Parse payload
 		var payload SessionPayload
 		if event.Payload != "" {
 			if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
-				continue // Skip malformed payloads
+				return true // Skip malformed payloads
 			}
 		}
 
 		// Parse ended_at from payload, fall back to created_at
-		endedAt := event.CreatedAt
+		endedAt, _ := time.Parse(time.RFC3339, event.CreatedAt)
 		if payload.EndedAt != "" {
 			if parsed, err := time.Parse(time.RFC3339, payload.EndedAt); err == nil {
 				endedAt = parsed
@@ -486,6 +657,14 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 			EndedAt:   endedAt,
 			WorkItem:  event.Target,
 		})
+		return true
+	})
+	if eventsUnavailable(err) {
+		// If rl fails (e.g., no relics database), return empty list
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return entries, nil
@@ -493,84 +672,55 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 // queryDigestRelics queries costs.digest events from the past N days and extracts session entries.
 func queryDigestRelics(days int) ([]CostEntry, error) {
-	// Get list of event IDs
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
-	}
-
-	listCmd := exec.Command("rl", listArgs...)
-	listOutput, err := listCmd.Output()
-	if err != nil {
-		return nil, nil
-	}
-
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return nil, fmt.Errorf("parsing event list: %w", err)
-	}
-
-	if len(listItems) == 0 {
-		return nil, nil
-	}
-
-	// Get full details for all events
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("rl", showArgs...)
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing event details: %w", err)
-	}
-
 	// Calculate date range
 	now := time.Now()
 	cutoff := now.AddDate(0, 0, -days)
 
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+
 	var entries []CostEntry
-	for _, event := range events {
-		// Filter for costs.digest events only
+	err = relics.New(cwd).ForEachEvent(func(event *relics.Issue) bool {
 		if event.EventKind != "costs.digest" {
-			continue
+			return true
 		}
 
 		// Parse the digest payload
 		var digest CostDigest
 		if event.Payload != "" {
 			if err := json.Unmarshal([]byte(event.Payload), &digest); err != nil {
-				continue
+				return true
 			}
 		}
 
 		// Check date is within range
 		digestDate, err := time.Parse("2006-01-02", digest.Date)
 		if err != nil {
-			continue
+			return true
 		}
 		if digestDate.Before(cutoff) {
-			continue
+			return true
 		}
 
 		// Extract individual session entries from the digest
 		entries = append(entries, digest.Sessions...)
+		return true
+	})
+	if eventsUnavailable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return entries, nil
 }
 
-// parseSessionName extracts role, warband, and worker from a session name.
-// Session names follow the pattern: gt-<warband>-<worker> or gt-<global-agent>
+// parseSessionName extracts role, warband, and worker from a session name,
+// using session.ParseSessionName. Global agents report themselves as the
+// worker, under either the hq- prefix or the legacy gt- one.
 // Examples:
 //   - gt-warchief -> role=warchief, warband="", worker="warchief"
 //   - gt-shaman -> role=shaman, warband="", worker="shaman"
@@ -578,11 +728,11 @@ func queryDigestRelics(days int) ([]CostEntry, error) {
 //   - gt-horde-witness -> role=witness, warband=horde, worker=""
 //   - gt-horde-forge -> role=forge, warband=horde, worker=""
 //   - gt-horde-clan-joe -> role=clan, warband=horde, worker=joe
-func parseSessionName(session string) (role, warband, worker string) {
+func parseSessionName(sessionName string) (role, warband, worker string) {
 	// Remove gt- prefix
-	name := strings.TrimPrefix(session, constants.SessionPrefix)
+	name := strings.TrimPrefix(sessionName, constants.SessionPrefix)
 
-	// Check for global agents
+	// Check for global agents under the legacy prefix
 	switch name {
 	case "warchief":
 		return constants.RoleWarchief, "", "warchief"
@@ -590,30 +740,15 @@ func parseSessionName(session string) (role, warband, worker string) {
 		return constants.RoleShaman, "", "shaman"
 	}
 
-	// Parse warband-based session: warband-worker or warband-clan-name
-	parts := strings.SplitN(name, "-", 3)
-	if len(parts) < 2 {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
 		return "unknown", "", name
 	}
-
-	warband = parts[0]
-	worker = parts[1]
-
-	// Check for clan pattern: warband-clan-name
-	if worker == "clan" && len(parts) >= 3 {
-		return constants.RoleCrew, warband, parts[2]
-	}
-
-	// Check for special workers
-	switch worker {
-	case "witness":
-		return constants.RoleWitness, warband, ""
-	case "forge":
-		return constants.RoleForge, warband, ""
+	switch id.Role {
+	case session.RoleWarchief, session.RoleShaman:
+		return string(id.Role), "", string(id.Role)
 	}
-
-	// Default to raider
-	return constants.RoleRaider, warband, worker
+	return string(id.Role), id.Warband, id.Name
 }
 
 // extractCost finds the most recent cost value in pane content.
@@ -685,6 +820,28 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 	return nil
 }
 
+// printWorkerBreakdown prints per-worker costs, most expensive first.
+func printWorkerBreakdown(byWorker map[string]float64) {
+	if len(byWorker) == 0 {
+		return
+	}
+	workers := make([]string, 0, len(byWorker))
+	for w := range byWorker {
+		workers = append(workers, w)
+	}
+	sort.Slice(workers, func(i, j int) bool {
+		if byWorker[workers[i]] != byWorker[workers[j]] {
+			return byWorker[workers[i]] > byWorker[workers[j]]
+		}
+		return workers[i] < workers[j]
+	})
+
+	fmt.Printf("\n%s\n", style.Bold.Render("By Worker:"))
+	for _, w := range workers {
+		fmt.Printf("  %-25s $%.2f\n", w, byWorker[w])
+	}
+}
+
 func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 	periodStr := ""
 	if output.Period != "" {
@@ -713,6 +870,8 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 		}
 	}
 
+	printWorkerBreakdown(output.ByWorker)
+
 	// Session count
 	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), len(entries))
 
@@ -841,36 +1000,31 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 //   - Raiders: gt-{warband}-{raider} (e.g., gt-horde-toast)
 //   - Clan: gt-{warband}-clan-{clan} (e.g., gt-horde-clan-max)
 //   - Witness/Forge: gt-{warband}-{role} (e.g., gt-horde-witness)
-//   - Warchief/Shaman: gt-{encampment}-{role} (e.g., gt-ai-warchief)
+//   - Warchief/Shaman: hq-{role} (e.g., hq-warchief)
 func deriveSessionName() string {
 	role := os.Getenv("HD_ROLE")
 	warband := os.Getenv("HD_WARBAND")
 	raider := os.Getenv("HD_RAIDER")
 	clan := os.Getenv("HD_CLAN")
-	encampment := os.Getenv("HD_ENCAMPMENT")
 
 	// Raider: gt-{warband}-{raider}
 	if raider != "" && warband != "" {
-		return fmt.Sprintf("hd-%s-%s", warband, raider)
+		return session.RaiderSessionName(warband, raider)
 	}
 
 	// Clan: gt-{warband}-clan-{clan}
 	if clan != "" && warband != "" {
-		return fmt.Sprintf("hd-%s-clan-%s", warband, clan)
+		return session.CrewSessionName(warband, clan)
 	}
 
-	// Encampment-level roles (warchief, shaman): gt-{encampment}-{role} or gt-{role}
+	// Encampment-level roles (warchief, shaman): one session per machine
 	if role == "warchief" || role == "shaman" {
-		if encampment != "" {
-			return fmt.Sprintf("hd-%s-%s", encampment, role)
-		}
-		// No encampment set - use simple gt-{role} pattern
-		return fmt.Sprintf("hd-%s", role)
+		return session.SessionName(role, "", "")
 	}
 
 	// Warband-based roles (witness, forge): gt-{warband}-{role}
 	if role != "" && warband != "" {
-		return fmt.Sprintf("hd-%s-%s", warband, role)
+		return session.SessionName(role, warband, "")
 	}
 
 	return ""
@@ -1290,92 +1444,49 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 
 // runCostsMigrate migrates legacy session.ended relics to the new architecture.
 func runCostsMigrate(cmd *cobra.Command, args []string) error {
-	// Query all session.ended events (both open and closed)
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
 	}
 
-	listCmd := exec.Command("rl", listArgs...)
-	listOutput, err := listCmd.Output()
-	if err != nil {
+	result, err := relics.New(cwd).MigrateLegacyEvents("session.ended", "migrated to wisp architecture", migrateDryRun)
+	if eventsUnavailable(err) {
 		fmt.Println(style.Dim.Render("No events found or rl command failed"))
 		return nil
 	}
-
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return fmt.Errorf("parsing event list: %w", err)
+	if err != nil {
+		return err
 	}
 
-	if len(listItems) == 0 {
+	if result.Scanned == 0 {
 		fmt.Println(style.Dim.Render("No events found"))
 		return nil
 	}
 
-	// Get full details for all events
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("rl", showArgs...)
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return fmt.Errorf("parsing event details: %w", err)
-	}
-
-	// Find open session.ended events
-	var openEvents []SessionEvent
-	var closedCount int
-	for _, event := range events {
-		if event.EventKind != "session.ended" {
-			continue
-		}
-		if event.Status == "closed" {
-			closedCount++
-			continue
-		}
-		openEvents = append(openEvents, event)
-	}
-
 	fmt.Printf("%s Legacy session.ended relics:\n", style.Bold.Render("📊"))
-	fmt.Printf("  Closed: %d (no action needed)\n", closedCount)
-	fmt.Printf("  Open:   %d (will be closed)\n", len(openEvents))
+	fmt.Printf("  Closed: %d (no action needed)\n", result.AlreadyClosed)
+	fmt.Printf("  Open:   %d (will be closed)\n", len(result.Open))
 
-	if len(openEvents) == 0 {
+	if len(result.Open) == 0 {
 		fmt.Println(style.Success.Render("\n✓ No migration needed - all session.ended events are already closed"))
 		return nil
 	}
 
 	if migrateDryRun {
-		fmt.Printf("\n%s Would close %d open session.ended events\n", style.Bold.Render("[DRY RUN]"), len(openEvents))
-		for _, event := range openEvents {
+		fmt.Printf("\n%s Would close %d open session.ended events\n", style.Bold.Render("[DRY RUN]"), len(result.Open))
+		for _, event := range result.Open {
 			fmt.Printf("  - %s: %s\n", event.ID, event.Title)
 		}
 		return nil
 	}
 
-	// Close all open session.ended events
-	closedMigrated := 0
-	for _, event := range openEvents {
-		closeCmd := exec.Command("rl", "close", event.ID, "--reason=migrated to wisp architecture")
-		if err := closeCmd.Run(); err != nil {
+	for _, event := range result.Open {
+		if err, failed := result.Failed[event.ID]; failed {
 			fmt.Fprintf(os.Stderr, "warning: could not close %s: %v\n", event.ID, err)
-			continue
 		}
-		closedMigrated++
 	}
 
-	fmt.Printf("\n%s Migrated %d session.ended events (closed)\n", style.Success.Render("✓"), closedMigrated)
+	fmt.Printf("\n%s Migrated %d session.ended events (closed)\n", style.Success.Render("✓"), len(result.Closed))
 	fmt.Println(style.Dim.Render("Legacy relics preserved for historical queries."))
 	fmt.Println(style.Dim.Render("New session costs will use ephemeral wisps + daily digests."))
 
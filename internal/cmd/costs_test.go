@@ -51,7 +51,7 @@ func TestDeriveSessionName(t *testing.T) {
 				"HD_ROLE": "warchief",
 				"HD_ENCAMPMENT": "ai",
 			},
-			expected: "hd-ai-warchief",
+			expected: "hq-warchief",
 		},
 		{
 			name: "shaman session",
@@ -59,21 +59,21 @@ func TestDeriveSessionName(t *testing.T) {
 				"HD_ROLE": "shaman",
 				"HD_ENCAMPMENT": "ai",
 			},
-			expected: "hd-ai-shaman",
+			expected: "hq-shaman",
 		},
 		{
 			name: "warchief session without HD_ENCAMPMENT",
 			envVars: map[string]string{
 				"HD_ROLE": "warchief",
 			},
-			expected: "hd-warchief",
+			expected: "hq-warchief",
 		},
 		{
 			name: "shaman session without HD_ENCAMPMENT",
 			envVars: map[string]string{
 				"HD_ROLE": "shaman",
 			},
-			expected: "hd-shaman",
+			expected: "hq-shaman",
 		},
 		{
 			name:     "no env vars",
@@ -112,3 +112,21 @@ func TestDeriveSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCostDelta(t *testing.T) {
+	tests := []struct {
+		delta    float64
+		expected string
+	}{
+		{0, "+$0.00"},
+		{0.42, "+$0.42"},
+		{12.5, "+$12.50"},
+		{-1.25, "-$1.25"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCostDelta(tt.delta); got != tt.expected {
+			t.Errorf("formatCostDelta(%v) = %q, want %q", tt.delta, got, tt.expected)
+		}
+	}
+}
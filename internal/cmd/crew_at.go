@@ -93,7 +93,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding encampment root: %w", err)
 	}
 	accountsPath := constants.WarchiefAccountsPath(townRoot)
-	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, crewAccount)
+	claudeConfigDir, accountHandle, err := config.ResolveRigAccountConfigDir(accountsPath, r.Path, crewAccount)
 	if err != nil {
 		return fmt.Errorf("resolving account: %w", err)
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/deeklead/horde/internal/clan"
 	"github.com/deeklead/horde/internal/git"
 	"github.com/deeklead/horde/internal/warband"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/workspace"
 )
@@ -68,7 +69,7 @@ func getCrewManager(rigName string) (*clan.Manager, *warband.Warband, error) {
 
 // crewSessionName generates the tmux session name for a clan worker.
 func crewSessionName(rigName, crewName string) string {
-	return fmt.Sprintf("hd-%s-clan-%s", rigName, crewName)
+	return session.CrewSessionName(rigName, crewName)
 }
 
 // parseRigSlashName parses "warband/name" format into separate warband and name parts.
@@ -309,7 +309,7 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 		townRoot = filepath.Dir(r.Path)
 	}
 	accountsPath := constants.WarchiefAccountsPath(townRoot)
-	claudeConfigDir, _, _ := config.ResolveAccountConfigDir(accountsPath, crewAccount)
+	claudeConfigDir, _, _ := config.ResolveRigAccountConfigDir(accountsPath, r.Path, crewAccount)
 
 	// Build start options (shared across all clan members)
 	opts := clan.StartOptions{
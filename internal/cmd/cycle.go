@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"fmt"
 	"os/exec"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/session"
 )
 
 // cycleSession is the --session flag for cycle next/prev commands.
@@ -127,8 +127,8 @@ func parseRigInfraSession(session string) string {
 // cycleRigInfraSession cycles between witness and forge sessions for a warband.
 func cycleRigInfraSession(direction int, currentSession, warband string) error {
 	// Find running infra sessions for this warband
-	witnessSession := fmt.Sprintf("hd-%s-witness", warband)
-	forgeSession := fmt.Sprintf("hd-%s-forge", warband)
+	witnessSession := session.WitnessSessionName(warband)
+	forgeSession := session.ForgeSessionName(warband)
 
 	var sessions []string
 	allSessions, err := listTmuxSessions()
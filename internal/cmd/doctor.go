@@ -64,6 +64,7 @@ Warband checks (with --warband flag):
   - warchief-clone-exists       Verify warchief/warband/ clone exists (fixable)
   - raider-clones-valid     Verify raider directories are valid clones
   - relics-config-valid       Verify relics configuration (fixable)
+  - warband-settings-fields  Detect misspelled keys in warband settings
 
 Routing checks (fixable):
   - routes-config            Check relics routing configuration
@@ -72,6 +73,7 @@ Routing checks (fixable):
 Session hook checks:
   - session-hooks            Check settings.json use session-start.sh
   - claude-settings          Check Claude settings.json match templates (fixable)
+  - role-agents              Warn when warband role_agents shadow the encampment's
 
 Scout checks:
   - scout-totems-exist   Verify scout totems exist
@@ -163,6 +165,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyHordeCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
+	d.Register(doctor.NewRoleAgentsCheck())
 
 	// Priming subsystem check
 	d.Register(doctor.NewPrimingCheck())
@@ -13,6 +13,7 @@ import (
 	"github.com/deeklead/horde/internal/git"
 	"github.com/deeklead/horde/internal/drums"
 	"github.com/deeklead/horde/internal/raider"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/warband"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/encampmentlog"
@@ -705,7 +706,7 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 		return fmt.Errorf("cannot determine session: warband=%q, raider=%q", rigName, raiderName)
 	}
 
-	sessionName := fmt.Sprintf("hd-%s-%s", rigName, raiderName)
+	sessionName := session.RaiderSessionName(rigName, raiderName)
 	agentID := fmt.Sprintf("%s/raiders/%s", rigName, raiderName)
 
 	// Log to encampmentlog (human-readable audit log)
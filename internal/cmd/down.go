@@ -162,7 +162,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 	// Phase 2a: Stop refineries
 	for _, rigName := range warbands {
-		sessionName := fmt.Sprintf("hd-%s-forge", rigName)
+		sessionName := session.ForgeSessionName(rigName)
 		if downDryRun {
 			if sessionSet.Has(sessionName) {
 				printDownStatus(fmt.Sprintf("Forge (%s)", rigName), true, "would stop")
@@ -182,7 +182,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 	// Phase 2b: Stop witnesses
 	for _, rigName := range warbands {
-		sessionName := fmt.Sprintf("hd-%s-witness", rigName)
+		sessionName := session.WitnessSessionName(rigName)
 		if downDryRun {
 			if sessionSet.Has(sessionName) {
 				printDownStatus(fmt.Sprintf("Witness (%s)", rigName), true, "would stop")
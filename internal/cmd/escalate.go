@@ -14,6 +14,7 @@ var (
 	escalateListJSON    bool
 	escalateListAll     bool
 	escalateStaleJSON   bool
+	escalateRoutesJSON  bool
 	escalateDryRun      bool
 	escalateCloseReason string
 )
@@ -55,7 +56,8 @@ Examples:
   hd escalate list                          # Show open escalations
   hd escalate ack hq-abc123                 # Acknowledge
   hd escalate close hq-abc123 --reason "Fixed in commit abc"
-  hd escalate stale                         # Re-escalate stale escalations`,
+  hd escalate stale                         # Re-escalate stale escalations
+  hd escalate routes                        # Show where each severity goes`,
 }
 
 var escalateListCmd = &cobra.Command{
@@ -135,6 +137,21 @@ Examples:
 	RunE: runEscalateShow,
 }
 
+var escalateRoutesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Show the resolved route for each severity",
+	Long: `Show the actions each severity level is routed to, from low to critical.
+
+Severities with no route in settings/escalation.json use the default route,
+and are marked as such. A severity whose route is empty is flagged, since
+escalations at that level would notify no one.
+
+Examples:
+  hd escalate routes
+  hd escalate routes --json`,
+	RunE: runEscalateRoutes,
+}
+
 func init() {
 	// Main escalate command flags
 	escalateCmd.Flags().StringVarP(&escalateSeverity, "severity", "s", "medium", "Severity level: critical, high, medium, low")
@@ -159,12 +176,16 @@ func init() {
 	// Show subcommand flags
 	escalateShowCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 
+	// Routes subcommand flags
+	escalateRoutesCmd.Flags().BoolVar(&escalateRoutesJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	escalateCmd.AddCommand(escalateListCmd)
 	escalateCmd.AddCommand(escalateAckCmd)
 	escalateCmd.AddCommand(escalateCloseCmd)
 	escalateCmd.AddCommand(escalateStaleCmd)
 	escalateCmd.AddCommand(escalateShowCmd)
+	escalateCmd.AddCommand(escalateRoutesCmd)
 
 	rootCmd.AddCommand(escalateCmd)
 }
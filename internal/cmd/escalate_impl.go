@@ -596,6 +596,39 @@ func formatEscalationMailBody(beadID, severity, reason, from, related string) st
 	return strings.Join(lines, "\n")
 }
 
+func runEscalateRoutes(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Horde workspace: %w", err)
+	}
+
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+	routes := escalationConfig.ResolveAllRoutes()
+
+	if escalateRoutesJSON {
+		out, _ := json.MarshalIndent(routes, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Println("Escalation routes:")
+	for _, severity := range config.ValidSeverities() {
+		actions := routes[severity]
+		line := strings.Join(actions, " → ")
+		if len(actions) == 0 {
+			line = style.Warning.Render("(empty: notifies no one)")
+		}
+		if _, configured := escalationConfig.Routes[severity]; !configured {
+			line += " " + style.Dim.Render("(default)")
+		}
+		fmt.Printf("  %s %-8s %s\n", severityEmoji(severity), severity, line)
+	}
+	return nil
+}
+
 func severityEmoji(severity string) string {
 	switch severity {
 	case config.SeverityCritical:
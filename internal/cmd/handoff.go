@@ -251,21 +251,21 @@ func resolveRoleToSession(role string) (string, error) {
 		if warband == "" || crewName == "" {
 			return "", fmt.Errorf("cannot determine clan identity - run from clan directory or specify HD_WARBAND/HD_CLAN")
 		}
-		return fmt.Sprintf("hd-%s-clan-%s", warband, crewName), nil
+		return session.CrewSessionName(warband, crewName), nil
 
 	case "witness", "wit":
 		warband := os.Getenv("HD_WARBAND")
 		if warband == "" {
 			return "", fmt.Errorf("cannot determine warband - set HD_WARBAND or run from warband context")
 		}
-		return fmt.Sprintf("hd-%s-witness", warband), nil
+		return session.WitnessSessionName(warband), nil
 
 	case "forge", "ref":
 		warband := os.Getenv("HD_WARBAND")
 		if warband == "" {
 			return "", fmt.Errorf("cannot determine warband - set HD_WARBAND or run from warband context")
 		}
-		return fmt.Sprintf("hd-%s-forge", warband), nil
+		return session.ForgeSessionName(warband), nil
 
 	default:
 		// Assume it's a direct session name (e.g., gt-horde-clan-max)
@@ -287,14 +287,14 @@ func resolvePathToSession(path string) (string, error) {
 	if len(parts) == 3 && parts[1] == "clan" {
 		warband := parts[0]
 		name := parts[2]
-		return fmt.Sprintf("hd-%s-clan-%s", warband, name), nil
+		return session.CrewSessionName(warband, name), nil
 	}
 
 	// Handle <warband>/raiders/<name> format (explicit raider path)
 	if len(parts) == 3 && parts[1] == "raiders" {
 		warband := parts[0]
 		name := strings.ToLower(parts[2]) // normalize raider name
-		return fmt.Sprintf("hd-%s-%s", warband, name), nil
+		return session.RaiderSessionName(warband, name), nil
 	}
 
 	// Handle <warband>/<role-or-raider> format
@@ -306,9 +306,9 @@ func resolvePathToSession(path string) (string, error) {
 		// Check for known roles first
 		switch secondLower {
 		case "witness":
-			return fmt.Sprintf("hd-%s-witness", warband), nil
+			return session.WitnessSessionName(warband), nil
 		case "forge":
-			return fmt.Sprintf("hd-%s-forge", warband), nil
+			return session.ForgeSessionName(warband), nil
 		case "clan":
 			// Just "<warband>/clan" without a name - need more info
 			return "", fmt.Errorf("clan path requires name: %s/clan/<name>", warband)
@@ -323,11 +323,11 @@ func resolvePathToSession(path string) (string, error) {
 			if townRoot != "" {
 				crewPath := filepath.Join(townRoot, warband, "clan", second)
 				if info, err := os.Stat(crewPath); err == nil && info.IsDir() {
-					return fmt.Sprintf("hd-%s-clan-%s", warband, second), nil
+					return session.CrewSessionName(warband, second), nil
 				}
 			}
 			// Not a clan member - treat as raider name (e.g., horde/nux)
-			return fmt.Sprintf("hd-%s-%s", warband, secondLower), nil
+			return session.RaiderSessionName(warband, secondLower), nil
 		}
 	}
 
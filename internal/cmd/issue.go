@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/tmux"
 )
 
@@ -113,10 +114,10 @@ func detectCurrentSession() string {
 
 	if warband != "" {
 		if raider != "" {
-			return fmt.Sprintf("hd-%s-%s", warband, raider)
+			return session.RaiderSessionName(warband, raider)
 		}
 		if clan != "" {
-			return fmt.Sprintf("hd-%s-clan-%s", warband, clan)
+			return session.CrewSessionName(warband, clan)
 		}
 	}
 
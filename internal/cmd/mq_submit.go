@@ -139,6 +139,12 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Reject malformed MR fields before they reach the queue
+	mrFields := &relics.MRFields{Branch: branch, Target: target, SourceIssue: issueID}
+	if err := mrFields.Validate(); err != nil {
+		return fmt.Errorf("invalid merge request: %w", err)
+	}
+
 	// Build MR bead title and description
 	title := fmt.Sprintf("Merge: %s", issueID)
 	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
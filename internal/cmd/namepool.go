@@ -276,26 +276,15 @@ func detectCurrentRigWithPath() (string, string) {
 func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 
-	// Load existing settings or create new
-	var settings *config.RigSettings
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err != nil {
-		// Create new settings if not found
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
+	// Update existing settings or create new (creates directory if needed)
+	err := config.UpdateRigSettings(settingsPath, func(settings *config.RigSettings) error {
+		settings.Namepool = &config.NamepoolConfig{
+			Style: theme,
+			Names: customNames,
 		}
-	}
-
-	// Set namepool
-	settings.Namepool = &config.NamepoolConfig{
-		Style: theme,
-		Names: customNames,
-	}
-
-	// Save (creates directory if needed)
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving settings: %w", err)
 	}
 
@@ -117,7 +117,7 @@ func SpawnRaiderForSling(rigName string, opts SlingSpawnOptions) (*SpawnedRaider
 
 	// Resolve account for runtime config
 	accountsPath := constants.WarchiefAccountsPath(townRoot)
-	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, opts.Account)
+	claudeConfigDir, accountHandle, err := config.ResolveRigAccountConfigDir(accountsPath, r.Path, opts.Account)
 	if err != nil {
 		return nil, fmt.Errorf("resolving account: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/forge"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/warband"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
@@ -487,7 +488,7 @@ func runForgeAttach(cmd *cobra.Command, args []string) error {
 	}
 
 	// Session name follows the same pattern as forge manager
-	sessionID := fmt.Sprintf("hd-%s-forge", rigName)
+	sessionID := session.ForgeSessionName(rigName)
 
 	// Check if session exists
 	t := tmux.NewTmux()
@@ -2,9 +2,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/deeklead/horde/internal/raider"
 	"github.com/deeklead/horde/internal/forge"
 	"github.com/deeklead/horde/internal/warband"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/wisp"
@@ -60,6 +63,9 @@ The command also:
   - Creates ~/horde/plugins/ (encampment-level) if it doesn't exist
   - Creates <warband>/plugins/ (warband-level)
 
+If a previous 'hd warband add' failed partway, re-running the same command
+resumes it: existing clones are kept and only the missing steps are run.
+
 Example:
   hd warband add horde https://github.com/deeklead/horde
   hd warband add my-project git@github.com:user/repo.git --prefix mp`,
@@ -105,11 +111,14 @@ This is the inverse of 'hd warband shutdown'. It starts:
 - The witness (if not already running)
 - The forge (if not already running)
 
-Raiders are NOT started by this command - they are spawned
-on demand when work is assigned.
+Raiders are normally spawned on demand when work is assigned.
+For latency-sensitive runs, --raiders N pre-spawns N idle raiders
+(names drawn from the namepool) so work can be charged to them
+without waiting for a worktree and session to come up.
 
 Examples:
-  hd warband boot greenplace`,
+  hd warband boot greenplace
+  hd warband boot greenplace --raiders 3`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRigBoot,
 }
@@ -182,6 +191,7 @@ var rigStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a specific warband including all workers.
 
 If no warband is specified, infers the warband from the current directory.
+Use --all for a compact overview of every registered warband.
 
 Displays:
 - Warband information (name, path, relics prefix)
@@ -190,10 +200,16 @@ Displays:
 - Raiders (name, state, assigned issue, session status)
 - Clan members (name, branch, session status, git status)
 
+Use --json for the same detail in machine-readable form; with --all it
+prints a JSON array with one entry per warband.
+
 Examples:
   hd warband status           # Infer warband from current directory
   hd warband status horde
-  hd warband status relics`,
+  hd warband status relics
+  hd warband status horde --json
+  hd warband status --all     # Compact status for every warband
+  hd warband status --all --json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRigStatus,
 }
@@ -267,6 +283,9 @@ var (
 	rigStopNuclear     bool
 	rigRestartForce    bool
 	rigRestartNuclear  bool
+	rigStatusAll       bool
+	rigStatusJSON      bool
+	rigBootRaiders     int
 )
 
 func init() {
@@ -283,6 +302,8 @@ func init() {
 	rigCmd.AddCommand(rigStatusCmd)
 	rigCmd.AddCommand(rigStopCmd)
 
+	rigBootCmd.Flags().IntVar(&rigBootRaiders, "raiders", 0, "Pre-spawn N idle raiders (warm pool)")
+
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Relics issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
@@ -303,6 +324,9 @@ func init() {
 
 	rigRestartCmd.Flags().BoolVarP(&rigRestartForce, "force", "f", false, "Force immediate shutdown during restart")
 	rigRestartCmd.Flags().BoolVar(&rigRestartNuclear, "nuclear", false, "DANGER: Bypass ALL safety checks (loses uncommitted work!)")
+
+	rigStatusCmd.Flags().BoolVar(&rigStatusAll, "all", false, "Show compact status for every warband")
+	rigStatusCmd.Flags().BoolVar(&rigStatusJSON, "json", false, "Output as JSON")
 }
 
 func runRigAdd(cmd *cobra.Command, args []string) error {
@@ -343,8 +367,8 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 
 	startTime := time.Now()
 
-	// Add the warband
-	newRig, err := mgr.AddRig(warband.AddRigOptions{
+	// Add the warband (resumes a partially-created warband for the same repo)
+	newRig, report, err := mgr.AddRigWithReport(warband.AddRigOptions{
 		Name:          name,
 		GitURL:        gitURL,
 		RelicsPrefix:   rigAddPrefix,
@@ -368,22 +392,19 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	// "<warband>/.relics", while repos with tracked relics have their database at warchief/warband/.relics.
 	var relicsWorkDir string
 	if newRig.Config.Prefix != "" {
-		routePath := name
-		warchiefRigRelics := filepath.Join(townRoot, name, "warchief", "warband", ".relics")
-		if _, err := os.Stat(warchiefRigRelics); err == nil {
-			// Source repo has .relics/ tracked - route to warchief/warband
-			routePath = name + "/warchief/warband"
-			relicsWorkDir = filepath.Join(townRoot, name, "warchief", "warband")
-		} else {
-			relicsWorkDir = filepath.Join(townRoot, name)
-		}
+		var routePath string
+		routePath, relicsWorkDir = warband.RelicsLocation(townRoot, name)
 		route := relics.Route{
 			Prefix: newRig.Config.Prefix + "-",
 			Path:   routePath,
 		}
-		if err := relics.AppendRoute(townRoot, route); err != nil {
+		if relics.RouteExists(townRoot, route) {
+			report.Skip("route")
+		} else if err := relics.AppendRoute(townRoot, route); err != nil {
 			// Non-fatal: routing will still work, just not from encampment root
 			fmt.Printf("  %s Could not update routes.jsonl: %v\n", style.Warning.Render("!"), err)
+		} else {
+			report.Perform("route")
 		}
 	}
 
@@ -394,18 +415,32 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		fields := &relics.RigFields{
 			Repo:   gitURL,
 			Prefix: newRig.Config.Prefix,
-			State:  "active",
+			State:  relics.RigStateActive,
 		}
-		if _, err := bd.CreateRigBead(rigBeadID, name, fields); err != nil {
+		if _, err := bd.Show(rigBeadID); err == nil {
+			report.Skip("identity bead")
+		} else if _, err := bd.CreateRigBead(rigBeadID, name, fields); err != nil {
 			// Non-fatal: warband is functional without the identity bead
 			fmt.Printf("  %s Could not create warband identity bead: %v\n", style.Warning.Render("!"), err)
 		} else {
 			fmt.Printf("  Created warband identity bead: %s\n", rigBeadID)
+			report.Perform("identity bead")
 		}
 	}
 
 	elapsed := time.Since(startTime)
 
+	if report.Resumed {
+		fmt.Printf("\n%s Warband resumed in %.1fs\n", style.Success.Render("✓"), elapsed.Seconds())
+		if len(report.Performed) > 0 {
+			fmt.Printf("  Performed: %s\n", strings.Join(report.Performed, ", "))
+		}
+		if len(report.Skipped) > 0 {
+			fmt.Printf("  Skipped:   %s\n", style.Dim.Render(strings.Join(report.Skipped, ", ")+" (already present)"))
+		}
+		return nil
+	}
+
 	// Read default branch from warband config
 	defaultBranch := "main"
 	if rigCfg, err := warband.LoadRigConfig(filepath.Join(townRoot, name)); err == nil && rigCfg.DefaultBranch != "" {
@@ -612,6 +647,10 @@ func runResetStale(bd *relics.Relics, dryRun bool) error {
 	var resetCount, skippedCount int
 	var resetIssues []string
 
+	// Find the dead agents, checking each assignee's session only once
+	checked := make(map[string]bool)
+	stale := make(map[string]bool) // assignee -> session is gone
+	var deadAgents []string
 	for _, issue := range issues {
 		if issue.Assignee == "" {
 			continue // No assignee to check
@@ -623,14 +662,17 @@ func runResetStale(bd *relics.Relics, dryRun bool) error {
 			continue // Couldn't parse assignee
 		}
 
-		// Check if session exists
-		hasSession, err := t.HasSession(sessionName)
-		if err != nil {
-			// tmux error, skip this one
-			continue
+		if !checked[issue.Assignee] {
+			checked[issue.Assignee] = true
+			// Check if session exists; on a tmux error, leave the agent alone
+			if hasSession, err := t.HasSession(sessionName); err == nil && !hasSession {
+				stale[issue.Assignee] = true
+				if !isPersistent {
+					deadAgents = append(deadAgents, issue.Assignee)
+				}
+			}
 		}
-
-		if hasSession {
+		if !stale[issue.Assignee] {
 			continue // Session exists, not stale
 		}
 
@@ -643,30 +685,27 @@ func runResetStale(bd *relics.Relics, dryRun bool) error {
 					issue.Assignee,
 					style.Dim.Render("(persistent, skipped)"))
 			}
-			continue
 		}
+	}
 
-		// Session doesn't exist - this is stale
+	// Session doesn't exist - release all of the agent's work at once
+	for _, assignee := range deadAgents {
+		result, err := bd.ReleaseAgentWork(assignee, dryRun)
+		if err != nil {
+			fmt.Printf("  %s Failed to reset work for %s: %v\n",
+				style.Warning.Render("⚠"),
+				assignee, err)
+			continue
+		}
 		if dryRun {
-			fmt.Printf("  %s: %s (no session) → open\n",
-				style.Bold.Render(issue.ID),
-				issue.Assignee)
-		} else {
-			// Reset status to open and clear assignee
-			openStatus := "open"
-			emptyAssignee := ""
-			if err := bd.Update(issue.ID, relics.UpdateOptions{
-				Status:   &openStatus,
-				Assignee: &emptyAssignee,
-			}); err != nil {
-				fmt.Printf("  %s Failed to reset %s: %v\n",
-					style.Warning.Render("⚠"),
-					issue.ID, err)
-				continue
+			for _, id := range result.Released {
+				fmt.Printf("  %s: %s (no session) → open\n",
+					style.Bold.Render(id),
+					assignee)
 			}
 		}
-		resetCount++
-		resetIssues = append(resetIssues, issue.ID)
+		resetCount += len(result.Released)
+		resetIssues = append(resetIssues, result.Released...)
 	}
 
 	if dryRun {
@@ -701,11 +740,11 @@ func assigneeToSessionName(assignee string) (sessionName string, isPersistent bo
 	switch len(parts) {
 	case 2:
 		// warband/raiderName -> gt-warband-raiderName
-		return fmt.Sprintf("hd-%s-%s", parts[0], parts[1]), false
+		return session.RaiderSessionName(parts[0], parts[1]), false
 	case 3:
 		// warband/clan/name -> gt-warband-clan-name
 		if parts[1] == "clan" {
-			return fmt.Sprintf("hd-%s-clan-%s", parts[0], parts[2]), true
+			return session.CrewSessionName(parts[0], parts[2]), true
 		}
 		// Other 3-part formats not recognized
 		return "", false
@@ -722,6 +761,9 @@ func pathExists(path string) bool {
 
 func runRigBoot(cmd *cobra.Command, args []string) error {
 	rigName := args[0]
+	if rigBootRaiders < 0 {
+		return fmt.Errorf("--raiders must be non-negative, got %d", rigBootRaiders)
+	}
 
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
@@ -752,7 +794,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 
 	// 1. Start the witness
 	// Check actual tmux session, not state file (may be stale)
-	witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
 	witnessRunning, _ := t.HasSession(witnessSession)
 	if witnessRunning {
 		skipped = append(skipped, "witness (already running)")
@@ -772,7 +814,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 
 	// 2. Start the forge
 	// Check actual tmux session, not state file (may be stale)
-	forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+	forgeSession := session.ForgeSessionName(rigName)
 	forgeRunning, _ := t.HasSession(forgeSession)
 	if forgeRunning {
 		skipped = append(skipped, "forge (already running)")
@@ -785,6 +827,21 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 		started = append(started, "forge")
 	}
 
+	// 3. Pre-spawn idle raiders (opt-in warm pool)
+	// Each gets a namepool name, a worktree, an agent bead in spawning
+	// state, and a session, exactly as charge would create on demand.
+	var warmed []string
+	var warmErr error
+	for i := 0; i < rigBootRaiders; i++ {
+		fmt.Printf("  Pre-spawning raider %d/%d...\n", i+1, rigBootRaiders)
+		info, err := SpawnRaiderForSling(rigName, SlingSpawnOptions{})
+		if err != nil {
+			warmErr = fmt.Errorf("pre-spawning raider %d/%d: %w", i+1, rigBootRaiders, err)
+			break
+		}
+		warmed = append(warmed, info.RaiderName)
+	}
+
 	// Report results
 	if len(started) > 0 {
 		fmt.Printf("%s Started: %s\n", style.Success.Render("✓"), strings.Join(started, ", "))
@@ -792,8 +849,11 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 	if len(skipped) > 0 {
 		fmt.Printf("%s Skipped: %s\n", style.Dim.Render("•"), strings.Join(skipped, ", "))
 	}
+	if len(warmed) > 0 {
+		fmt.Printf("%s Pre-warmed %d raider(s): %s\n", style.Success.Render("✓"), len(warmed), strings.Join(warmed, ", "))
+	}
 
-	return nil
+	return warmErr
 }
 
 func runRigStart(cmd *cobra.Command, args []string) error {
@@ -832,7 +892,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 		hasError := false
 
 		// 1. Start the witness
-		witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
+		witnessSession := session.WitnessSessionName(rigName)
 		witnessRunning, _ := t.HasSession(witnessSession)
 		if witnessRunning {
 			skipped = append(skipped, "witness")
@@ -852,7 +912,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 		}
 
 		// 2. Start the forge
-		forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+		forgeSession := session.ForgeSessionName(rigName)
 		forgeRunning, _ := t.HasSession(forgeSession)
 		if forgeRunning {
 			skipped = append(skipped, "forge")
@@ -1020,7 +1080,55 @@ func runRigReboot(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// RigStatusDetail is the detailed status of one warband, as shown by
+// hd warband status.
+type RigStatusDetail struct {
+	Name        string            `json:"name"`
+	Path        string            `json:"path"`
+	Prefix      string            `json:"prefix,omitempty"`
+	State       string            `json:"state"`                  // OPERATIONAL, PARKED, or DOCKED
+	StateSource string            `json:"state_source,omitempty"` // Where a non-operational state was set
+	Witness     RigServiceStatus  `json:"witness"`
+	Forge       RigServiceStatus  `json:"forge"`
+	Raiders     []RigRaiderStatus `json:"raiders"`
+	Clan        []RigCrewStatus   `json:"clan"`
+}
+
+// RigServiceStatus is the status of a warband's witness or forge.
+type RigServiceStatus struct {
+	Session       string     `json:"session"`
+	Running       bool       `json:"running"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`     // Set while running, if known
+	UptimeSeconds int64      `json:"uptime_seconds,omitempty"` // Since StartedAt
+	QueueSize     int        `json:"queue_size,omitempty"`     // Forge merge queue items
+}
+
+// RigRaiderStatus is the status of one raider in a warband.
+type RigRaiderStatus struct {
+	Name    string `json:"name"`
+	Session string `json:"session"`
+	Running bool   `json:"running"`
+	State   string `json:"state"`
+	Issue   string `json:"issue,omitempty"` // Assigned issue
+}
+
+// RigCrewStatus is the status of one clan member in a warband.
+type RigCrewStatus struct {
+	Name    string `json:"name"`
+	Session string `json:"session"`
+	Running bool   `json:"running"`
+	Branch  string `json:"branch,omitempty"`
+	Dirty   bool   `json:"dirty"` // Uncommitted changes in the clone
+}
+
 func runRigStatus(cmd *cobra.Command, args []string) error {
+	if rigStatusAll {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot combine --all with a warband name")
+		}
+		return runRigStatusAll()
+	}
+
 	var rigName string
 
 	if len(args) > 0 {
@@ -1043,123 +1151,268 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	status := gatherRigStatus(townRoot, r, tmux.NewTmux())
+	if rigStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	printRigStatus(status)
+	return nil
+}
 
-	// Header
-	fmt.Printf("%s\n", style.Bold.Render(rigName))
+// gatherRigStatus collects the detailed status of warband r.
+func gatherRigStatus(townRoot string, r *warband.Warband, t *tmux.Tmux) *RigStatusDetail {
+	rigName := r.Name
+	status := &RigStatusDetail{
+		Name:    rigName,
+		Path:    r.Path,
+		Raiders: []RigRaiderStatus{},
+		Clan:    []RigCrewStatus{},
+	}
+	status.State, status.StateSource = getRigOperationalState(townRoot, rigName)
+	if r.Config != nil {
+		status.Prefix = r.Config.Prefix
+	}
 
-	// Operational state
-	opState, opSource := getRigOperationalState(townRoot, rigName)
-	if opState == "OPERATIONAL" {
-		fmt.Printf("  Status: %s\n", style.Success.Render(opState))
-	} else if opState == "PARKED" {
-		fmt.Printf("  Status: %s (%s)\n", style.Warning.Render(opState), opSource)
-	} else if opState == "DOCKED" {
-		fmt.Printf("  Status: %s (%s)\n", style.Dim.Render(opState), opSource)
+	// Witness
+	status.Witness.Session = session.WitnessSessionName(rigName)
+	status.Witness.Running, _ = t.HasSession(status.Witness.Session)
+	if witStatus, _ := witness.NewManager(r).Status(); status.Witness.Running && witStatus != nil && witStatus.StartedAt != nil {
+		status.Witness.StartedAt = witStatus.StartedAt
+		status.Witness.UptimeSeconds = int64(time.Since(*witStatus.StartedAt).Seconds())
 	}
 
-	fmt.Printf("  Path: %s\n", r.Path)
-	if r.Config != nil && r.Config.Prefix != "" {
-		fmt.Printf("  Relics prefix: %s-\n", r.Config.Prefix)
+	// Forge
+	status.Forge.Session = session.ForgeSessionName(rigName)
+	status.Forge.Running, _ = t.HasSession(status.Forge.Session)
+	if status.Forge.Running {
+		refMgr := forge.NewManager(r)
+		if refStatus, _ := refMgr.Status(); refStatus != nil && refStatus.StartedAt != nil {
+			status.Forge.StartedAt = refStatus.StartedAt
+			status.Forge.UptimeSeconds = int64(time.Since(*refStatus.StartedAt).Seconds())
+		}
+		if queue, err := refMgr.Queue(); err == nil {
+			status.Forge.QueueSize = len(queue)
+		}
 	}
-	fmt.Println()
 
-	// Witness status
-	fmt.Printf("%s\n", style.Bold.Render("Witness"))
-	witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
-	witnessRunning, _ := t.HasSession(witnessSession)
-	witMgr := witness.NewManager(r)
-	witStatus, _ := witMgr.Status()
-	if witnessRunning {
-		fmt.Printf("  %s running", style.Success.Render("●"))
-		if witStatus != nil && witStatus.StartedAt != nil {
-			fmt.Printf(" (uptime: %s)", formatDuration(time.Since(*witStatus.StartedAt)))
+	// Raiders
+	raiderMgr := raider.NewManager(r, git.NewGit(r.Path), t)
+	if raiders, err := raiderMgr.List(); err == nil {
+		for _, p := range raiders {
+			sessionName := session.RaiderSessionName(rigName, p.Name)
+			running, _ := t.HasSession(sessionName)
+			status.Raiders = append(status.Raiders, RigRaiderStatus{
+				Name:    p.Name,
+				Session: sessionName,
+				Running: running,
+				State:   string(p.State),
+				Issue:   p.Issue,
+			})
 		}
-		fmt.Printf("\n")
-	} else {
-		fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
 	}
-	fmt.Println()
 
-	// Forge status
-	fmt.Printf("%s\n", style.Bold.Render("Forge"))
-	forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
-	forgeRunning, _ := t.HasSession(forgeSession)
-	refMgr := forge.NewManager(r)
-	refStatus, _ := refMgr.Status()
-	if forgeRunning {
-		fmt.Printf("  %s running", style.Success.Render("●"))
-		if refStatus != nil && refStatus.StartedAt != nil {
-			fmt.Printf(" (uptime: %s)", formatDuration(time.Since(*refStatus.StartedAt)))
+	// Clan
+	crewMgr := clan.NewManager(r, git.NewGit(townRoot))
+	if crewWorkers, err := crewMgr.List(); err == nil {
+		for _, w := range crewWorkers {
+			session := crewSessionName(rigName, w.Name)
+			running, _ := t.HasSession(session)
+
+			crewGit := git.NewGit(w.ClonePath)
+			branch, _ := crewGit.CurrentBranch()
+			gitStatus, _ := crewGit.Status()
+
+			status.Clan = append(status.Clan, RigCrewStatus{
+				Name:    w.Name,
+				Session: session,
+				Running: running,
+				Branch:  branch,
+				Dirty:   gitStatus != nil && !gitStatus.Clean,
+			})
 		}
-		fmt.Printf("\n")
-		// Show queue size
-		queue, err := refMgr.Queue()
-		if err == nil && len(queue) > 0 {
-			fmt.Printf("  Queue: %d items\n", len(queue))
+	}
+
+	return status
+}
+
+// printRigStatus prints the human-readable view of a warband's status.
+func printRigStatus(status *RigStatusDetail) {
+	sessionIcon := func(running bool) string {
+		if running {
+			return style.Success.Render("●")
 		}
-	} else {
-		fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
+		return style.Dim.Render("○")
+	}
+	printService := func(title string, svc RigServiceStatus) {
+		fmt.Printf("%s\n", style.Bold.Render(title))
+		if !svc.Running {
+			fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
+		} else {
+			fmt.Printf("  %s running", style.Success.Render("●"))
+			if svc.StartedAt != nil {
+				fmt.Printf(" (uptime: %s)", formatDuration(time.Since(*svc.StartedAt)))
+			}
+			fmt.Printf("\n")
+			if svc.QueueSize > 0 {
+				fmt.Printf("  Queue: %d items\n", svc.QueueSize)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Header
+	fmt.Printf("%s\n", style.Bold.Render(status.Name))
+
+	// Operational state
+	if status.State == "OPERATIONAL" {
+		fmt.Printf("  Status: %s\n", style.Success.Render(status.State))
+	} else if status.State == "PARKED" {
+		fmt.Printf("  Status: %s (%s)\n", style.Warning.Render(status.State), status.StateSource)
+	} else if status.State == "DOCKED" {
+		fmt.Printf("  Status: %s (%s)\n", style.Dim.Render(status.State), status.StateSource)
+	}
+
+	fmt.Printf("  Path: %s\n", status.Path)
+	if status.Prefix != "" {
+		fmt.Printf("  Relics prefix: %s-\n", status.Prefix)
 	}
 	fmt.Println()
 
+	printService("Witness", status.Witness)
+	printService("Forge", status.Forge)
+
 	// Raiders
-	raiderGit := git.NewGit(r.Path)
-	raiderMgr := raider.NewManager(r, raiderGit, t)
-	raiders, err := raiderMgr.List()
 	fmt.Printf("%s", style.Bold.Render("Raiders"))
-	if err != nil || len(raiders) == 0 {
+	if len(status.Raiders) == 0 {
 		fmt.Printf(" (none)\n")
 	} else {
-		fmt.Printf(" (%d)\n", len(raiders))
-		for _, p := range raiders {
-			sessionName := fmt.Sprintf("hd-%s-%s", rigName, p.Name)
-			hasSession, _ := t.HasSession(sessionName)
-
-			sessionIcon := style.Dim.Render("○")
-			if hasSession {
-				sessionIcon = style.Success.Render("●")
-			}
-
-			stateStr := string(p.State)
+		fmt.Printf(" (%d)\n", len(status.Raiders))
+		for _, p := range status.Raiders {
+			stateStr := p.State
 			if p.Issue != "" {
 				stateStr = fmt.Sprintf("%s → %s", p.State, p.Issue)
 			}
-
-			fmt.Printf("  %s %s: %s\n", sessionIcon, p.Name, stateStr)
+			fmt.Printf("  %s %s: %s\n", sessionIcon(p.Running), p.Name, stateStr)
 		}
 	}
 	fmt.Println()
 
 	// Clan
-	crewMgr := clan.NewManager(r, git.NewGit(townRoot))
-	crewWorkers, err := crewMgr.List()
 	fmt.Printf("%s", style.Bold.Render("Clan"))
-	if err != nil || len(crewWorkers) == 0 {
+	if len(status.Clan) == 0 {
 		fmt.Printf(" (none)\n")
 	} else {
-		fmt.Printf(" (%d)\n", len(crewWorkers))
-		for _, w := range crewWorkers {
-			sessionName := crewSessionName(rigName, w.Name)
-			hasSession, _ := t.HasSession(sessionName)
+		fmt.Printf(" (%d)\n", len(status.Clan))
+		for _, w := range status.Clan {
+			gitInfo := ""
+			if w.Dirty {
+				gitInfo = style.Warning.Render(" (dirty)")
+			}
+			fmt.Printf("  %s %s: %s%s\n", sessionIcon(w.Running), w.Name, w.Branch, gitInfo)
+		}
+	}
+}
 
-			sessionIcon := style.Dim.Render("○")
-			if hasSession {
-				sessionIcon = style.Success.Render("●")
+// runRigStatusAll prints a compact status block for every registered warband.
+// Tmux sessions are listed once up front rather than probed per agent.
+func runRigStatusAll() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Horde workspace: %w", err)
+	}
+
+	rigsPath := filepath.Join(townRoot, "warchief", "warbands.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil || len(rigsConfig.Warbands) == 0 {
+		if rigStatusJSON {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Println("No warbands configured.")
+		return nil
+	}
+
+	names := make([]string, 0, len(rigsConfig.Warbands))
+	for name := range rigsConfig.Warbands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mgr := warband.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	if rigStatusJSON {
+		// Full per-warband detail, as for a single warband's --json
+		t := tmux.NewTmux()
+		statuses := []*RigStatusDetail{}
+		for _, name := range names {
+			r, err := mgr.GetRig(name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping warband %s: %v\n", name, err)
+				continue
 			}
+			statuses = append(statuses, gatherRigStatus(townRoot, r, t))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
 
-			// Get git info
-			crewGit := git.NewGit(w.ClonePath)
-			branch, _ := crewGit.CurrentBranch()
-			gitStatus, _ := crewGit.Status()
+	sessions := make(map[string]bool)
+	if list, err := tmux.NewTmux().ListSessions(); err == nil {
+		for _, s := range list {
+			sessions[s] = true
+		}
+	}
 
-			gitInfo := ""
-			if gitStatus != nil && !gitStatus.Clean {
-				gitInfo = style.Warning.Render(" (dirty)")
+	upDown := func(running bool) string {
+		if running {
+			return style.Success.Render("●") + " up"
+		}
+		return style.Dim.Render("○") + " down"
+	}
+
+	for _, name := range names {
+		r, err := mgr.GetRig(name)
+		if err != nil {
+			fmt.Printf("%s %s\n\n", style.Warning.Render("!"), name)
+			continue
+		}
+
+		opState, opSource := getRigOperationalState(townRoot, name)
+		switch opState {
+		case "OPERATIONAL":
+			fmt.Printf("%s  %s\n", style.Bold.Render(name), style.Success.Render(opState))
+		case "PARKED":
+			fmt.Printf("%s  %s (%s)\n", style.Bold.Render(name), style.Warning.Render(opState), opSource)
+		default:
+			fmt.Printf("%s  %s (%s)\n", style.Bold.Render(name), style.Dim.Render(opState), opSource)
+		}
+
+		forgeRunning := sessions[session.ForgeSessionName(name)]
+		fmt.Printf("  Witness: %s  Forge: %s", upDown(sessions[session.WitnessSessionName(name)]), upDown(forgeRunning))
+		if forgeRunning {
+			if queue, err := forge.NewManager(r).Queue(); err == nil {
+				fmt.Printf("  Queue: %d", len(queue))
 			}
+		}
+		fmt.Println()
 
-			fmt.Printf("  %s %s: %s%s\n", sessionIcon, w.Name, branch, gitInfo)
+		summary := r.Summary()
+		raidersActive := 0
+		for _, p := range r.Raiders {
+			if sessions[session.RaiderSessionName(name, p)] {
+				raidersActive++
+			}
 		}
+		crewActive := 0
+		for _, c := range r.Clan {
+			if sessions[crewSessionName(name, c)] {
+				crewActive++
+			}
+		}
+		fmt.Printf("  Raiders: %d (%d active)  Clan: %d (%d active)\n\n",
+			summary.RaiderCount, raidersActive, summary.CrewCount, crewActive)
 	}
 
 	return nil
@@ -1412,7 +1665,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		var skipped []string
 
 		// 1. Start the witness
-		witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
+		witnessSession := session.WitnessSessionName(rigName)
 		witnessRunning, _ := t.HasSession(witnessSession)
 		if witnessRunning {
 			skipped = append(skipped, "witness")
@@ -1431,7 +1684,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		}
 
 		// 2. Start the forge
-		forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+		forgeSession := session.ForgeSessionName(rigName)
 		forgeRunning, _ := t.HasSession(forgeSession)
 		if forgeRunning {
 			skipped = append(skipped, "forge")
@@ -1485,7 +1738,8 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 }
 
 // getRigOperationalState returns the operational state and source for a warband.
-// It checks the wisp layer first (local/ephemeral), then warband bead labels (global).
+// It checks the wisp layer first (local/ephemeral), then the warband identity bead's
+// status labels and state field (global).
 // Returns state ("OPERATIONAL", "PARKED", or "DOCKED") and source ("local", "global - synced", or "default").
 func getRigOperationalState(townRoot, rigName string) (state string, source string) {
 	// Check wisp layer first (local/ephemeral overrides)
@@ -1499,9 +1753,8 @@ func getRigOperationalState(townRoot, rigName string) (state string, source stri
 		}
 	}
 
-	// Check warband bead labels (global/synced)
+	// Check warband identity bead state (global/synced)
 	// Warband identity bead ID: <prefix>-warband-<name>
-	// Look for status:docked or status:parked labels
 	rigPath := filepath.Join(townRoot, rigName)
 	rigRelicsDir := relics.ResolveRelicsDir(rigPath)
 	bd := relics.NewWithRelicsDir(rigPath, rigRelicsDir)
@@ -1511,16 +1764,11 @@ func getRigOperationalState(townRoot, rigName string) (state string, source stri
 	if rigCfg, err := warband.LoadRigConfig(rigPath); err == nil && rigCfg.Relics != nil {
 		rigBeadID := fmt.Sprintf("%s-warband-%s", rigCfg.Relics.Prefix, rigName)
 		if issue, err := bd.Show(rigBeadID); err == nil {
-			for _, label := range issue.Labels {
-				if strings.HasPrefix(label, "status:") {
-					statusValue := strings.TrimPrefix(label, "status:")
-					switch strings.ToLower(statusValue) {
-					case "docked":
-						return "DOCKED", "global - synced"
-					case "parked":
-						return "PARKED", "global - synced"
-					}
-				}
+			switch relics.RigBeadState(issue) {
+			case relics.RigStateDocked:
+				return "DOCKED", "global - synced"
+			case relics.RigStateParked:
+				return "PARKED", "global - synced"
 			}
 		}
 	}
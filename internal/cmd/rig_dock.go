@@ -7,14 +7,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/forge"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/witness"
 )
 
-// RigDockedLabel is the label set on warband identity relics when docked.
-const RigDockedLabel = "status:docked"
-
 var rigDockCmd = &cobra.Command{
 	Use:   "dock <warband>",
 	Short: "Dock a warband (global, persistent shutdown)",
@@ -23,7 +21,7 @@ var rigDockCmd = &cobra.Command{
 Docking a warband:
   - Stops the witness if running
   - Stops the forge if running
-  - Sets status:docked label on the warband identity bead
+  - Sets state: docked and the status:docked label on the warband identity bead
   - Syncs via git so all clones see the docked status
 
 This is a Level 2 (global/persistent) operation:
@@ -46,7 +44,7 @@ var rigUndockCmd = &cobra.Command{
 	Long: `Undock a warband to remove the persistent docked status.
 
 Undocking a warband:
-  - Removes the status:docked label from the warband identity bead
+  - Sets state: active and removes the status:docked label from the warband identity bead
   - Syncs via git so all clones see the undocked status
   - Allows the daemon to auto-restart agents
   - Does NOT automatically start agents (use 'hd warband start' for that)
@@ -90,7 +88,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 		rigBead, err = bd.CreateRigBead(rigBeadID, rigName, &relics.RigFields{
 			Repo:   r.GitURL,
 			Prefix: prefix,
-			State:  "active",
+			State:  relics.RigStateActive,
 		})
 		if err != nil {
 			return fmt.Errorf("creating warband identity bead: %w", err)
@@ -98,11 +96,9 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if already docked
-	for _, label := range rigBead.Labels {
-		if label == RigDockedLabel {
-			fmt.Printf("%s Warband %s is already docked\n", style.Dim.Render("•"), rigName)
-			return nil
-		}
+	if relics.RigBeadState(rigBead) == relics.RigStateDocked {
+		fmt.Printf("%s Warband %s is already docked\n", style.Dim.Render("•"), rigName)
+		return nil
 	}
 
 	fmt.Printf("Docking warband %s...\n", style.Bold.Render(rigName))
@@ -112,7 +108,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 	t := tmux.NewTmux()
 
 	// Stop witness if running
-	witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
 	witnessRunning, _ := t.HasSession(witnessSession)
 	if witnessRunning {
 		fmt.Printf("  Stopping witness...\n")
@@ -125,7 +121,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 	}
 
 	// Stop forge if running
-	forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+	forgeSession := session.ForgeSessionName(rigName)
 	forgeRunning, _ := t.HasSession(forgeSession)
 	if forgeRunning {
 		fmt.Printf("  Stopping forge...\n")
@@ -137,24 +133,20 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Set docked label on warband identity bead
-	if err := bd.Update(rigBeadID, relics.UpdateOptions{
-		AddLabels: []string{RigDockedLabel},
-	}); err != nil {
-		return fmt.Errorf("setting docked label: %w", err)
+	// Record docked state on warband identity bead
+	if err := bd.SetRigState(rigBeadID, relics.RigStateDocked); err != nil {
+		return fmt.Errorf("setting docked state: %w", err)
 	}
 
 	// Sync relics to propagate to other clones
 	fmt.Printf("  Syncing relics...\n")
-	syncCmd := exec.Command("rl", "sync")
-	syncCmd.Dir = r.RelicsPath()
-	if output, err := syncCmd.CombinedOutput(); err != nil {
-		fmt.Printf("  %s rl sync warning: %v\n%s", style.Warning.Render("!"), err, string(output))
+	if err := bd.Sync(relics.SyncOptions{}); err != nil {
+		fmt.Printf("  %s rl sync warning: %v\n", style.Warning.Render("!"), err)
 	}
 
 	// Output
 	fmt.Printf("%s Warband %s docked (global)\n", style.Success.Render("✓"), rigName)
-	fmt.Printf("  Label added: %s\n", RigDockedLabel)
+	fmt.Printf("  State set: %s\n", relics.RigStateDocked)
 	for _, msg := range stoppedAgents {
 		fmt.Printf("  %s\n", msg)
 	}
@@ -191,43 +183,32 @@ func runRigUndock(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if actually docked
-	isDocked := false
-	for _, label := range rigBead.Labels {
-		if label == RigDockedLabel {
-			isDocked = true
-			break
-		}
-	}
-	if !isDocked {
+	if relics.RigBeadState(rigBead) != relics.RigStateDocked {
 		fmt.Printf("%s Warband %s is not docked\n", style.Dim.Render("•"), rigName)
 		return nil
 	}
 
-	// Remove docked label from warband identity bead
-	if err := bd.Update(rigBeadID, relics.UpdateOptions{
-		RemoveLabels: []string{RigDockedLabel},
-	}); err != nil {
-		return fmt.Errorf("removing docked label: %w", err)
+	// Return warband identity bead to active (also drops the docked label)
+	if err := bd.SetRigState(rigBeadID, relics.RigStateActive); err != nil {
+		return fmt.Errorf("clearing docked state: %w", err)
 	}
 
 	// Sync relics to propagate to other clones
 	fmt.Printf("  Syncing relics...\n")
-	syncCmd := exec.Command("rl", "sync")
-	syncCmd.Dir = r.RelicsPath()
-	if output, err := syncCmd.CombinedOutput(); err != nil {
-		fmt.Printf("  %s rl sync warning: %v\n%s", style.Warning.Render("!"), err, string(output))
+	if err := bd.Sync(relics.SyncOptions{}); err != nil {
+		fmt.Printf("  %s rl sync warning: %v\n", style.Warning.Render("!"), err)
 	}
 
 	fmt.Printf("%s Warband %s undocked\n", style.Success.Render("✓"), rigName)
-	fmt.Printf("  Label removed: %s\n", RigDockedLabel)
+	fmt.Printf("  State set: %s\n", relics.RigStateActive)
 	fmt.Printf("  Daemon can now auto-restart agents\n")
 	fmt.Printf("  Use '%s' to start agents immediately\n", style.Dim.Render("hd warband start "+rigName))
 
 	return nil
 }
 
-// IsRigDocked checks if a warband is docked by checking for the status:docked label
-// on the warband identity bead. This function is exported for use by the daemon.
+// IsRigDocked checks if a warband is docked by checking the state recorded on
+// the warband identity bead. This function is exported for use by the daemon.
 func IsRigDocked(townRoot, rigName, prefix string) bool {
 	// Construct the warband relics path
 	rigPath := townRoot + "/" + rigName
@@ -244,10 +225,5 @@ func IsRigDocked(townRoot, rigName, prefix string) bool {
 		return false
 	}
 
-	for _, label := range rigBead.Labels {
-		if label == RigDockedLabel {
-			return true
-		}
-	}
-	return false
+	return relics.RigBeadState(rigBead) == relics.RigStateDocked
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/deeklead/horde/internal/config"
+	"github.com/deeklead/horde/internal/style"
+	"github.com/deeklead/horde/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var rigExplainAgentJSON bool
+
+var rigExplainAgentCmd = &cobra.Command{
+	Use:   "explain-agent <role> [warband]",
+	Short: "Explain how the agent for a role is resolved",
+	Long: `Show every configuration source consulted when resolving the agent for a role,
+which one won, and the final command that will be started.
+
+Sources are consulted in this order:
+  1. Warband role_agents[<role>]
+  2. Encampment role_agents[<role>]
+  3. Warband runtime (legacy, set directly)
+  4. Warband agent
+  5. Encampment default_agent
+  6. Built-in default (claude)
+
+Omit the warband for encampment-level roles (warchief, shaman).
+
+Examples:
+  hd warband explain-agent raider horde
+  hd warband explain-agent warchief
+  hd warband explain-agent witness horde --json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRigExplainAgent,
+}
+
+func init() {
+	rigCmd.AddCommand(rigExplainAgentCmd)
+	rigExplainAgentCmd.Flags().BoolVar(&rigExplainAgentJSON, "json", false, "Output as JSON")
+}
+
+func runRigExplainAgent(cmd *cobra.Command, args []string) error {
+	role := args[0]
+
+	var townRoot, rigPath string
+	if len(args) > 1 {
+		root, r, err := getRig(args[1])
+		if err != nil {
+			return err
+		}
+		townRoot, rigPath = root, r.Path
+	} else {
+		root, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Horde workspace: %w", err)
+		}
+		townRoot = root
+	}
+
+	trace := config.ExplainAgentResolution(role, townRoot, rigPath)
+
+	if rigExplainAgentJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trace)
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Agent resolution for role:"), role)
+	for i, step := range trace.Steps {
+		value := step.Value
+		if value == "" {
+			value = "-"
+		}
+		result := step.Result
+		switch {
+		case result == "selected":
+			result = style.Success.Render("✓ selected")
+		case strings.HasPrefix(result, "skipped"):
+			result = style.Warning.Render(result)
+		default:
+			result = style.Dim.Render(result)
+		}
+		fmt.Printf("  %d. %-32s %-20s %s\n", i+1, step.Source, value, result)
+	}
+
+	fmt.Println()
+	if trace.AgentName != "" {
+		fmt.Printf("  Agent:      %s (from %s)\n", trace.AgentName, trace.DefinedIn)
+	} else {
+		fmt.Printf("  Agent:      (%s)\n", trace.DefinedIn)
+	}
+	if trace.Runtime != nil {
+		fmt.Printf("  Command:    %s\n", strings.TrimSpace(trace.Runtime.Command+" "+strings.Join(trace.Runtime.Args, " ")))
+	}
+	if c := trace.Conflict; c != nil {
+		fmt.Printf("\n  %s warband role_agents[%s]=%s shadows encampment role_agents[%s]=%s\n",
+			style.Warning.Render("⚠"), c.Role, c.RigAgent, c.Role, c.TownAgent)
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -677,3 +678,92 @@ func TestRigAddRejectsInvalidNames(t *testing.T) {
 		})
 	}
 }
+
+// TestRigAddResumesPartialRig verifies that re-running AddRig over a
+// partially-created warband keeps existing clones and redoes missing steps.
+func TestRigAddResumesPartialRig(t *testing.T) {
+	mockBdCommand(t)
+	townRoot := setupTestTown(t)
+	gitURL := createTestGitRepo(t, "resumetest")
+
+	rigsPath := filepath.Join(townRoot, "warchief", "warbands.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		t.Fatalf("load warbands.json: %v", err)
+	}
+
+	g := git.NewGit(townRoot)
+	mgr := warband.NewManager(townRoot, rigsConfig, g)
+
+	opts := warband.AddRigOptions{
+		Name:         "resumetest",
+		GitURL:       gitURL,
+		RelicsPrefix: "rt",
+	}
+	if _, err := mgr.AddRig(opts); err != nil {
+		t.Fatalf("AddRig: %v", err)
+	}
+
+	// Simulate an interrupted run: forge worktree missing, warband not registered
+	rigPath := filepath.Join(townRoot, "resumetest")
+	if err := os.RemoveAll(filepath.Join(rigPath, "forge", "warband")); err != nil {
+		t.Fatalf("remove forge worktree: %v", err)
+	}
+	delete(rigsConfig.Warbands, "resumetest")
+
+	_, report, err := mgr.AddRigWithReport(opts)
+	if err != nil {
+		t.Fatalf("AddRigWithReport (resume): %v", err)
+	}
+	if !report.Resumed {
+		t.Error("report.Resumed = false, want true")
+	}
+	for _, step := range []string{"config", "bare repo", "warchief clone"} {
+		if !slices.Contains(report.Skipped, step) {
+			t.Errorf("step %q not skipped; skipped = %v", step, report.Skipped)
+		}
+	}
+	for _, step := range []string{"forge worktree", "registration"} {
+		if !slices.Contains(report.Performed, step) {
+			t.Errorf("step %q not performed; performed = %v", step, report.Performed)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "forge", "warband", ".git")); err != nil {
+		t.Errorf("forge worktree not recreated: %v", err)
+	}
+	if !mgr.RigExists("resumetest") {
+		t.Error("warband not registered after resume")
+	}
+}
+
+// TestRigAddRejectsForeignDirectory verifies that AddRig does not adopt an
+// existing directory that isn't a warband for the same repository.
+func TestRigAddRejectsForeignDirectory(t *testing.T) {
+	mockBdCommand(t)
+	townRoot := setupTestTown(t)
+	gitURL := createTestGitRepo(t, "foreign")
+
+	rigsPath := filepath.Join(townRoot, "warchief", "warbands.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		t.Fatalf("load warbands.json: %v", err)
+	}
+
+	rigPath := filepath.Join(townRoot, "foreign")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	keep := filepath.Join(rigPath, "notes.txt")
+	if err := os.WriteFile(keep, []byte("mine"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	mgr := warband.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	_, err = mgr.AddRig(warband.AddRigOptions{Name: "foreign", GitURL: gitURL})
+	if err == nil || !strings.Contains(err.Error(), "directory already exists") {
+		t.Fatalf("AddRig error = %v, want 'directory already exists'", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("existing directory contents were touched: %v", err)
+	}
+}
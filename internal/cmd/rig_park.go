@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/deeklead/horde/internal/forge"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/wisp"
@@ -95,7 +96,7 @@ func parkOneRig(rigName string) error {
 	t := tmux.NewTmux()
 
 	// Stop witness if running
-	witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
 	witnessRunning, _ := t.HasSession(witnessSession)
 	if witnessRunning {
 		fmt.Printf("  Stopping witness...\n")
@@ -108,7 +109,7 @@ func parkOneRig(rigName string) error {
 	}
 
 	// Stop forge if running
-	forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+	forgeSession := session.ForgeSessionName(rigName)
 	forgeRunning, _ := t.HasSession(forgeSession)
 	if forgeRunning {
 		fmt.Printf("  Stopping forge...\n")
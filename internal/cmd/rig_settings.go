@@ -0,0 +1,95 @@
+// Package cmd provides CLI commands for the hd tool.
+// This file implements the hd warband settings commands for maintaining
+// a warband's settings/config.json file.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/config"
+	"github.com/deeklead/horde/internal/style"
+)
+
+var rigSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Maintain warband settings files",
+	RunE:  requireSubcommand,
+}
+
+var rigSettingsMigrateCmd = &cobra.Command{
+	Use:   "migrate <warband>",
+	Short: "Upgrade a warband's settings file to the current schema version",
+	Long: `Upgrade a warband's settings/config.json to the current schema version.
+
+Loads the settings, applies any pending version migrations, shows what
+changed, and writes the upgraded file. Use --dry-run to preview the
+changes without writing.
+
+Examples:
+  hd warband settings migrate horde --dry-run
+  hd warband settings migrate horde`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigSettingsMigrate,
+}
+
+var rigSettingsMigrateDryRun bool
+
+func init() {
+	rigCmd.AddCommand(rigSettingsCmd)
+	rigSettingsCmd.AddCommand(rigSettingsMigrateCmd)
+
+	rigSettingsMigrateCmd.Flags().BoolVar(&rigSettingsMigrateDryRun, "dry-run", false, "Show changes without writing")
+}
+
+func runRigSettingsMigrate(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	path := config.RigSettingsPath(r.Path)
+	settings, err := config.LoadRigSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading warband settings: %w", err)
+	}
+
+	migrated, changed, err := config.MigrateRigSettings(settings)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("%s %s settings already at version %d\n", style.Success.Render("✓"), rigName, settings.Version)
+		return nil
+	}
+
+	changes, err := config.DiffRigSettings(settings, migrated)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrating %s settings: version %d → %d\n", style.Bold.Render(rigName), settings.Version, migrated.Version)
+	for _, c := range changes {
+		oldVal, newVal := c.Old, c.New
+		if oldVal == "" {
+			oldVal = style.Dim.Render("(unset)")
+		}
+		if newVal == "" {
+			newVal = style.Dim.Render("(unset)")
+		}
+		fmt.Printf("  %s: %s → %s\n", c.Path, oldVal, newVal)
+	}
+
+	if rigSettingsMigrateDryRun {
+		fmt.Printf("\n%s\n", style.Dim.Render("Dry run: no changes written"))
+		return nil
+	}
+
+	if err := config.SaveRigSettings(path, migrated); err != nil {
+		return fmt.Errorf("saving warband settings: %w", err)
+	}
+	fmt.Printf("\n%s Wrote %s\n", style.Success.Render("✓"), path)
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/deeklead/horde/internal/config"
+	"github.com/deeklead/horde/internal/ritual"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/workspace"
 	"golang.org/x/text/cases"
@@ -20,12 +21,13 @@ import (
 
 // Ritual command flags
 var (
-	formulaListJSON   bool
-	formulaShowJSON   bool
-	formulaRunPR      int
-	formulaRunRig     string
-	formulaRunDryRun  bool
-	formulaCreateType string
+	formulaListJSON    bool
+	formulaShowJSON    bool
+	formulaShowMermaid bool
+	formulaRunPR       int
+	formulaRunRig      string
+	formulaRunDryRun   bool
+	formulaCreateType  string
 )
 
 var formulaCmd = &cobra.Command{
@@ -43,6 +45,7 @@ for ephemeral scout cycles.
 Commands:
   list    List available rituals from all search paths
   show    Display ritual details (steps, variables, composition)
+  graph   Render the dependency graph (DOT, Mermaid, or waves)
   run     Execute a ritual (cast and dispatch)
   create  Create a new ritual template
 
@@ -85,9 +88,13 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --mermaid, prints the dependency graph as a Mermaid flowchart instead,
+ready to paste into markdown or a handoff bead.
+
 Examples:
   hd ritual show shiny
-  hd ritual show rule-of-five --json`,
+  hd ritual show rule-of-five --json
+  hd ritual show shiny --mermaid`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -98,7 +105,7 @@ var formulaRunCmd = &cobra.Command{
 	Long: `Execute a ritual by pouring it and dispatching work.
 
 This command:
-  1. Looks up the ritual by name (or uses default from warband config)
+  1. Looks up the ritual by name or file path (or uses default from warband config)
   2. Pours it to create a totem (or uses existing proto)
   3. Dispatches the totem to available workers
 
@@ -107,17 +114,40 @@ For PR-based workflows, use --pr to specify the GitHub PR number.
 If no ritual name is provided, uses the default ritual configured in
 the warband's settings/config.json under workflow.default_formula.
 
+Workflow rituals given as a file path, or run with --cmd, run locally
+instead: each wave of ready steps is dispatched in dependency order, running
+--cmd once per step with the step described in {{step.id}}, {{step.title}}
+and {{ritual}} (substituted as shell-quoted words, and also set as
+HD_STEP_ID, HD_STEP_TITLE and HD_RITUAL), plus the step's [steps.env]
+variables. A step's shell (default sh) runs the command, in the step's cwd
+relative to the current directory. Progress is shown per wave, followed by a
+per-step summary.
+
 Options:
   --pr=N      Run ritual on GitHub PR #N
   --warband=NAME  Target specific warband (default: current or horde)
   --dry-run   Show what would happen without executing
 
+Workflow options:
+  --cmd=CMD          Command to run for each step (sh -c)
+  --parallel=N       Max steps running at once (default: unlimited)
+  --timeout=DUR      Per-step timeout
+  --retries=N        Retry a failed step up to N times
+  --on-failure=MODE  stop (default) or continue with independent steps
+  --from=STEP        Run STEP and everything downstream of it
+  --to=STEP          Run STEP and only the upstream steps it needs
+  --only=STEP,...    Run only the listed steps
+
 Examples:
   hd ritual run shiny                    # Run ritual in current warband
   hd ritual run                          # Run default ritual from warband config
   hd ritual run shiny --pr=123           # Run on PR #123
   hd ritual run security-audit --warband=relics  # Run in specific warband
-  hd ritual run release --dry-run        # Preview execution`,
+  hd ritual run release --dry-run        # Preview execution
+  hd ritual run ./ci.ritual.toml --dry-run       # Show a workflow's waves
+  hd ritual run ./ci.ritual.toml --cmd 'make {{step.id}}' --parallel=4
+  hd ritual run ./ci.ritual.toml --cmd 'make {{step.id}}' --from=build
+  hd ritual run ./ci.ritual.toml --cmd 'make {{step.id}}' --to=publish`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -149,6 +179,7 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowMermaid, "mermaid", false, "Output the dependency graph as a Mermaid flowchart")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run ritual on")
@@ -180,9 +211,26 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 	return bdCmd.Run()
 }
 
-// runFormulaShow delegates to rl ritual show
+// runFormulaShow delegates to rl ritual show, except for --mermaid, which
+// renders the ritual file directly.
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowMermaid {
+		if formulaShowJSON {
+			return fmt.Errorf("--mermaid and --json are mutually exclusive")
+		}
+		path, err := findFormulaFile(formulaName)
+		if err != nil {
+			return err
+		}
+		f, err := ritual.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("parsing ritual %s: %w", formulaName, err)
+		}
+		fmt.Print(f.RenderMermaid())
+		return nil
+	}
+
 	bdArgs := []string{"ritual", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -229,20 +277,28 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Load warband workflow settings; a missing or unreadable file leaves
+	// settings nil, which the accessors treat as "no workflow config".
+	var settings *config.RigSettings
+	if rigPath != "" {
+		settings, _ = config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	}
+
 	// Get ritual name from args or default
 	var formulaName string
 	if len(args) > 0 {
 		formulaName = args[0]
 	} else {
 		// Try to get default ritual from warband config
-		if rigPath != "" {
-			formulaName = config.GetDefaultFormula(rigPath)
-		}
+		formulaName = settings.GetDefaultFormula()
 		if formulaName == "" {
 			return fmt.Errorf("no ritual specified and no default ritual configured\n\nTo set a default ritual, add to your warband's settings/config.json:\n  \"workflow\": {\n    \"default_formula\": \"<ritual-name>\"\n  }")
 		}
 		fmt.Printf("%s Using default ritual: %s\n", style.Dim.Render("Note:"), formulaName)
 	}
+	if !settings.IsFormulaAllowed(formulaName) {
+		return fmt.Errorf("ritual %q is not allowed in warband %s (allowed: %s)", formulaName, targetRig, strings.Join(settings.GetAllowedFormulas(), ", "))
+	}
 
 	// Find the ritual file
 	formulaPath, err := findFormulaFile(formulaName)
@@ -256,16 +312,22 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing ritual: %w", err)
 	}
 
+	// Workflow rituals given by path or with --cmd run locally, one --cmd
+	// invocation per step; named ones otherwise take the usual path below
+	if f.Type == "workflow" && (formulaRunStepCmd != "" || isFormulaPath(formulaName)) {
+		return runWorkflowFormula(cmd, formulaPath, formulaName)
+	}
+
 	// Handle dry-run mode
 	if formulaRunDryRun {
 		return dryRunFormula(f, formulaName, targetRig)
 	}
 
-	// Currently only raid rituals are supported for execution
+	// Other than workflows, only raid rituals are supported for execution
 	if f.Type != "raid" {
 		fmt.Printf("%s Ritual type '%s' not yet supported for execution.\n",
 			style.Dim.Render("Note:"), f.Type)
-		fmt.Printf("Currently only 'raid' rituals can be run.\n")
+		fmt.Printf("Currently only 'raid' and 'workflow' rituals can be run.\n")
 		fmt.Printf("\nTo run '%s' manually:\n", formulaName)
 		fmt.Printf("  1. View ritual:   hd ritual show %s\n", formulaName)
 		fmt.Printf("  2. Invoke to proto:  rl invoke %s\n", formulaName)
@@ -505,8 +567,21 @@ type formulaSynthesis struct {
 	DependsOn   []string
 }
 
-// findFormulaFile searches for a ritual file by name
+// isFormulaPath reports whether name is a path to a ritual file rather than
+// a ritual name to search for.
+func isFormulaPath(name string) bool {
+	return strings.ContainsRune(name, filepath.Separator) || strings.HasSuffix(name, ".toml") || strings.HasSuffix(name, ".json")
+}
+
+// findFormulaFile searches for a ritual file by name, or accepts a path to one
 func findFormulaFile(name string) (string, error) {
+	// An explicit path to a ritual file is used as is
+	if isFormulaPath(name) {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			return name, nil
+		}
+	}
+
 	// Search paths in order
 	searchPaths := []string{}
 
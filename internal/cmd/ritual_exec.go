@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/ritual"
+	"github.com/deeklead/horde/internal/style"
+)
+
+// Workflow execution flags for hd ritual run
+var (
+	formulaRunStepCmd   string
+	formulaRunParallel  int
+	formulaRunTimeout   time.Duration
+	formulaRunRetries   int
+	formulaRunOnFailure string
+	formulaRunFrom      string
+	formulaRunTo        string
+	formulaRunOnly      []string
+)
+
+// stepOutputTail is how many lines of a failed step's output are shown.
+const stepOutputTail = 20
+
+func init() {
+	formulaRunCmd.Flags().StringVar(&formulaRunStepCmd, "cmd", "", "Command to run for each workflow step (sh -c; supports {{step.id}}, {{step.title}}, {{ritual}})")
+	formulaRunCmd.Flags().IntVar(&formulaRunParallel, "parallel", 0, "Max workflow steps running at once (0 = unlimited)")
+	formulaRunCmd.Flags().DurationVar(&formulaRunTimeout, "timeout", 0, "Per-step timeout, e.g. 10m (0 = none)")
+	formulaRunCmd.Flags().IntVar(&formulaRunRetries, "retries", 0, "Times to retry a failed workflow step")
+	formulaRunCmd.Flags().StringVar(&formulaRunOnFailure, "on-failure", string(ritual.FailStop), "After a step fails: stop, or continue with steps that don't depend on it")
+	formulaRunCmd.Flags().StringVar(&formulaRunFrom, "from", "", "Run only this workflow step and the steps that depend on it")
+	formulaRunCmd.Flags().StringVar(&formulaRunTo, "to", "", "Run only this workflow step and the steps it needs")
+	formulaRunCmd.Flags().StringSliceVar(&formulaRunOnly, "only", nil, "Run only these workflow steps (comma-separated or repeated)")
+}
+
+// stepRun records how a workflow step went, for the final summary.
+type stepRun struct {
+	Attempts int
+	Duration time.Duration
+}
+
+// runWorkflowFormula executes a workflow ritual locally with ritual.Executor,
+// running the --cmd command once per step. Steps left out by --from, --to,
+// or --only are treated as already done.
+func runWorkflowFormula(cmd *cobra.Command, formulaPath, formulaName string) error {
+	f, err := ritual.ParseFile(formulaPath)
+	if err != nil {
+		return fmt.Errorf("parsing ritual: %w", err)
+	}
+	if f.Name != "" {
+		formulaName = f.Name
+	}
+
+	selected, err := selectWorkflowSteps(f, formulaRunFrom, formulaRunTo, formulaRunOnly)
+	if err != nil {
+		return err
+	}
+
+	policy := ritual.FailurePolicy(formulaRunOnFailure)
+	if policy != ritual.FailStop && policy != ritual.FailContinue {
+		return fmt.Errorf("invalid --on-failure %q: must be %s or %s", formulaRunOnFailure, ritual.FailStop, ritual.FailContinue)
+	}
+	if formulaRunRetries < 0 {
+		return fmt.Errorf("--retries must be non-negative")
+	}
+
+	if formulaRunDryRun {
+		return printWorkflowPlan(f, formulaName, selected)
+	}
+	if formulaRunStepCmd == "" {
+		return fmt.Errorf("workflow rituals need --cmd to run each step (use --dry-run to see the plan)")
+	}
+
+	// From here on, errors are step failures rather than usage mistakes
+	cmd.SilenceUsage = true
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("%s Running workflow ritual: %s\n", style.Bold.Render("⚙️"), formulaName)
+
+	var mu sync.Mutex
+	runs := make(map[string]*stepRun)
+
+	e := ritual.NewExecutor(f)
+	e.ParallelLimit = formulaRunParallel
+	e.OnFailure = policy
+	wave := 0
+	e.OnWave = func(ids []string) {
+		var run []string
+		for _, id := range ids {
+			if selected[id] {
+				run = append(run, id)
+			}
+		}
+		if len(run) == 0 {
+			return
+		}
+		wave++
+		fmt.Printf("\n%s Wave %d: %s\n", style.Bold.Render("▶"), wave, strings.Join(run, ", "))
+	}
+
+	results, execErr := e.Execute(ctx, func(ctx context.Context, id string) error {
+		if !selected[id] {
+			return nil
+		}
+		step := f.GetStep(id)
+		start := time.Now()
+
+		var err error
+		attempts := 0
+		for attempts <= formulaRunRetries {
+			attempts++
+			if err = runWorkflowStep(ctx, f, formulaName, ".", step); err == nil || ctx.Err() != nil {
+				break
+			}
+			if attempts <= formulaRunRetries {
+				fmt.Printf("  %s %s failed (attempt %d), retrying: %s\n",
+					style.Warning.Render("↻"), id, attempts, firstLine(err.Error()))
+			}
+		}
+
+		elapsed := time.Since(start).Round(time.Millisecond)
+		mu.Lock()
+		runs[id] = &stepRun{Attempts: attempts, Duration: elapsed}
+		mu.Unlock()
+
+		if err != nil {
+			fmt.Printf("  %s %s (%s): %s\n", style.Error.Render("✗"), id, elapsed, err)
+		} else {
+			fmt.Printf("  %s %s (%s)\n", style.Success.Render("✓"), id, elapsed)
+		}
+		return err
+	})
+
+	printWorkflowSummary(f, results, runs, selected)
+	return execErr
+}
+
+// selectWorkflowSteps returns the set of steps to run: every step by
+// default, the listed steps for --only, the step and everything downstream
+// of it for --from, or the step and everything it needs for --to.
+func selectWorkflowSteps(f *ritual.Ritual, from, to string, only []string) (map[string]bool, error) {
+	set := 0
+	for _, used := range []bool{from != "", to != "", len(only) > 0} {
+		if used {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("--from, --to, and --only cannot be used together")
+	}
+
+	selected := make(map[string]bool)
+	switch {
+	case len(only) > 0:
+		for _, id := range only {
+			if f.GetStep(id) == nil {
+				return nil, fmt.Errorf("unknown step %q", id)
+			}
+			selected[id] = true
+		}
+	case from != "":
+		if f.GetStep(from) == nil {
+			return nil, fmt.Errorf("unknown step %q", from)
+		}
+		dependents := make(map[string][]string)
+		for _, id := range f.GetAllIDs() {
+			for _, dep := range f.GetDependencies(id) {
+				dependents[dep] = append(dependents[dep], id)
+			}
+		}
+		queue := []string{from}
+		selected[from] = true
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, next := range dependents[id] {
+				if !selected[next] {
+					selected[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	case to != "":
+		required, err := f.RequiredFor(to)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range required {
+			selected[id] = true
+		}
+	default:
+		for _, id := range f.GetAllIDs() {
+			selected[id] = true
+		}
+	}
+	return selected, nil
+}
+
+// runWorkflowStep runs the --cmd command for a step, applying --timeout.
+// The command runs under the step's shell, in its cwd relative to root.
+// The step's env table is added to the environment, and the step is also
+// described to the command through HD_RITUAL, HD_STEP_ID, and HD_STEP_TITLE.
+func runWorkflowStep(ctx context.Context, f *ritual.Ritual, formulaName, root string, step *ritual.Step) error {
+	dir, err := f.StepDir(step.ID, root)
+	if err != nil {
+		return err
+	}
+	if formulaRunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, formulaRunTimeout)
+		defer cancel()
+	}
+
+	// Substituted values come from the ritual file, not the user, so they
+	// are quoted to reach the command as single words without expansion.
+	command := strings.NewReplacer(
+		"{{step.id}}", shellQuote(step.ID),
+		"{{step.title}}", shellQuote(step.Title),
+		"{{ritual}}", shellQuote(formulaName),
+	).Replace(formulaRunStepCmd)
+
+	c := exec.CommandContext(ctx, f.StepShell(step.ID), "-c", command) //nolint:gosec // G204: command is the user's --cmd with quoted ritual values
+	c.Dir = dir
+	c.Env = os.Environ()
+	for k, v := range f.StepEnv(step.ID) {
+		c.Env = append(c.Env, k+"="+v)
+	}
+	c.Env = append(c.Env,
+		"HD_RITUAL="+formulaName,
+		"HD_STEP_ID="+step.ID,
+		"HD_STEP_TITLE="+step.Title,
+	)
+	var output bytes.Buffer
+	c.Stdout = &output
+	c.Stderr = &output
+	// Don't wait on children of a killed step that still hold its output open
+	c.WaitDelay = time.Second
+
+	err = c.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", formulaRunTimeout)
+	}
+	if err != nil {
+		if tail := outputTail(output.String(), stepOutputTail); tail != "" {
+			return fmt.Errorf("%w\n%s", err, tail)
+		}
+		return err
+	}
+	return nil
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// printWorkflowPlan prints the waves a run would dispatch, for --dry-run.
+func printWorkflowPlan(f *ritual.Ritual, formulaName string, selected map[string]bool) error {
+	waves, err := f.Waves()
+	if err != nil {
+		return err
+	}
+	est, err := f.EstimateParallelism(formulaRunParallel)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Would execute workflow ritual:\n", style.Dim.Render("[dry-run]"))
+	fmt.Printf("  Ritual:  %s\n", style.Bold.Render(formulaName))
+	if formulaRunStepCmd != "" {
+		fmt.Printf("  Command: %s\n", formulaRunStepCmd)
+	}
+	fmt.Printf("  Steps:   %d (%d waves, widest %d, %d rounds", est.TotalSteps, est.Waves, est.MaxWidth, est.Rounds)
+	if formulaRunParallel > 0 {
+		fmt.Printf(" with --parallel=%d", formulaRunParallel)
+	}
+	fmt.Printf(")\n")
+
+	for i, wave := range waves {
+		fmt.Printf("\n  Wave %d:\n", i+1)
+		for _, id := range wave {
+			line := id
+			if step := f.GetStep(id); step != nil && step.Title != "" {
+				line += ": " + step.Title
+			}
+			if !selected[id] {
+				fmt.Printf("    %s\n", style.Dim.Render("○ "+line+" (not selected)"))
+				continue
+			}
+			fmt.Printf("    • %s\n", line)
+		}
+	}
+	return nil
+}
+
+// printWorkflowSummary prints the per-step results of a workflow run.
+func printWorkflowSummary(f *ritual.Ritual, results map[string]ritual.StepResult, runs map[string]*stepRun, selected map[string]bool) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Results:"))
+
+	counts := make(map[ritual.StepStatus]int)
+	for _, id := range f.GetAllIDs() {
+		if !selected[id] {
+			fmt.Printf("  %s\n", style.Dim.Render("○ "+id+"  not selected"))
+			continue
+		}
+
+		result := results[id]
+		counts[result.Status]++
+		detail := ""
+		if run := runs[id]; run != nil {
+			detail = run.Duration.String()
+			if run.Attempts > 1 {
+				detail += fmt.Sprintf(", %d attempts", run.Attempts)
+			}
+		}
+
+		switch result.Status {
+		case ritual.StepCompleted:
+			fmt.Printf("  %s %s  %s\n", style.Success.Render("✓"), id, style.Dim.Render(detail))
+		case ritual.StepFailed:
+			optional := ""
+			if f.IsOptional(id) {
+				optional = " (optional)"
+			}
+			fmt.Printf("  %s %s  %s%s: %s\n", style.Error.Render("✗"), id, style.Dim.Render(detail), optional,
+				firstLine(result.Err.Error()))
+		default:
+			fmt.Printf("  %s %s  skipped\n", style.Dim.Render("-"), id)
+		}
+	}
+
+	fmt.Printf("\n%d completed, %d failed, %d skipped\n",
+		counts[ritual.StepCompleted], counts[ritual.StepFailed], counts[ritual.StepSkipped])
+}
+
+// outputTail returns the last n non-empty lines of output, indented.
+func outputTail(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		b.WriteString("    | " + line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
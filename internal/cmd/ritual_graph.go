@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/ritual"
+)
+
+// Ritual graph flags
+var (
+	formulaGraphFormat string
+	formulaGraphTo     string
+)
+
+var formulaGraphCmd = &cobra.Command{
+	Use:   "graph <name|file>",
+	Short: "Render a ritual's dependency graph",
+	Long: `Render a ritual's dependency graph to stdout.
+
+The ritual is parsed and validated first, so errors in the file are reported
+instead of a partial graph.
+
+Formats:
+  dot      Graphviz DOT, for piping into dot (default)
+  mermaid  Mermaid flowchart, for pasting into markdown
+  waves    The dependency levels a run dispatches, one line per wave
+
+With --to, only that step and the steps it needs are drawn, matching
+'hd ritual run --to'.
+
+Examples:
+  hd ritual graph workflow.ritual.toml | dot -Tsvg > workflow.svg
+  hd ritual graph release --format mermaid
+  hd ritual graph release --to publish --format waves`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaGraph,
+}
+
+func init() {
+	formulaGraphCmd.Flags().StringVar(&formulaGraphFormat, "format", "dot", "Output format: dot, mermaid, or waves")
+	formulaGraphCmd.Flags().StringVar(&formulaGraphTo, "to", "", "Draw only this step and the steps it needs")
+
+	formulaCmd.AddCommand(formulaGraphCmd)
+}
+
+// runFormulaGraph renders a ritual file in the chosen graph format.
+func runFormulaGraph(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+	switch formulaGraphFormat {
+	case "dot", "mermaid", "waves":
+	default:
+		return fmt.Errorf("invalid --format %q: must be dot, mermaid, or waves", formulaGraphFormat)
+	}
+
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		return err
+	}
+	f, err := ritual.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("parsing ritual %s: %w", formulaName, err)
+	}
+	if formulaGraphTo != "" {
+		if f, err = f.Subgraph(formulaGraphTo); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+
+	out, err := renderFormulaGraph(f, formulaGraphFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// renderFormulaGraph renders f as dot, mermaid, or waves.
+func renderFormulaGraph(f *ritual.Ritual, format string) (string, error) {
+	switch format {
+	case "mermaid":
+		return f.RenderMermaid(), nil
+	case "waves":
+		waves, err := f.Waves()
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		for i, wave := range waves {
+			fmt.Fprintf(&b, "Wave %d: %s\n", i+1, strings.Join(wave, ", "))
+		}
+		return b.String(), nil
+	default:
+		return f.RenderDOT(), nil
+	}
+}
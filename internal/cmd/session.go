@@ -13,6 +13,7 @@ import (
 	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/git"
 	"github.com/deeklead/horde/internal/raider"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/warband"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/suggest"
@@ -653,7 +654,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 				continue
 			}
 			raiderName := entry.Name()
-			sessionName := fmt.Sprintf("hd-%s-%s", r.Name, raiderName)
+			sessionName := session.RaiderSessionName(r.Name, raiderName)
 			totalChecked++
 
 			// Check if session exists
@@ -11,6 +11,7 @@ import (
 	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/constants"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/workspace"
@@ -368,8 +369,8 @@ func wakeRigAgents(rigName string) {
 
 	// Signal witness and forge to clear any backoff
 	t := tmux.NewTmux()
-	witnessSession := fmt.Sprintf("hd-%s-witness", rigName)
-	forgeSession := fmt.Sprintf("hd-%s-forge", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
+	forgeSession := session.ForgeSessionName(rigName)
 
 	// Silent nudges - sessions might not exist yet
 	_ = t.SignalSession(witnessSession, "Raider dispatched - check for work")
@@ -814,7 +814,7 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 
 	// Resolve account for Claude config
 	accountsPath := constants.WarchiefAccountsPath(townRoot)
-	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, startCrewAccount)
+	claudeConfigDir, accountHandle, err := config.ResolveRigAccountConfigDir(accountsPath, r.Path, startCrewAccount)
 	if err != nil {
 		return fmt.Errorf("resolving account: %w", err)
 	}
@@ -18,6 +18,7 @@ import (
 	"github.com/deeklead/horde/internal/clan"
 	"github.com/deeklead/horde/internal/git"
 	"github.com/deeklead/horde/internal/drums"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/warband"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
@@ -266,13 +267,15 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	// Fetch warband-level agent relics
 	for _, r := range warbands {
 		rigRelicsPath := filepath.Join(r.Path, "warchief", "warband")
-		rigRelics := relics.New(rigRelicsPath)
-		rigAgentRelics, _ := rigRelics.ListAgentRelics()
-		if rigAgentRelics == nil {
+		rigAgentRelics, err := townRelicsClient.ListIn(rigRelicsPath, relics.ListOptions{
+			Label:    "gt:agent",
+			Priority: -1,
+		})
+		if err != nil {
 			continue
 		}
-		for id, issue := range rigAgentRelics {
-			allAgentRelics[id] = issue
+		for _, issue := range rigAgentRelics {
+			allAgentRelics[issue.ID] = issue
 		}
 
 		var hookIDs []string
@@ -293,7 +296,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		if len(hookIDs) == 0 {
 			continue
 		}
-		bannerRelics, _ := rigRelics.ShowMultiple(hookIDs)
+		bannerRelics, _ := townRelicsClient.ShowMultipleIn(rigRelicsPath, hookIDs)
 		for id, issue := range bannerRelics {
 			allBannerRelics[id] = issue
 		}
@@ -1044,7 +1047,7 @@ func discoverRigAgents(allSessions map[string]bool, r *warband.Warband, clans []
 		defs = append(defs, agentDef{
 			name:    "forge",
 			address: r.Name + "/forge",
-			session: fmt.Sprintf("hd-%s-forge", r.Name),
+			session: session.ForgeSessionName(r.Name),
 			role:    "forge",
 			beadID:  relics.ForgeBeadIDWithPrefix(prefix, r.Name),
 		})
@@ -1055,7 +1058,7 @@ func discoverRigAgents(allSessions map[string]bool, r *warband.Warband, clans []
 		defs = append(defs, agentDef{
 			name:    name,
 			address: r.Name + "/" + name,
-			session: fmt.Sprintf("hd-%s-%s", r.Name, name),
+			session: session.RaiderSessionName(r.Name, name),
 			role:    "raider",
 			beadID:  relics.RaiderBeadIDWithPrefix(prefix, r.Name, name),
 		})
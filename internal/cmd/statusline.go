@@ -11,6 +11,7 @@ import (
 	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/drums"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/workspace"
 )
@@ -477,7 +478,7 @@ func runWitnessStatusLine(t *tmux.Tmux, rigName string) error {
 
 	// Get encampment root from witness pane's working directory
 	var townRoot string
-	sessionName := fmt.Sprintf("hd-%s-witness", rigName)
+	sessionName := session.WitnessSessionName(rigName)
 	paneDir, err := t.GetPaneWorkDir(sessionName)
 	if err == nil && paneDir != "" {
 		townRoot, _ = workspace.Find(paneDir)
@@ -556,7 +557,7 @@ func runForgeStatusLine(t *tmux.Tmux, rigName string) error {
 
 	// Get encampment root from forge pane's working directory
 	var townRoot string
-	sessionName := fmt.Sprintf("hd-%s-forge", rigName)
+	sessionName := session.ForgeSessionName(rigName)
 	paneDir, err := t.GetPaneWorkDir(sessionName)
 	if err == nil && paneDir != "" {
 		townRoot, _ = workspace.Find(paneDir)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/relics"
 	"github.com/deeklead/horde/internal/ritual"
 	"github.com/deeklead/horde/internal/runtime"
 	"github.com/deeklead/horde/internal/style"
@@ -409,19 +411,24 @@ func getRaidMeta(raidID string) (*RaidMeta, error) {
 }
 
 // collectLegOutputs gathers outputs from all raid legs.
-func collectLegOutputs(meta *RaidMeta, f *ritual.Ritual) ([]LegOutput, bool, error) { //nolint:unparam // error return kept for future use
+// A leg whose relics database stays unavailable is an error rather than an
+// unknown leg, so synthesis isn't started or skipped on a transient failure.
+func collectLegOutputs(meta *RaidMeta, f *ritual.Ritual) ([]LegOutput, bool, error) {
 	var outputs []LegOutput
 	allComplete := true
 
 	// If we have tracked issues, use those as legs
 	if len(meta.LegIssues) > 0 {
 		for _, issueID := range meta.LegIssues {
-			details := getIssueDetails(issueID)
+			details, err := getIssueDetailsRetry(issueID)
+			if errors.Is(err, relics.ErrUnavailable) {
+				return nil, false, fmt.Errorf("looking up leg %s: %w", issueID, err)
+			}
 			output := LegOutput{
 				LegID: issueID,
 				Title: "(unknown)",
 			}
-			if details != nil {
+			if err == nil {
 				output.Title = details.Title
 				output.Status = details.Status
 			}
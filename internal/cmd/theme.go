@@ -338,25 +338,14 @@ func saveRigTheme(rigName, themeName string) error {
 
 	settingsPath := filepath.Join(townRoot, rigName, "settings", "config.json")
 
-	// Load existing settings or create new
-	var settings *config.RigSettings
-	settings, err = config.LoadRigSettings(settingsPath)
-	if err != nil {
-		// Create new settings if not found
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
+	// Update existing settings or create new
+	err = config.UpdateRigSettings(settingsPath, func(settings *config.RigSettings) error {
+		settings.Theme = &config.ThemeConfig{
+			Name: themeName,
 		}
-	}
-
-	// Set theme
-	settings.Theme = &config.ThemeConfig{
-		Name: themeName,
-	}
-
-	// Save
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving settings: %w", err)
 	}
 
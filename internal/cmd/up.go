@@ -14,6 +14,7 @@ import (
 	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/clan"
 	"github.com/deeklead/horde/internal/daemon"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/shaman"
 	"github.com/deeklead/horde/internal/events"
 	"github.com/deeklead/horde/internal/warchief"
@@ -188,7 +189,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 		for _, rigName := range warbands {
 			crewStarted, crewErrors := startCrewFromSettings(townRoot, rigName)
 			for _, name := range crewStarted {
-				printStatus(fmt.Sprintf("Clan (%s/%s)", rigName, name), true, fmt.Sprintf("hd-%s-clan-%s", rigName, name))
+				printStatus(fmt.Sprintf("Clan (%s/%s)", rigName, name), true, session.CrewSessionName(rigName, name))
 			}
 			for name, err := range crewErrors {
 				printStatus(fmt.Sprintf("Clan (%s/%s)", rigName, name), false, err.Error())
@@ -200,7 +201,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 		for _, rigName := range warbands {
 			raidersStarted, raiderErrors := startRaidersWithWork(townRoot, rigName)
 			for _, name := range raidersStarted {
-				printStatus(fmt.Sprintf("Raider (%s/%s)", rigName, name), true, fmt.Sprintf("hd-%s-raider-%s", rigName, name))
+				printStatus(fmt.Sprintf("Raider (%s/%s)", rigName, name), true, session.RaiderSessionName(rigName, name))
 			}
 			for name, err := range raiderErrors {
 				printStatus(fmt.Sprintf("Raider (%s/%s)", rigName, name), false, err.Error())
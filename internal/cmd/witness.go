@@ -7,6 +7,7 @@ import (
 	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/deeklead/horde/internal/session"
 	"github.com/deeklead/horde/internal/style"
 	"github.com/deeklead/horde/internal/tmux"
 	"github.com/deeklead/horde/internal/witness"
@@ -279,7 +280,7 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 
 // witnessSessionName returns the tmux session name for a warband's witness.
 func witnessSessionName(rigName string) string {
-	return fmt.Sprintf("hd-%s-witness", rigName)
+	return session.WitnessSessionName(rigName)
 }
 
 func runWitnessAttach(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrInvalidAccountStrategy indicates an unknown account selection strategy.
+var ErrInvalidAccountStrategy = errors.New("invalid account strategy")
+
+// AccountsState records which accounts were handed out and when, so the
+// round-robin and lru strategies can pick the next one (warchief/accounts-state.json).
+type AccountsState struct {
+	LastUsed map[string]time.Time `json:"last_used"`      // handle -> last selection time
+	Last     string               `json:"last,omitempty"` // most recently selected handle
+}
+
+// AccountsStatePath returns the path of the state file kept alongside the
+// accounts config at accountsPath.
+func AccountsStatePath(accountsPath string) string {
+	return filepath.Join(filepath.Dir(accountsPath), "accounts-state.json")
+}
+
+// ResolveAccountConfigDirWithStrategy is ResolveAccountConfigDir for callers
+// that start a session on the resolved account. With the round-robin or lru
+// strategy, it picks from all accounts instead of the default one and records
+// the selection in the state file, so sessions are spread across the pool.
+// HD_ACCOUNT and accountFlag still take priority, and count as a use.
+func ResolveAccountConfigDirWithStrategy(accountsPath, accountFlag string) (configDir, handle string, err error) {
+	return ResolveRigAccountConfigDir(accountsPath, "", accountFlag)
+}
+
+// ResolveRigAccountConfigDir is ResolveAccountConfigDirWithStrategy for a
+// session in the warband at rigPath: if the warband's settings select an
+// account, it is used ahead of the default account and the pool strategy.
+// HD_ACCOUNT and accountFlag still override it, as for agent overrides.
+func ResolveRigAccountConfigDir(accountsPath, rigPath, accountFlag string) (configDir, handle string, err error) {
+	cfg, loadErr := LoadAccountsConfig(accountsPath)
+	if loadErr != nil {
+		// No accounts configured - that's OK, return empty
+		return "", "", nil
+	}
+	rigAccount := RigAccount(rigPath)
+	if cfg.Strategy != AccountStrategyRoundRobin && cfg.Strategy != AccountStrategyLRU {
+		return resolveAccount(cfg, accountFlag, rigAccount)
+	}
+
+	// Serialize selection so concurrent spawns don't pick the same account.
+	lock := flock.New(AccountsStatePath(accountsPath) + ".lock")
+	if err := lock.Lock(); err != nil {
+		return "", "", fmt.Errorf("locking accounts state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := loadAccountsState(AccountsStatePath(accountsPath))
+	if err != nil {
+		return "", "", err
+	}
+
+	configDir, handle, err = resolveAccount(cfg, accountFlag, rigAccount)
+	if err != nil {
+		return "", "", err
+	}
+	if os.Getenv("HD_ACCOUNT") == "" && accountFlag == "" && rigAccount == "" {
+		handle = pickAccount(cfg, state)
+		if handle == "" {
+			return "", "", nil
+		}
+		configDir = expandPath(cfg.Accounts[handle].ConfigDir)
+	}
+
+	state.LastUsed[handle] = time.Now()
+	state.Last = handle
+	if err := saveAccountsState(AccountsStatePath(accountsPath), state); err != nil {
+		return "", "", err
+	}
+	return configDir, handle, nil
+}
+
+// pickAccount returns the account the config's strategy selects next, or ""
+// if there are no accounts.
+func pickAccount(cfg *AccountsConfig, state *AccountsState) string {
+	handles := make([]string, 0, len(cfg.Accounts))
+	for h := range cfg.Accounts {
+		handles = append(handles, h)
+	}
+	sort.Strings(handles)
+	if len(handles) == 0 {
+		return ""
+	}
+
+	if cfg.Strategy == AccountStrategyLRU {
+		// Never-used accounts have the zero time and so come first.
+		best := handles[0]
+		for _, h := range handles[1:] {
+			if state.LastUsed[h].Before(state.LastUsed[best]) {
+				best = h
+			}
+		}
+		return best
+	}
+
+	// Round-robin: the handle after the last one used, wrapping around.
+	for i, h := range handles {
+		if h == state.Last {
+			return handles[(i+1)%len(handles)]
+		}
+	}
+	if cfg.Default != "" {
+		return cfg.Default
+	}
+	return handles[0]
+}
+
+// loadAccountsState reads the accounts state file, returning an empty state
+// if it doesn't exist yet.
+func loadAccountsState(path string) (*AccountsState, error) {
+	state := &AccountsState{}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading accounts state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("parsing accounts state: %w", err)
+		}
+	}
+	if state.LastUsed == nil {
+		state.LastUsed = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// saveAccountsState writes the accounts state file.
+func saveAccountsState(path string, state *AccountsState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding accounts state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts state holds only handles and timestamps
+		return fmt.Errorf("writing accounts state: %w", err)
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -234,7 +236,7 @@ func loadAgentRegistryFromPathLocked(path string) error {
 	}
 
 	var userRegistry AgentRegistry
-	if err := json.Unmarshal(data, &userRegistry); err != nil {
+	if err := unmarshalConfig(data, &userRegistry); err != nil {
 		return err
 	}
 
@@ -407,7 +409,9 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 	result := &RuntimeConfig{
 		Command:       rc.Command,
 		Args:          append([]string(nil), rc.Args...),
+		Env:           rc.Env,
 		InitialPrompt: rc.InitialPrompt,
+		PromptFile:    rc.PromptFile,
 	}
 
 	// Apply preset defaults only if not overridden
@@ -430,6 +434,67 @@ func IsKnownPreset(name string) bool {
 	return ok
 }
 
+// Agent sources reported in AgentInfo.Source.
+const (
+	AgentSourcePreset = "preset" // built-in or agents.json registry preset
+	AgentSourceTown   = "encampment"
+	AgentSourceRig    = "warband"
+)
+
+// AgentInfo describes an agent that can be named in default_agent or
+// role_agents.
+type AgentInfo struct {
+	Name      string `json:"name"`
+	Source    string `json:"source"`    // AgentSourcePreset, AgentSourceTown, or AgentSourceRig
+	Command   string `json:"command"`   // binary the agent runs
+	Available bool   `json:"available"` // whether Command is on PATH
+}
+
+// ListAvailableAgents returns every agent that can be resolved with the given
+// settings: the registry presets plus the encampment's and warband's custom
+// agents, sorted by name. Either settings may be nil. A name defined in more
+// than one place is listed once, with the source and command that lookup
+// would use (warband, then encampment, then preset).
+func ListAvailableAgents(townSettings *TownSettings, rigSettings *RigSettings) []AgentInfo {
+	sources := make(map[string]string)
+	for _, name := range ListAgentPresets() {
+		sources[name] = AgentSourcePreset
+	}
+	if townSettings != nil {
+		for name, rc := range townSettings.Agents {
+			if rc != nil {
+				sources[name] = AgentSourceTown
+			}
+		}
+	}
+	if rigSettings != nil {
+		for name, rc := range rigSettings.Agents {
+			if rc != nil {
+				sources[name] = AgentSourceRig
+			}
+		}
+	}
+
+	agents := make([]AgentInfo, 0, len(sources))
+	for name, source := range sources {
+		rc := lookupAgentConfigIfExists(name, townSettings, rigSettings)
+		if rc == nil {
+			continue
+		}
+		_, err := exec.LookPath(rc.Command)
+		agents = append(agents, AgentInfo{
+			Name:      name,
+			Source:    source,
+			Command:   rc.Command,
+			Available: err == nil,
+		})
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].Name < agents[j].Name
+	})
+	return agents
+}
+
 // SaveAgentRegistry writes the agent registry to a file.
 func SaveAgentRegistry(path string, registry *AgentRegistry) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -629,3 +629,48 @@ func TestLoadRigAgentRegistry(t *testing.T) {
 		}
 	})
 }
+
+func TestListAvailableAgents(t *testing.T) {
+	t.Parallel()
+	townSettings := NewTownSettings()
+	townSettings.Agents = map[string]*RuntimeConfig{
+		"shared": {Command: "nonexistent-binary-xyz"},
+		"gemini": {Command: "sh"},
+	}
+	rigSettings := NewRigSettings()
+	rigSettings.Agents = map[string]*RuntimeConfig{
+		"shared": {Command: "sh"},
+	}
+
+	agents := ListAvailableAgents(townSettings, rigSettings)
+	byName := make(map[string]AgentInfo)
+	for i, a := range agents {
+		if i > 0 && agents[i-1].Name >= a.Name {
+			t.Errorf("agents not sorted: %q before %q", agents[i-1].Name, a.Name)
+		}
+		byName[a.Name] = a
+	}
+
+	want := map[string]AgentInfo{
+		"claude": {Name: "claude", Source: AgentSourcePreset, Command: "claude"},
+		"gemini": {Name: "gemini", Source: AgentSourceTown, Command: "sh", Available: true},
+		"shared": {Name: "shared", Source: AgentSourceRig, Command: "sh", Available: true},
+	}
+	for name, w := range want {
+		got, ok := byName[name]
+		if !ok {
+			t.Errorf("agent %q not listed", name)
+			continue
+		}
+		if got.Source != w.Source || got.Command != w.Command {
+			t.Errorf("agent %q = %+v, want source %q command %q", name, got, w.Source, w.Command)
+		}
+		if w.Available && !got.Available {
+			t.Errorf("agent %q Available = false, want true", name)
+		}
+	}
+
+	if got := ListAvailableAgents(nil, nil); len(got) < len(builtinPresets) {
+		t.Errorf("ListAvailableAgents(nil, nil) listed %d agents, want at least %d presets", len(got), len(builtinPresets))
+	}
+}
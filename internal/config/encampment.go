@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/deeklead/horde/internal/constants"
+)
+
+// ErrInconsistent indicates config files that are each valid but disagree
+// with one another, such as a registered warband with no config.json.
+var ErrInconsistent = errors.New("inconsistent encampment config")
+
+// EncampmentError is a problem found by ValidateEncampment, tied to the file
+// it was found in.
+type EncampmentError struct {
+	Path string // config file the problem belongs to
+	Err  error
+}
+
+func (e *EncampmentError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *EncampmentError) Unwrap() error {
+	return e.Err
+}
+
+// encampmentRoute is a line of the encampment's .relics/routes.jsonl. The
+// relics package owns this file but imports config, so it is read directly.
+type encampmentRoute struct {
+	Prefix string `json:"prefix"`
+	Path   string `json:"path"`
+}
+
+// ValidateEncampment loads the encampment's config files and checks the
+// cross-file invariants single-file validation can't see:
+//   - every warband in warbands.json has a directory and a valid config.json
+//     whose name and relics prefix match the registry
+//   - warband settings, where present, load, and any account they select
+//     exists in the accounts config
+//   - every route in routes.jsonl points at a registered warband (or the
+//     encampment), and every registered prefix has a route
+//   - the accounts config, where present, loads (its default must exist)
+//
+// Load failures are reported alongside inconsistencies, which wrap
+// ErrInconsistent. Each problem is an *EncampmentError. A nil result means
+// no problems were found.
+func ValidateEncampment(townRoot string) []error {
+	var errs []error
+	report := func(path string, err error) {
+		errs = append(errs, &EncampmentError{Path: path, Err: err})
+	}
+	inconsistent := func(path, format string, args ...any) {
+		report(path, fmt.Errorf("%w: %s", ErrInconsistent, fmt.Sprintf(format, args...)))
+	}
+
+	rigsPath := constants.WarchiefRigsPath(townRoot)
+	rigs, err := LoadRigsConfig(rigsPath)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		report(rigsPath, err)
+	}
+	if rigs == nil {
+		rigs = &RigsConfig{}
+	}
+
+	names := make([]string, 0, len(rigs.Warbands))
+	for name := range rigs.Warbands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prefixes := make(map[string]string)    // relics prefix (e.g., "hd") -> warband
+	rigAccounts := make(map[string]string) // warband settings path -> account
+	for _, name := range names {
+		entry := rigs.Warbands[name]
+		rigPath := filepath.Join(townRoot, name)
+		if prefix := relicsPrefix(entry.RelicsConfig); prefix != "" {
+			prefixes[prefix] = name
+		}
+
+		if info, err := os.Stat(rigPath); err != nil || !info.IsDir() {
+			inconsistent(rigsPath, "warband %q is registered but %s is missing", name, rigPath)
+			continue
+		}
+
+		configPath := filepath.Join(rigPath, "config.json")
+		rigCfg, err := LoadRigConfig(configPath)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			inconsistent(rigsPath, "warband %q is registered but %s is missing", name, configPath)
+		case err != nil:
+			report(configPath, err)
+		default:
+			if rigCfg.Name != name {
+				inconsistent(configPath, "name %q doesn't match registered warband %q", rigCfg.Name, name)
+			}
+			if regPrefix, cfgPrefix := relicsPrefix(entry.RelicsConfig), relicsPrefix(rigCfg.Relics); regPrefix != "" && cfgPrefix != "" && regPrefix != cfgPrefix {
+				inconsistent(configPath, "relics prefix %q doesn't match %q in warbands.json", cfgPrefix, regPrefix)
+			}
+		}
+
+		settingsPath := RigSettingsPath(rigPath)
+		settings, err := LoadRigSettings(settingsPath)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			report(settingsPath, err)
+		}
+		if settings != nil && settings.Account != "" {
+			rigAccounts[settingsPath] = settings.Account
+		}
+	}
+
+	routesPath := filepath.Join(townRoot, ".relics", "routes.jsonl")
+	routes, err := loadEncampmentRoutes(routesPath)
+	if err != nil {
+		report(routesPath, err)
+	}
+	routed := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		prefix := strings.TrimSuffix(r.Prefix, "-")
+		routed[prefix] = true
+		if r.Path == "." {
+			continue
+		}
+		rigName, _, _ := strings.Cut(r.Path, "/")
+		if _, ok := rigs.Warbands[rigName]; !ok {
+			inconsistent(routesPath, "route %q points at %s, but warband %q is not registered", r.Prefix, r.Path, rigName)
+			continue
+		}
+		if owner, ok := prefixes[prefix]; ok && owner != rigName {
+			inconsistent(routesPath, "route %q points at warband %q, but the prefix is registered to %q", r.Prefix, rigName, owner)
+		}
+	}
+	if routes != nil {
+		for _, name := range names {
+			entry := rigs.Warbands[name]
+			if prefix := relicsPrefix(entry.RelicsConfig); prefix != "" && !routed[prefix] {
+				inconsistent(routesPath, "warband %q has relics prefix %q but no route", name, prefix)
+			}
+		}
+	}
+
+	accountsPath := constants.WarchiefAccountsPath(townRoot)
+	accounts, err := LoadAccountsConfig(accountsPath)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		report(accountsPath, err)
+	}
+	settingsPaths := make([]string, 0, len(rigAccounts))
+	for path := range rigAccounts {
+		settingsPaths = append(settingsPaths, path)
+	}
+	sort.Strings(settingsPaths)
+	for _, path := range settingsPaths {
+		if handle := rigAccounts[path]; accounts == nil || accounts.GetAccount(handle) == nil {
+			inconsistent(path, "account %q is not in %s", handle, accountsPath)
+		}
+	}
+
+	return errs
+}
+
+// relicsPrefix returns the configured issue prefix without its trailing
+// hyphen, or "" if there is none.
+func relicsPrefix(c *RelicsConfig) string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSuffix(c.Prefix, "-")
+}
+
+// loadEncampmentRoutes reads routes.jsonl. It returns nil if the file doesn't
+// exist, in which case routes aren't checked.
+func loadEncampmentRoutes(path string) ([]encampmentRoute, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading routes: %w", err)
+	}
+	defer file.Close()
+
+	routes := []encampmentRoute{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var r encampmentRoute
+		if err := json.Unmarshal([]byte(text), &r); err != nil {
+			return nil, fmt.Errorf("parsing routes line %d: %w", line, err)
+		}
+		routes = append(routes, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading routes: %w", err)
+	}
+	return routes, nil
+}
@@ -39,6 +39,20 @@ type AgentEnvConfig struct {
 
 // AgentEnv returns all environment variables for an agent based on the config.
 // This is the single source of truth for agent environment variables.
+//
+// Every role gets HD_ROLE. BD_ACTOR and GIT_AUTHOR_NAME identify the agent:
+//
+//	warchief, shaman   "warchief", "shaman" (both variables)
+//	boot               BD_ACTOR "shaman-boot", GIT_AUTHOR_NAME "boot"
+//	witness, forge     "<warband>/witness", "<warband>/forge" (both), plus HD_WARBAND
+//	raider             BD_ACTOR "<warband>/raiders/<name>", GIT_AUTHOR_NAME "<name>",
+//	                   plus HD_WARBAND, HD_RAIDER, RELICS_AGENT_NAME "<warband>/<name>"
+//	clan               BD_ACTOR "<warband>/clan/<name>", GIT_AUTHOR_NAME "<name>",
+//	                   plus HD_WARBAND, HD_CLAN, RELICS_AGENT_NAME "<warband>/<name>"
+//
+// HD_ROOT, CLAUDE_CONFIG_DIR, HD_SESSION_ID_ENV and RELICS_NO_DAEMON are set
+// only when the corresponding config field is. The result depends on cfg
+// alone; ExportPrefix renders it in a stable order.
 func AgentEnv(cfg AgentEnvConfig) map[string]string {
 	env := make(map[string]string)
 
@@ -136,7 +150,7 @@ func ExportPrefix(env map[string]string) string {
 
 	var parts []string
 	for _, k := range keys {
-		parts = append(parts, fmt.Sprintf("%s=%s", k, env[k]))
+		parts = append(parts, k+"="+quoteEnvValue(env[k]))
 	}
 
 	return "export " + strings.Join(parts, " ") + " && "
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -201,6 +202,15 @@ func TestExportPrefix(t *testing.T) {
 			},
 			expected: "export AAA=first MMM=middle ZZZ=last && ",
 		},
+		{
+			name: "values needing quotes",
+			env: map[string]string{
+				"EMPTY": "",
+				"PATHS": "/a/b:/c",
+				"TOKEN": "it's $x && y",
+			},
+			expected: `export EMPTY='' PATHS=/a/b:/c TOKEN='it'\''s $x && y' && `,
+		},
 	}
 
 	for _, tt := range tests {
@@ -324,3 +334,101 @@ func assertNotSet(t *testing.T, env map[string]string, key string) {
 		t.Errorf("env[%q] should not be set, but is %q", key, env[key])
 	}
 }
+
+// TestAgentEnv_Roles pins the complete variable set for each role, so a
+// change to the role mapping shows up here rather than in command strings.
+func TestAgentEnv_Roles(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  AgentEnvConfig
+		want map[string]string
+	}{
+		{
+			name: "warchief",
+			cfg:  AgentEnvConfig{Role: "warchief", TownRoot: "/encampment"},
+			want: map[string]string{
+				"HD_ROLE": "warchief", "BD_ACTOR": "warchief", "GIT_AUTHOR_NAME": "warchief",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "shaman",
+			cfg:  AgentEnvConfig{Role: "shaman", TownRoot: "/encampment"},
+			want: map[string]string{
+				"HD_ROLE": "shaman", "BD_ACTOR": "shaman", "GIT_AUTHOR_NAME": "shaman",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "boot",
+			cfg:  AgentEnvConfig{Role: "boot", TownRoot: "/encampment"},
+			want: map[string]string{
+				"HD_ROLE": "boot", "BD_ACTOR": "shaman-boot", "GIT_AUTHOR_NAME": "boot",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "witness",
+			cfg:  AgentEnvConfig{Role: "witness", Warband: "myrig", TownRoot: "/encampment"},
+			want: map[string]string{
+				"HD_ROLE": "witness", "HD_WARBAND": "myrig",
+				"BD_ACTOR": "myrig/witness", "GIT_AUTHOR_NAME": "myrig/witness",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "forge",
+			cfg:  AgentEnvConfig{Role: "forge", Warband: "myrig", TownRoot: "/encampment"},
+			want: map[string]string{
+				"HD_ROLE": "forge", "HD_WARBAND": "myrig",
+				"BD_ACTOR": "myrig/forge", "GIT_AUTHOR_NAME": "myrig/forge",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "raider",
+			cfg: AgentEnvConfig{
+				Role: "raider", Warband: "myrig", AgentName: "Toast", TownRoot: "/encampment",
+				RelicsNoDaemon: true,
+			},
+			want: map[string]string{
+				"HD_ROLE": "raider", "HD_WARBAND": "myrig", "HD_RAIDER": "Toast",
+				"BD_ACTOR": "myrig/raiders/Toast", "GIT_AUTHOR_NAME": "Toast",
+				"RELICS_AGENT_NAME": "myrig/Toast", "RELICS_NO_DAEMON": "1",
+				"HD_ROOT": "/encampment",
+			},
+		},
+		{
+			name: "clan",
+			cfg: AgentEnvConfig{
+				Role: "clan", Warband: "myrig", AgentName: "max", TownRoot: "/encampment",
+				RuntimeConfigDir: "/home/user/.claude-accounts/work", SessionIDEnv: "CLAUDE_SESSION_ID",
+			},
+			want: map[string]string{
+				"HD_ROLE": "clan", "HD_WARBAND": "myrig", "HD_CLAN": "max",
+				"BD_ACTOR": "myrig/clan/max", "GIT_AUTHOR_NAME": "max",
+				"RELICS_AGENT_NAME": "myrig/max", "HD_ROOT": "/encampment",
+				"CLAUDE_CONFIG_DIR": "/home/user/.claude-accounts/work", "HD_SESSION_ID_ENV": "CLAUDE_SESSION_ID",
+			},
+		},
+		{
+			name: "unknown role",
+			cfg:  AgentEnvConfig{Role: "stranger", Warband: "myrig", AgentName: "x"},
+			want: map[string]string{"HD_ROLE": "stranger"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := AgentEnv(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AgentEnv(%+v)\n got %v\nwant %v", tt.cfg, got, tt.want)
+			}
+			if a, b := ExportPrefix(got), ExportPrefix(AgentEnv(tt.cfg)); a != b {
+				t.Errorf("ExportPrefix not deterministic:\n%s\n%s", a, b)
+			}
+		})
+	}
+}
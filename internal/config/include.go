@@ -0,0 +1,236 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrIncludeCycle indicates settings files that include each other.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// settingsInclude is the shape of a file referenced by a settings "include"
+// directive. Only agent definitions are shared; included files may include
+// further files.
+type settingsInclude struct {
+	Include    []string                  `json:"include,omitempty"`
+	Agents     map[string]*RuntimeConfig `json:"agents,omitempty"`
+	RoleAgents map[string]string         `json:"role_agents,omitempty"`
+}
+
+// includedEntries records the agent entries merged in from includes, so they
+// can be left out again when the settings are saved.
+type includedEntries struct {
+	agents     map[string]*RuntimeConfig
+	roleAgents map[string]string
+}
+
+// loadIncludes reads the include files listed by the settings file at path,
+// resolving each relative to the file that names it. Later includes override
+// earlier ones. Missing files and cycles are errors.
+func loadIncludes(path string, include []string) (*includedEntries, error) {
+	inc := &includedEntries{
+		agents:     make(map[string]*RuntimeConfig),
+		roleAgents: make(map[string]string),
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving settings path: %w", err)
+	}
+	if err := inc.load(abs, include, []string{abs}); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (inc *includedEntries) load(from string, include []string, stack []string) error {
+	for _, rel := range include {
+		path := rel
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(from), rel)
+		}
+		path = filepath.Clean(path)
+
+		for _, seen := range stack {
+			if seen == path {
+				return fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, path), " -> "))
+			}
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path comes from the encampment's own settings
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("%w: include %q in %s", ErrNotFound, rel, from)
+			}
+			return fmt.Errorf("reading include %s: %w", path, err)
+		}
+
+		var file settingsInclude
+		if err := unmarshalConfig(data, &file); err != nil {
+			return fmt.Errorf("parsing include %s: %w", path, err)
+		}
+
+		// Nested includes first, so this file's own entries win over them.
+		if err := inc.load(path, file.Include, append(stack, path)); err != nil {
+			return err
+		}
+		for name, rc := range file.Agents {
+			inc.agents[name] = rc
+		}
+		for role, name := range file.RoleAgents {
+			inc.roleAgents[role] = name
+		}
+	}
+	return nil
+}
+
+// merge adds included entries to agents and roleAgents without overriding
+// local entries. The maps are allocated if needed.
+func (inc *includedEntries) merge(agents *map[string]*RuntimeConfig, roleAgents *map[string]string) {
+	if len(inc.agents) > 0 && *agents == nil {
+		*agents = make(map[string]*RuntimeConfig)
+	}
+	for name, rc := range inc.agents {
+		if _, ok := (*agents)[name]; !ok {
+			(*agents)[name] = rc
+		}
+	}
+	if len(inc.roleAgents) > 0 && *roleAgents == nil {
+		*roleAgents = make(map[string]string)
+	}
+	for role, name := range inc.roleAgents {
+		if _, ok := (*roleAgents)[role]; !ok {
+			(*roleAgents)[role] = name
+		}
+	}
+}
+
+// strip returns copies of agents and roleAgents without the entries that
+// still hold their included values. Entries changed since loading are kept.
+func (inc *includedEntries) strip(agents map[string]*RuntimeConfig, roleAgents map[string]string) (map[string]*RuntimeConfig, map[string]string) {
+	var outAgents map[string]*RuntimeConfig
+	for name, rc := range agents {
+		if inc.agents[name] == rc {
+			continue
+		}
+		if outAgents == nil {
+			outAgents = make(map[string]*RuntimeConfig)
+		}
+		outAgents[name] = rc
+	}
+	var outRoles map[string]string
+	for role, name := range roleAgents {
+		if included, ok := inc.roleAgents[role]; ok && included == name {
+			continue
+		}
+		if outRoles == nil {
+			outRoles = make(map[string]string)
+		}
+		outRoles[role] = name
+	}
+	return outAgents, outRoles
+}
+
+// applyIncludes merges the settings' include files into its agents.
+func (c *TownSettings) applyIncludes(path string) error {
+	if len(c.Include) == 0 {
+		return nil
+	}
+	inc, err := loadIncludes(path, c.Include)
+	if err != nil {
+		return err
+	}
+	inc.merge(&c.Agents, &c.RoleAgents)
+	c.included = inc
+	return nil
+}
+
+// applyIncludes merges the settings' include files into its agents.
+func (c *RigSettings) applyIncludes(path string) error {
+	if len(c.Include) == 0 {
+		return nil
+	}
+	inc, err := loadIncludes(path, c.Include)
+	if err != nil {
+		return err
+	}
+	inc.merge(&c.Agents, &c.RoleAgents)
+	c.included = inc
+	return nil
+}
+
+// forValidation returns settings with includes applied, for validating
+// settings built in memory rather than loaded. c itself is not modified.
+func (c *TownSettings) forValidation(path string) (*TownSettings, error) {
+	if len(c.Include) == 0 || c.included != nil {
+		return c, nil
+	}
+	v := *c
+	v.Agents = copyAgents(c.Agents)
+	v.RoleAgents = copyRoleAgents(c.RoleAgents)
+	if err := v.applyIncludes(path); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// forValidation returns settings with includes applied, for validating
+// settings built in memory rather than loaded. c itself is not modified.
+func (c *RigSettings) forValidation(path string) (*RigSettings, error) {
+	if len(c.Include) == 0 || c.included != nil {
+		return c, nil
+	}
+	v := *c
+	v.Agents = copyAgents(c.Agents)
+	v.RoleAgents = copyRoleAgents(c.RoleAgents)
+	if err := v.applyIncludes(path); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// forSave returns the settings as they should be written: entries merged in
+// from includes are left out so they stay defined in one place.
+func (c *TownSettings) forSave() *TownSettings {
+	if c.included == nil {
+		return c
+	}
+	out := *c
+	out.Agents, out.RoleAgents = c.included.strip(c.Agents, c.RoleAgents)
+	return &out
+}
+
+// forSave returns the settings as they should be written: entries merged in
+// from includes are left out so they stay defined in one place.
+func (c *RigSettings) forSave() *RigSettings {
+	if c.included == nil {
+		return c
+	}
+	out := *c
+	out.Agents, out.RoleAgents = c.included.strip(c.Agents, c.RoleAgents)
+	return &out
+}
+
+func copyAgents(m map[string]*RuntimeConfig) map[string]*RuntimeConfig {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]*RuntimeConfig, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func copyRoleAgents(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJSON(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTownSettingsInclude(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeJSON(t, filepath.Join(dir, "shared-agents.json"), `{
+		"agents": {
+			"fast": {"command": "claude", "args": ["--model", "haiku"]},
+			"deep": {"command": "claude", "args": ["--model", "opus"]}
+		},
+		"role_agents": {"witness": "fast", "warchief": "deep"}
+	}`)
+	path := filepath.Join(dir, "settings", "config.json")
+	writeJSON(t, path, `{
+		"type": "encampment-settings",
+		"version": 1,
+		"include": ["../shared-agents.json"],
+		"agents": {"deep": {"command": "claude", "args": ["--model", "sonnet"]}},
+		"role_agents": {"warchief": "fast"}
+	}`)
+
+	settings, err := LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	if settings.Agents["fast"] == nil {
+		t.Fatal("included agent 'fast' not merged")
+	}
+	if got := settings.Agents["deep"].Args; len(got) != 2 || got[1] != "sonnet" {
+		t.Errorf("deep args = %v, want local definition to win", got)
+	}
+	if settings.RoleAgents["witness"] != "fast" || settings.RoleAgents["warchief"] != "fast" {
+		t.Errorf("role_agents = %v, want witness=fast (included), warchief=fast (local)", settings.RoleAgents)
+	}
+
+	// Saving keeps included entries out of the file.
+	settings.RoleAgents["raider"] = "deep"
+	if err := SaveTownSettings(path, settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "haiku") || strings.Contains(string(data), `"witness"`) {
+		t.Errorf("saved settings contain included entries:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"raider"`) {
+		t.Errorf("saved settings lost local change:\n%s", data)
+	}
+}
+
+func TestRigSettingsIncludeNested(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeJSON(t, filepath.Join(dir, "base.json"), `{"agents": {"fast": {"command": "claude"}}}`)
+	writeJSON(t, filepath.Join(dir, "shared", "agents.json"), `{"include": ["../base.json"], "role_agents": {"raider": "fast"}}`)
+	path := filepath.Join(dir, "warband", "settings", "config.json")
+	writeJSON(t, path, `{"type": "warband-settings", "version": 1, "include": ["../../shared/agents.json"]}`)
+
+	settings, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatalf("LoadRigSettings: %v", err)
+	}
+	if settings.Agents["fast"] == nil || settings.RoleAgents["raider"] != "fast" {
+		t.Errorf("nested include not merged: agents=%v role_agents=%v", settings.Agents, settings.RoleAgents)
+	}
+}
+
+func TestSettingsIncludeErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		writeJSON(t, path, `{"type": "warband-settings", "version": 1, "include": ["nope.json"]}`)
+		if _, err := LoadRigSettings(path); !errors.Is(err, ErrNotFound) {
+			t.Errorf("error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		dir := t.TempDir()
+		writeJSON(t, filepath.Join(dir, "a.json"), `{"include": ["b.json"]}`)
+		writeJSON(t, filepath.Join(dir, "b.json"), `{"include": ["a.json"]}`)
+		path := filepath.Join(dir, "config.json")
+		writeJSON(t, path, `{"type": "encampment-settings", "version": 1, "include": ["a.json"]}`)
+		if _, err := LoadOrCreateTownSettings(path); !errors.Is(err, ErrIncludeCycle) {
+			t.Errorf("error = %v, want ErrIncludeCycle", err)
+		}
+	})
+
+	t.Run("self include", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		writeJSON(t, path, `{"type": "warband-settings", "version": 1, "include": ["config.json"]}`)
+		if _, err := LoadRigSettings(path); !errors.Is(err, ErrIncludeCycle) {
+			t.Errorf("error = %v, want ErrIncludeCycle", err)
+		}
+	})
+}
@@ -39,7 +39,7 @@ func LoadTownConfig(path string) (*TownConfig, error) {
 	}
 
 	var config TownConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -83,7 +83,7 @@ func LoadRigsConfig(path string) (*RigsConfig, error) {
 	}
 
 	var config RigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -152,7 +152,7 @@ func LoadRigConfig(path string) (*RigConfig, error) {
 	}
 
 	var config RigConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -212,9 +212,52 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if err := validateRoleAgents(c.RoleAgents); err != nil {
+		return err
+	}
 	return nil
 }
 
+// ErrUnknownRole indicates a role_agents key that isn't a known role.
+var ErrUnknownRole = errors.New("unknown role")
+
+// knownRoles is the set of role names accepted as role_agents keys.
+var knownRoles = []string{
+	constants.RoleWarchief,
+	constants.RoleShaman,
+	constants.RoleWitness,
+	constants.RoleForge,
+	constants.RoleRaider,
+	constants.RoleCrew,
+}
+
+// isKnownRole returns true if role is one of the known agent roles.
+func isKnownRole(role string) bool {
+	for _, r := range knownRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoleAgents checks that every role_agents key is a known role.
+// A misspelled key would otherwise silently never apply.
+func validateRoleAgents(roleAgents map[string]string) error {
+	var unknown []string
+	for role := range roleAgents {
+		if !isKnownRole(role) {
+			unknown = append(unknown, role)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("%w in role_agents: %s (valid roles: %s)",
+		ErrUnknownRole, strings.Join(unknown, ", "), strings.Join(knownRoles, ", "))
+}
+
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
@@ -244,6 +287,46 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	return nil
 }
 
+// ResolveMergeQueueConfig returns the effective merge queue config for a warband.
+// on_conflict, poll_interval, max_concurrent, and retry_flaky_tests are layered
+// field by field: warband settings, then encampment settings, then
+// DefaultMergeQueueConfig, with empty or zero values falling through to the
+// next layer. The remaining fields come from the most specific layer that
+// defines a merge_queue section. Either settings argument may be nil.
+func ResolveMergeQueueConfig(town *TownSettings, rig *RigSettings) (*MergeQueueConfig, error) {
+	layers := []*MergeQueueConfig{DefaultMergeQueueConfig()}
+	if town != nil && town.MergeQueue != nil {
+		layers = append(layers, town.MergeQueue)
+	}
+	if rig != nil && rig.MergeQueue != nil {
+		layers = append(layers, rig.MergeQueue)
+	}
+
+	// Start from the most specific layer, then fill layered fields from
+	// less specific ones.
+	merged := *layers[len(layers)-1]
+	for i := len(layers) - 2; i >= 0; i-- {
+		l := layers[i]
+		if merged.OnConflict == "" {
+			merged.OnConflict = l.OnConflict
+		}
+		if merged.PollInterval == "" {
+			merged.PollInterval = l.PollInterval
+		}
+		if merged.MaxConcurrent == 0 {
+			merged.MaxConcurrent = l.MaxConcurrent
+		}
+		if merged.RetryFlakyTests == 0 {
+			merged.RetryFlakyTests = l.RetryFlakyTests
+		}
+	}
+
+	if err := validateMergeQueueConfig(&merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
 // NewRigConfig creates a new RigConfig (identity only).
 func NewRigConfig(name, gitURL string) *RigConfig {
 	return &RigConfig{
@@ -275,10 +358,14 @@ func LoadRigSettings(path string) (*RigSettings, error) {
 	}
 
 	var settings RigSettings
-	if err := json.Unmarshal(data, &settings); err != nil {
+	if err := unmarshalConfig(data, &settings); err != nil {
 		return nil, fmt.Errorf("parsing settings: %w", err)
 	}
 
+	if err := settings.applyIncludes(path); err != nil {
+		return nil, err
+	}
+
 	if err := validateRigSettings(&settings); err != nil {
 		return nil, err
 	}
@@ -288,7 +375,8 @@ func LoadRigSettings(path string) (*RigSettings, error) {
 
 // SaveRigSettings saves warband settings to a file.
 func SaveRigSettings(path string, settings *RigSettings) error {
-	if err := validateRigSettings(settings); err != nil {
+	data, err := encodeRigSettings(path, settings)
+	if err != nil {
 		return err
 	}
 
@@ -296,11 +384,6 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding settings: %w", err)
-	}
-
 	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
 		return fmt.Errorf("writing settings: %w", err)
 	}
@@ -308,6 +391,24 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 	return nil
 }
 
+// encodeRigSettings validates settings as they would load from path and
+// returns the JSON to write there.
+func encodeRigSettings(path string, settings *RigSettings) ([]byte, error) {
+	merged, err := settings.forValidation(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRigSettings(merged); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(settings.forSave(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding settings: %w", err)
+	}
+	return data, nil
+}
+
 // LoadWarchiefConfig loads and validates a warchief config file.
 func LoadWarchiefConfig(path string) (*WarchiefConfig, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
@@ -319,7 +420,7 @@ func LoadWarchiefConfig(path string) (*WarchiefConfig, error) {
 	}
 
 	var config WarchiefConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -387,7 +488,7 @@ func LoadDaemonPatrolConfig(path string) (*DaemonPatrolConfig, error) {
 	}
 
 	var config DaemonPatrolConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing daemon scout config: %w", err)
 	}
 
@@ -427,6 +528,30 @@ func validateDaemonPatrolConfig(c *DaemonPatrolConfig) error {
 	if c.Version > CurrentDaemonPatrolConfigVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentDaemonPatrolConfigVersion)
 	}
+	if c.Heartbeat != nil && c.Heartbeat.Interval != "" {
+		if err := validatePositiveDuration(c.Heartbeat.Interval); err != nil {
+			return fmt.Errorf("invalid heartbeat interval: %w", err)
+		}
+	}
+	for name, scout := range c.Patrols {
+		if scout.Interval != "" {
+			if err := validatePositiveDuration(scout.Interval); err != nil {
+				return fmt.Errorf("invalid interval for scout '%s': %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePositiveDuration checks that s parses as a duration greater than zero.
+func validatePositiveDuration(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", s)
+	}
 	return nil
 }
 
@@ -442,6 +567,49 @@ func EnsureDaemonPatrolConfig(townRoot string) error {
 	return nil
 }
 
+// IsHeartbeatEnabled reports whether the daemon heartbeat is enabled.
+// Returns true if no heartbeat section is configured.
+func (c *DaemonPatrolConfig) IsHeartbeatEnabled() bool {
+	if c.Heartbeat == nil {
+		return true
+	}
+	return c.Heartbeat.Enabled
+}
+
+// GetHeartbeatInterval returns the heartbeat interval as a time.Duration.
+// Returns 3 minutes if not configured or invalid.
+func (c *DaemonPatrolConfig) GetHeartbeatInterval() time.Duration {
+	if c.Heartbeat == nil {
+		return 3 * time.Minute
+	}
+	return parsePositiveDuration(c.Heartbeat.Interval, 3*time.Minute)
+}
+
+// IsPatrolEnabled reports whether the named scout is configured and enabled.
+// Scouts absent from the config are disabled.
+func (c *DaemonPatrolConfig) IsPatrolEnabled(name string) bool {
+	scout, ok := c.Patrols[name]
+	return ok && scout.Enabled
+}
+
+// GetPatrolInterval returns the named scout's interval as a time.Duration.
+// Returns 5 minutes if the scout or its interval is not configured or invalid.
+func (c *DaemonPatrolConfig) GetPatrolInterval(name string) time.Duration {
+	return parsePositiveDuration(c.Patrols[name].Interval, 5*time.Minute)
+}
+
+// parsePositiveDuration parses s, returning def if s is empty, invalid, or not positive.
+func parsePositiveDuration(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
 // LoadAccountsConfig loads and validates an accounts configuration file.
 func LoadAccountsConfig(path string) (*AccountsConfig, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
@@ -453,7 +621,7 @@ func LoadAccountsConfig(path string) (*AccountsConfig, error) {
 	}
 
 	var config AccountsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing accounts config: %w", err)
 	}
 
@@ -500,6 +668,12 @@ func validateAccountsConfig(c *AccountsConfig) error {
 			return fmt.Errorf("%w: default account '%s' not found in accounts", ErrMissingField, c.Default)
 		}
 	}
+	switch c.Strategy {
+	case "", AccountStrategyDefault, AccountStrategyRoundRobin, AccountStrategyLRU:
+	default:
+		return fmt.Errorf("%w: got '%s', want '%s', '%s' or '%s'", ErrInvalidAccountStrategy,
+			c.Strategy, AccountStrategyDefault, AccountStrategyRoundRobin, AccountStrategyLRU)
+	}
 	// Validate each account has required fields
 	for handle, acct := range c.Accounts {
 		if acct.ConfigDir == "" {
@@ -537,7 +711,8 @@ func (c *AccountsConfig) GetDefaultAccount() *Account {
 // Priority order:
 //  1. HD_ACCOUNT environment variable
 //  2. accountFlag (from --account command flag)
-//  3. Default account from config
+//  3. Warband account (RigSettings.Account; see ResolveRigAccountConfigDir)
+//  4. Default account from config
 //
 // Returns empty string if no account configured or resolved.
 // Returns the handle that was resolved as second value.
@@ -549,6 +724,26 @@ func ResolveAccountConfigDir(accountsPath, accountFlag string) (configDir, handl
 		return "", "", nil
 	}
 
+	return resolveAccount(cfg, accountFlag, "")
+}
+
+// RigAccount returns the account handle the warband at rigPath selects in
+// its settings, or "" if it doesn't select one (or its settings can't be
+// loaded, in which case the encampment's accounts apply).
+func RigAccount(rigPath string) string {
+	if rigPath == "" {
+		return ""
+	}
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return ""
+	}
+	return settings.Account
+}
+
+// resolveAccount applies ResolveAccountConfigDir's priority order to a loaded
+// accounts config. rigAccount is the warband's account, or "" for none.
+func resolveAccount(cfg *AccountsConfig, accountFlag, rigAccount string) (configDir, handle string, err error) {
 	// Priority 1: HD_ACCOUNT env var
 	if envAccount := os.Getenv("HD_ACCOUNT"); envAccount != "" {
 		acct := cfg.GetAccount(envAccount)
@@ -567,7 +762,16 @@ func ResolveAccountConfigDir(accountsPath, accountFlag string) (configDir, handl
 		return expandPath(acct.ConfigDir), accountFlag, nil
 	}
 
-	// Priority 3: Default account
+	// Priority 3: Warband account
+	if rigAccount != "" {
+		acct := cfg.GetAccount(rigAccount)
+		if acct == nil {
+			return "", "", fmt.Errorf("warband account '%s' not found in accounts config", rigAccount)
+		}
+		return expandPath(acct.ConfigDir), rigAccount, nil
+	}
+
+	// Priority 4: Default account
 	if cfg.Default != "" {
 		acct := cfg.GetDefaultAccount()
 		if acct != nil {
@@ -600,7 +804,7 @@ func LoadMessagingConfig(path string) (*MessagingConfig, error) {
 	}
 
 	var config MessagingConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing messaging config: %w", err)
 	}
 
@@ -751,26 +955,89 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	}
 
 	var settings TownSettings
-	if err := json.Unmarshal(data, &settings); err != nil {
+	if err := unmarshalConfig(data, &settings); err != nil {
+		return nil, err
+	}
+	if err := settings.applyIncludes(path); err != nil {
 		return nil, err
 	}
+	if err := validateTownSettings(&settings); err != nil {
+		return nil, err
+	}
+	// An unresolvable agent name only affects the roles that use it, and
+	// resolution falls back past it, so don't discard the rest of the file.
+	if err := validateTownAgents(&settings, filepath.Dir(path)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s: %v\n", path, err)
+	}
 	return &settings, nil
 }
 
+// ErrUnknownAgent indicates a default_agent or role_agents value that doesn't
+// name an encampment custom agent or a registered preset.
+var ErrUnknownAgent = errors.New("unknown agent")
+
+// validateTownSettings validates a TownSettings' type, version, role_agents
+// keys, and merge queue. Agent names are checked by validateTownAgents.
+func validateTownSettings(c *TownSettings) error {
+	if c.Type != "encampment-settings" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'encampment-settings', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentTownSettingsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentTownSettingsVersion)
+	}
+	if err := validateRoleAgents(c.RoleAgents); err != nil {
+		return err
+	}
+	if c.MergeQueue != nil {
+		if err := validateMergeQueueConfig(c.MergeQueue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTownAgents checks that DefaultAgent and every RoleAgents value
+// name an encampment custom agent or a registered preset. SaveTownSettings
+// rejects settings that fail it; LoadOrCreateTownSettings only warns.
+// settingsDir is the directory holding the settings file; its agents.json
+// registry (if any) is loaded so registry-defined agents resolve.
+func validateTownAgents(c *TownSettings, settingsDir string) error {
+	_ = LoadAgentRegistry(filepath.Join(settingsDir, "agents.json"))
+
+	if c.DefaultAgent != "" && lookupAgentConfigIfExists(c.DefaultAgent, c, nil) == nil {
+		return fmt.Errorf("%w in default_agent: %s", ErrUnknownAgent, c.DefaultAgent)
+	}
+	var unknown []string
+	for role, name := range c.RoleAgents {
+		if name != "" && lookupAgentConfigIfExists(name, c, nil) == nil {
+			unknown = append(unknown, role+"="+name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w in role_agents: %s", ErrUnknownAgent, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
 // SaveTownSettings saves encampment settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
-	if settings.Type != "encampment-settings" && settings.Type != "" {
-		return fmt.Errorf("%w: expected type 'encampment-settings', got '%s'", ErrInvalidType, settings.Type)
+	merged, err := settings.forValidation(path)
+	if err != nil {
+		return err
 	}
-	if settings.Version > CurrentTownSettingsVersion {
-		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
+	if err := validateTownSettings(merged); err != nil {
+		return err
+	}
+	if err := validateTownAgents(merged, filepath.Dir(path)); err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	data, err := json.MarshalIndent(settings.forSave(), "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
@@ -956,7 +1223,21 @@ func lookupAgentConfigIfExists(name string, townSettings *TownSettings, rigSetti
 // townRoot is the path to the encampment directory (e.g., ~/horde).
 // rigPath is the path to the warband directory (e.g., ~/horde/horde), or empty for encampment-level roles.
 func ResolveRoleAgentConfig(role, townRoot, rigPath string) *RuntimeConfig {
-	// Load warband settings (may be nil for encampment-level roles like warchief/shaman)
+	townSettings, rigSettings := loadRoleAgentSettings(townRoot, rigPath)
+	rc, trace := resolveRoleAgent(role, townSettings, rigSettings)
+	for _, step := range trace.Steps {
+		if reason, ok := strings.CutPrefix(step.Result, "skipped: "); ok {
+			fmt.Fprintf(os.Stderr, "warning: role_agents[%s]=%s - %s, falling back to default\n", role, step.Value, reason)
+		}
+	}
+	return rc
+}
+
+// ResolveRoleAgentName returns the agent name that would be used for a specific role.
+// This is useful for logging and diagnostics.
+// Returns the agent name and whether it came from role-specific configuration.
+func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isRoleSpecific bool) {
+	// Load warband settings
 	var rigSettings *RigSettings
 	if rigPath != "" {
 		var err error
@@ -972,43 +1253,73 @@ func ResolveRoleAgentConfig(role, townRoot, rigPath string) *RuntimeConfig {
 		townSettings = NewTownSettings()
 	}
 
-	// Load custom agent registries
-	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
-	if rigPath != "" {
-		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
-	}
-
 	// Check warband's RoleAgents first
 	if rigSettings != nil && rigSettings.RoleAgents != nil {
-		if agentName, ok := rigSettings.RoleAgents[role]; ok && agentName != "" {
-			if err := ValidateAgentConfig(agentName, townSettings, rigSettings); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: role_agents[%s]=%s - %v, falling back to default\n", role, agentName, err)
-			} else {
-				return lookupAgentConfig(agentName, townSettings, rigSettings)
-			}
+		if name, ok := rigSettings.RoleAgents[role]; ok && name != "" {
+			return name, true
 		}
 	}
 
 	// Check encampment's RoleAgents
 	if townSettings.RoleAgents != nil {
-		if agentName, ok := townSettings.RoleAgents[role]; ok && agentName != "" {
-			if err := ValidateAgentConfig(agentName, townSettings, rigSettings); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: role_agents[%s]=%s - %v, falling back to default\n", role, agentName, err)
-			} else {
-				return lookupAgentConfig(agentName, townSettings, rigSettings)
-			}
+		if name, ok := townSettings.RoleAgents[role]; ok && name != "" {
+			return name, true
 		}
 	}
 
-	// Fall back to existing resolution (warband's Agent → encampment's DefaultAgent → "claude")
-	return ResolveAgentConfig(townRoot, rigPath)
+	// Fall back to existing resolution
+	if rigSettings != nil && rigSettings.Agent != "" {
+		return rigSettings.Agent, false
+	}
+	if townSettings.DefaultAgent != "" {
+		return townSettings.DefaultAgent, false
+	}
+	return "claude", false
+}
+
+// ResolutionStep records one source consulted while resolving an agent.
+type ResolutionStep struct {
+	Source string `json:"source"`          // e.g., "warband role_agents[raider]"
+	Value  string `json:"value,omitempty"` // agent name or setting found there (empty if unset)
+	Result string `json:"result"`          // "unset", "selected", "skipped: <reason>", or "not reached"
 }
 
-// ResolveRoleAgentName returns the agent name that would be used for a specific role.
-// This is useful for logging and diagnostics.
-// Returns the agent name and whether it came from role-specific configuration.
-func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isRoleSpecific bool) {
-	// Load warband settings
+// ResolutionTrace explains how ResolveRoleAgentConfig picks an agent for a role.
+type ResolutionTrace struct {
+	Role      string           `json:"role"`
+	Steps     []ResolutionStep `json:"steps"`                // Sources in the order they are consulted
+	Winner    string           `json:"winner"`               // Source of the step that was selected
+	AgentName string           `json:"agent,omitempty"`      // Selected agent (empty when warband runtime is used directly)
+	DefinedIn string           `json:"defined_in,omitempty"` // Where the agent's definition came from
+	Runtime   *RuntimeConfig   `json:"runtime"`
+
+	// Conflict is set when the warband's role_agents entry for the role
+	// shadows a different encampment entry.
+	Conflict *RoleAgentConflict `json:"conflict,omitempty"`
+}
+
+// ExplainAgentResolution traces agent resolution for a role, following the same
+// precedence as ResolveRoleAgentConfig, and reports every source consulted,
+// which one won, and the final RuntimeConfig.
+//
+// role is one of: "warchief", "shaman", "witness", "forge", "raider", "clan".
+// townRoot is the path to the encampment directory (e.g., ~/horde).
+// rigPath is the path to the warband directory, or empty for encampment-level roles.
+func ExplainAgentResolution(role, townRoot, rigPath string) ResolutionTrace {
+	townSettings, rigSettings := loadRoleAgentSettings(townRoot, rigPath)
+	_, trace := resolveRoleAgent(role, townSettings, rigSettings)
+	for _, conflict := range roleAgentConflicts(townSettings, rigSettings) {
+		if conflict.Role == role {
+			trace.Conflict = &conflict
+		}
+	}
+	return trace
+}
+
+// loadRoleAgentSettings loads the settings and custom agent registries that
+// role agent resolution consults. rigSettings is nil for encampment-level
+// roles and when the warband settings can't be loaded.
+func loadRoleAgentSettings(townRoot, rigPath string) (*TownSettings, *RigSettings) {
 	var rigSettings *RigSettings
 	if rigPath != "" {
 		var err error
@@ -1018,34 +1329,175 @@ func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isR
 		}
 	}
 
-	// Load encampment settings
 	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
 	if err != nil {
 		townSettings = NewTownSettings()
 	}
 
-	// Check warband's RoleAgents first
-	if rigSettings != nil && rigSettings.RoleAgents != nil {
-		if name, ok := rigSettings.RoleAgents[role]; ok && name != "" {
-			return name, true
+	// Load custom agent registries
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	if rigPath != "" {
+		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
+	}
+	return townSettings, rigSettings
+}
+
+// resolveRoleAgent applies the role agent precedence shared by
+// ResolveRoleAgentConfig and ExplainAgentResolution. It returns the selected
+// RuntimeConfig and a trace of every source consulted; role_agents entries
+// that fail validation are recorded as skipped.
+func resolveRoleAgent(role string, townSettings *TownSettings, rigSettings *RigSettings) (*RuntimeConfig, ResolutionTrace) {
+	trace := ResolutionTrace{Role: role}
+
+	decided := false
+	consider := func(source, value string, selectable bool, reason string) bool {
+		step := ResolutionStep{Source: source, Value: value}
+		switch {
+		case decided:
+			step.Result = "not reached"
+		case value == "":
+			step.Result = "unset"
+		case !selectable:
+			step.Result = "skipped: " + reason
+		default:
+			step.Result = "selected"
+			trace.Winner = source
+			decided = true
 		}
+		trace.Steps = append(trace.Steps, step)
+		return step.Result == "selected"
 	}
 
-	// Check encampment's RoleAgents
-	if townSettings.RoleAgents != nil {
-		if name, ok := townSettings.RoleAgents[role]; ok && name != "" {
-			return name, true
+	roleAgent := func(source string, roleAgents map[string]string) {
+		name := roleAgents[role]
+		reason := ""
+		ok := true
+		if name != "" && !decided {
+			if err := ValidateAgentConfig(name, townSettings, rigSettings); err != nil {
+				ok, reason = false, err.Error()
+			}
+		}
+		if consider(source, name, ok, reason) {
+			trace.AgentName = name
 		}
 	}
 
-	// Fall back to existing resolution
-	if rigSettings != nil && rigSettings.Agent != "" {
-		return rigSettings.Agent, false
+	// 1-2. Role-specific assignments (warband, then encampment)
+	var rigRoleAgents map[string]string
+	if rigSettings != nil {
+		rigRoleAgents = rigSettings.RoleAgents
 	}
-	if townSettings.DefaultAgent != "" {
-		return townSettings.DefaultAgent, false
+	roleAgent(fmt.Sprintf("warband role_agents[%s]", role), rigRoleAgents)
+	roleAgent(fmt.Sprintf("encampment role_agents[%s]", role), townSettings.RoleAgents)
+
+	// 3. Warband runtime set directly (backwards compatibility)
+	runtimeValue := ""
+	if rigSettings != nil && rigSettings.Runtime != nil {
+		runtimeValue = rigSettings.Runtime.Command
+		if runtimeValue == "" {
+			runtimeValue = "(runtime block)"
+		}
 	}
-	return "claude", false
+	if consider("warband runtime", runtimeValue, true, "") {
+		trace.DefinedIn = "warband runtime"
+		trace.Runtime = fillRuntimeDefaults(rigSettings.Runtime)
+	}
+
+	// 4-6. Warband agent → encampment default_agent → built-in fallback
+	rigAgent := ""
+	if rigSettings != nil {
+		rigAgent = rigSettings.Agent
+	}
+	if consider("warband agent", rigAgent, true, "") {
+		trace.AgentName = rigAgent
+	}
+	if consider("encampment default_agent", townSettings.DefaultAgent, true, "") {
+		trace.AgentName = townSettings.DefaultAgent
+	}
+	if consider("built-in default", "claude", true, "") {
+		trace.AgentName = "claude"
+	}
+
+	if trace.Runtime == nil {
+		trace.DefinedIn = agentDefinitionSource(trace.AgentName, townSettings, rigSettings)
+		trace.Runtime = lookupAgentConfig(trace.AgentName, townSettings, rigSettings)
+	}
+	return trace.Runtime, trace
+}
+
+// RoleAgentConflict is a role assigned to different agents by the encampment
+// and warband role_agents. The warband's assignment shadows the encampment's,
+// so edits to the encampment setting have no effect for that warband.
+type RoleAgentConflict struct {
+	Role      string `json:"role"`
+	TownAgent string `json:"town_agent"`
+	RigAgent  string `json:"rig_agent"`
+	// Winner is the level whose agent ResolveRoleAgentConfig uses: "warband",
+	// or "encampment" if the warband's agent is invalid and the encampment's
+	// isn't. Empty if neither is valid.
+	Winner string `json:"winner"`
+}
+
+// FindRoleAgentConflicts reports the roles for which rigPath's role_agents
+// shadow a different encampment role_agents entry, sorted by role. It only
+// reports; resolution is unchanged.
+func FindRoleAgentConflicts(townRoot, rigPath string) []RoleAgentConflict {
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
+
+	return roleAgentConflicts(townSettings, rigSettings)
+}
+
+// roleAgentConflicts compares the role_agents of already-loaded settings.
+func roleAgentConflicts(townSettings *TownSettings, rigSettings *RigSettings) []RoleAgentConflict {
+	if townSettings == nil || rigSettings == nil {
+		return nil
+	}
+
+	var conflicts []RoleAgentConflict
+	for role, rigAgent := range rigSettings.RoleAgents {
+		townAgent := townSettings.RoleAgents[role]
+		if rigAgent == "" || townAgent == "" || rigAgent == townAgent {
+			continue
+		}
+		conflict := RoleAgentConflict{Role: role, TownAgent: townAgent, RigAgent: rigAgent}
+		switch {
+		case ValidateAgentConfig(rigAgent, townSettings, rigSettings) == nil:
+			conflict.Winner = "warband"
+		case ValidateAgentConfig(townAgent, townSettings, rigSettings) == nil:
+			conflict.Winner = "encampment"
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Role < conflicts[j].Role })
+	return conflicts
+}
+
+// agentDefinitionSource reports which layer lookupAgentConfig would take
+// the named agent's definition from.
+func agentDefinitionSource(name string, townSettings *TownSettings, rigSettings *RigSettings) string {
+	if rigSettings != nil && rigSettings.Agents != nil {
+		if custom, ok := rigSettings.Agents[name]; ok && custom != nil {
+			return "warband agents"
+		}
+	}
+	if townSettings != nil && townSettings.Agents != nil {
+		if custom, ok := townSettings.Agents[name]; ok && custom != nil {
+			return "encampment agents"
+		}
+	}
+	if preset := GetAgentPresetByName(name); preset != nil {
+		return "preset"
+	}
+	return "default (agent not found)"
 }
 
 // lookupAgentConfig looks up an agent by name.
@@ -1083,7 +1535,9 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 	result := &RuntimeConfig{
 		Command:       rc.Command,
 		Args:          rc.Args,
+		Env:           rc.Env,
 		InitialPrompt: rc.InitialPrompt,
+		PromptFile:    rc.PromptFile,
 	}
 	if result.Command == "" {
 		result.Command = "claude"
@@ -1202,79 +1656,23 @@ func findTownRootFromCwd() (string, error) {
 
 // BuildStartupCommand builds a full startup command with environment exports.
 // envVars is a map of environment variable names to values.
-// rigPath is optional - if empty, tries to detect encampment root from cwd.
+// rigPath is optional - if empty, the encampment root is taken from envVars'
+// HD_ROOT, or detected from cwd.
 // prompt is optional - if provided, appended as the initial prompt.
 //
 // If envVars contains HD_ROLE, the function uses role-based agent resolution
 // (ResolveRoleAgentConfig) to select the appropriate agent for the role.
 // This enables per-role model selection via role_agents in settings.
+//
+// If the agent references a secret that can't be resolved, the returned
+// command prints the error and fails instead of starting the agent without
+// it. Use BuildStartupCommandWithAgentOverride to get the error instead.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
-	var rc *RuntimeConfig
-	var townRoot string
-
-	// Extract role from envVars for role-based agent resolution
-	role := envVars["HD_ROLE"]
-
-	if rigPath != "" {
-		// Derive encampment root from warband path
-		townRoot = filepath.Dir(rigPath)
-		if role != "" {
-			// Use role-based agent resolution for per-role model selection
-			rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
-		} else {
-			rc = ResolveAgentConfig(townRoot, rigPath)
-		}
-	} else {
-		// Try to detect encampment root from cwd for encampment-level agents (warchief, shaman)
-		var err error
-		townRoot, err = findTownRootFromCwd()
-		if err != nil {
-			rc = DefaultRuntimeConfig()
-		} else {
-			if role != "" {
-				// Use role-based agent resolution for per-role model selection
-				rc = ResolveRoleAgentConfig(role, townRoot, "")
-			} else {
-				rc = ResolveAgentConfig(townRoot, "")
-			}
-		}
-	}
-
-	// Copy env vars to avoid mutating caller map
-	resolvedEnv := make(map[string]string, len(envVars)+2)
-	for k, v := range envVars {
-		resolvedEnv[k] = v
-	}
-	// Add HD_ROOT so agents can find encampment-level resources (rituals, etc.)
-	if townRoot != "" {
-		resolvedEnv["HD_ROOT"] = townRoot
-	}
-	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
-		resolvedEnv["HD_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
-	}
-
-	// Build environment export prefix
-	var exports []string
-	for k, v := range resolvedEnv {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Sort for deterministic output
-	sort.Strings(exports)
-
-	var cmd string
-	if len(exports) > 0 {
-		cmd = "export " + strings.Join(exports, " ") + " && "
-	}
-
-	// Add runtime command
-	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
-	} else {
-		cmd += rc.BuildCommand()
+	env, rc, err := resolveStartupEnv(envVars, rigPath, "")
+	if err != nil {
+		return "echo " + quoteForShell("hd: "+err.Error()) + " >&2 && false"
 	}
-
-	return cmd
+	return startupCommand(env, rc, prompt)
 }
 
 // PrependEnv prepends export statements to a command string.
@@ -1285,7 +1683,7 @@ func PrependEnv(command string, envVars map[string]string) string {
 
 	var exports []string
 	for k, v := range envVars {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
+		exports = append(exports, k+"="+quoteEnvValue(v))
 	}
 
 	sort.Strings(exports)
@@ -1300,49 +1698,78 @@ func PrependEnv(command string, envVars map[string]string) string {
 //  2. role_agents[HD_ROLE] (if HD_ROLE is in envVars)
 //  3. Default agent resolution (warband's Agent → encampment's DefaultAgent → "claude")
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
-	var rc *RuntimeConfig
-	var townRoot string
+	env, rc, err := resolveStartupEnv(envVars, rigPath, agentOverride)
+	if err != nil {
+		return "", err
+	}
+	return startupCommand(env, rc, prompt), nil
+}
 
+// ResolveAgentEnv returns exactly the environment variables
+// BuildAgentStartupCommand exports for an agent: the AgentEnv variables for
+// role and warband, HD_ROOT, and HD_SESSION_ID_ENV if the agent resolved for
+// the role reports its session ID through the environment. Use it to inspect
+// an agent's environment without building its command.
+func ResolveAgentEnv(role, warband, townRoot, rigPath string) map[string]string {
+	envVars := AgentEnv(AgentEnvConfig{
+		Role:     role,
+		Warband:  warband,
+		TownRoot: townRoot,
+	})
+	env, _, _ := resolveStartupEnv(envVars, rigPath, "")
+	return env
+}
+
+// resolveStartupEnv resolves the agent a startup command runs and the
+// environment it exports: the agent's Env, a copy of envVars, HD_ROOT and
+// HD_SESSION_ID_ENV. The agent is agentOverride if set, else the role agent
+// for envVars' HD_ROLE, else the default agent. The encampment root is
+// rigPath's parent, else envVars' HD_ROOT, else detected from cwd.
+// Secret references in the agent's Args and Env are resolved here, and an
+// unresolvable one is an error wrapping ErrUnresolvedSecret.
+// This is the single source of the startup environment.
+func resolveStartupEnv(envVars map[string]string, rigPath, agentOverride string) (map[string]string, *RuntimeConfig, error) {
 	// Extract role from envVars for role-based agent resolution (when no override)
 	role := envVars["HD_ROLE"]
 
+	var townRoot string
 	if rigPath != "" {
+		// Derive encampment root from warband path
 		townRoot = filepath.Dir(rigPath)
-		if agentOverride != "" {
-			var err error
-			rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
-			if err != nil {
-				return "", err
-			}
-		} else if role != "" {
-			// No override, use role-based agent resolution
-			rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
-		} else {
-			rc = ResolveAgentConfig(townRoot, rigPath)
-		}
-	} else {
+	} else if root := envVars["HD_ROOT"]; root != "" {
+		townRoot = root
+	} else if root, err := findTownRootFromCwd(); err == nil {
+		// Encampment-level agents (warchief, shaman) run from the encampment
+		townRoot = root
+	}
+
+	var rc *RuntimeConfig
+	switch {
+	case townRoot == "":
+		rc = DefaultRuntimeConfig()
+	case agentOverride != "":
 		var err error
-		townRoot, err = findTownRootFromCwd()
+		rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
 		if err != nil {
-			rc = DefaultRuntimeConfig()
-		} else {
-			if agentOverride != "" {
-				var resolveErr error
-				rc, _, resolveErr = ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
-				if resolveErr != nil {
-					return "", resolveErr
-				}
-			} else if role != "" {
-				// No override, use role-based agent resolution
-				rc = ResolveRoleAgentConfig(role, townRoot, "")
-			} else {
-				rc = ResolveAgentConfig(townRoot, "")
-			}
+			return nil, nil, err
 		}
+	case role != "":
+		// Use role-based agent resolution for per-role model selection
+		rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
+	default:
+		rc = ResolveAgentConfig(townRoot, rigPath)
+	}
+
+	rc, err := rc.withSecretsResolved()
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Copy env vars to avoid mutating caller map
-	resolvedEnv := make(map[string]string, len(envVars)+2)
+	resolvedEnv := make(map[string]string, len(rc.Env)+len(envVars)+2)
+	for k, v := range rc.Env {
+		resolvedEnv[k] = v
+	}
 	for k, v := range envVars {
 		resolvedEnv[k] = v
 	}
@@ -1353,12 +1780,18 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["HD_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
+	return resolvedEnv, rc, nil
+}
 
+// startupCommand builds the export prefix for env followed by rc's command.
+func startupCommand(env map[string]string, rc *RuntimeConfig, prompt string) string {
 	// Build environment export prefix
 	var exports []string
-	for k, v := range resolvedEnv {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
+	for k, v := range env {
+		exports = append(exports, k+"="+quoteEnvValue(v))
 	}
+
+	// Sort for deterministic output
 	sort.Strings(exports)
 
 	var cmd string
@@ -1366,13 +1799,14 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		cmd = "export " + strings.Join(exports, " ") + " && "
 	}
 
+	// Add runtime command
 	if prompt != "" {
 		cmd += rc.BuildCommandWithPrompt(prompt)
 	} else {
 		cmd += rc.BuildCommand()
 	}
 
-	return cmd, nil
+	return cmd
 }
 
 // BuildAgentStartupCommand is a convenience function for starting agent sessions.
@@ -1481,10 +1915,41 @@ func GetDefaultFormula(rigPath string) string {
 	if err != nil {
 		return ""
 	}
-	if settings.Workflow == nil {
+	return settings.GetDefaultFormula()
+}
+
+// GetDefaultFormula returns the workflow's default ritual.
+// Returns empty string if settings or the workflow section is nil, or no default is set.
+func (s *RigSettings) GetDefaultFormula() string {
+	if s == nil || s.Workflow == nil {
 		return ""
 	}
-	return settings.Workflow.DefaultFormula
+	return s.Workflow.DefaultFormula
+}
+
+// GetAllowedFormulas returns the rituals the workflow allows to run.
+// Returns nil, meaning any ritual is allowed, if settings or the workflow section is nil
+// or no list is set.
+func (s *RigSettings) GetAllowedFormulas() []string {
+	if s == nil || s.Workflow == nil {
+		return nil
+	}
+	return s.Workflow.AllowedFormulas
+}
+
+// IsFormulaAllowed reports whether the workflow allows running the named ritual.
+// Every ritual is allowed unless allowed_formulas is set.
+func (s *RigSettings) IsFormulaAllowed(name string) bool {
+	allowed := s.GetAllowedFormulas()
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == name {
+			return true
+		}
+	}
+	return false
 }
 
 // GetRigPrefix returns the relics prefix for a warband from warbands.json.
@@ -1527,7 +1992,7 @@ func LoadEscalationConfig(path string) (*EscalationConfig, error) {
 	}
 
 	var config EscalationConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing escalation config: %w", err)
 	}
 
@@ -1631,6 +2096,19 @@ func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
 	return []string{"bead", "drums:warchief"}
 }
 
+// ResolveAllRoutes returns, for each severity in ValidSeverities, the route
+// GetRouteForSeverity resolves it to, with the default route filled in for
+// severities that have none configured. It is meant for auditing the
+// policy, e.g. spotting a severity whose route is empty. The routes are
+// copies, so changing them doesn't change c.
+func (c *EscalationConfig) ResolveAllRoutes() map[string][]string {
+	routes := make(map[string][]string, len(ValidSeverities()))
+	for _, severity := range ValidSeverities() {
+		routes[severity] = append([]string{}, c.GetRouteForSeverity(severity)...)
+	}
+	return routes
+}
+
 // GetMaxReescalations returns the maximum number of re-escalations allowed.
 // Returns 2 if not configured.
 func (c *EscalationConfig) GetMaxReescalations() int {
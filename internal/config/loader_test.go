@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -235,6 +237,65 @@ func TestRigSettingsWithCustomMergeQueue(t *testing.T) {
 	}
 }
 
+func TestResolveMergeQueueConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults when unset", func(t *testing.T) {
+		mq, err := ResolveMergeQueueConfig(nil, nil)
+		if err != nil {
+			t.Fatalf("ResolveMergeQueueConfig: %v", err)
+		}
+		if *mq != *DefaultMergeQueueConfig() {
+			t.Errorf("got %+v, want defaults", mq)
+		}
+	})
+
+	t.Run("warband over encampment over defaults", func(t *testing.T) {
+		town := &TownSettings{MergeQueue: &MergeQueueConfig{
+			Enabled:      true,
+			TargetBranch: "main",
+			OnConflict:   OnConflictAutoRebase,
+			PollInterval: "2m",
+		}}
+		warband := &RigSettings{MergeQueue: &MergeQueueConfig{
+			Enabled:       true,
+			TargetBranch:  "develop",
+			PollInterval:  "10s",
+			MaxConcurrent: 4,
+		}}
+
+		mq, err := ResolveMergeQueueConfig(town, warband)
+		if err != nil {
+			t.Fatalf("ResolveMergeQueueConfig: %v", err)
+		}
+		if mq.OnConflict != OnConflictAutoRebase {
+			t.Errorf("OnConflict = %q, want %q (from encampment)", mq.OnConflict, OnConflictAutoRebase)
+		}
+		if mq.PollInterval != "10s" {
+			t.Errorf("PollInterval = %q, want 10s (from warband)", mq.PollInterval)
+		}
+		if mq.MaxConcurrent != 4 {
+			t.Errorf("MaxConcurrent = %d, want 4 (from warband)", mq.MaxConcurrent)
+		}
+		if mq.RetryFlakyTests != DefaultMergeQueueConfig().RetryFlakyTests {
+			t.Errorf("RetryFlakyTests = %d, want default", mq.RetryFlakyTests)
+		}
+		if mq.TargetBranch != "develop" {
+			t.Errorf("TargetBranch = %q, want develop", mq.TargetBranch)
+		}
+		if warband.MergeQueue.OnConflict != "" {
+			t.Error("ResolveMergeQueueConfig modified warband settings")
+		}
+	})
+
+	t.Run("invalid merged result", func(t *testing.T) {
+		town := &TownSettings{MergeQueue: &MergeQueueConfig{OnConflict: "merge_anyway"}}
+		if _, err := ResolveMergeQueueConfig(town, nil); !errors.Is(err, ErrInvalidOnConflict) {
+			t.Errorf("error = %v, want ErrInvalidOnConflict", err)
+		}
+	})
+}
+
 func TestRigConfigValidation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -334,6 +395,31 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid role_agents",
+			settings: &RigSettings{
+				Type:    "warband-settings",
+				Version: 1,
+				RoleAgents: map[string]string{
+					"warchief": "claude-opus",
+					"shaman":   "claude",
+					"witness":  "claude-haiku",
+					"forge":    "claude",
+					"raider":   "claude-sonnet",
+					"clan":     "claude",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "misspelled role_agents key",
+			settings: &RigSettings{
+				Type:       "warband-settings",
+				Version:    1,
+				RoleAgents: map[string]string{"raidr": "claude-sonnet"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -346,6 +432,81 @@ func TestRigSettingsValidation(t *testing.T) {
 	}
 }
 
+func TestTownSettingsRoleAgentsValidation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings", "config.json")
+
+	settings := NewTownSettings()
+	settings.Agents["claude-sonnet"] = &RuntimeConfig{Command: "claude", Args: []string{"--model", "sonnet"}}
+	settings.RoleAgents["raider"] = "claude-sonnet"
+	if err := SaveTownSettings(path, settings); err != nil {
+		t.Fatalf("SaveTownSettings with valid role: %v", err)
+	}
+
+	settings.RoleAgents["raidr"] = "claude-sonnet"
+	err := SaveTownSettings(path, settings)
+	if !errors.Is(err, ErrUnknownRole) {
+		t.Fatalf("SaveTownSettings with misspelled role: error = %v, want ErrUnknownRole", err)
+	}
+
+	// Hand-edited file with a bad key must fail on load too
+	data := []byte(`{"type":"encampment-settings","version":1,"role_agents":{"raidr":"claude"}}`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateTownSettings(path); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("LoadOrCreateTownSettings with misspelled role: error = %v, want ErrUnknownRole", err)
+	}
+}
+
+func TestLoadOrCreateTownSettingsValidates(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"valid", `{"type":"encampment-settings","version":1,"default_agent":"gemini","role_agents":{"raider":"codex"}}`, nil},
+		{"custom agent", `{"type":"encampment-settings","version":1,"default_agent":"fast","agents":{"fast":{"command":"claude"}}}`, nil},
+		{"bad type", `{"type":"warband-settings","version":1}`, ErrInvalidType},
+		{"future version", `{"type":"encampment-settings","version":999}`, ErrInvalidVersion},
+		// Unknown agent names only warn on load; resolution falls back past them
+		{"unknown default agent", `{"type":"encampment-settings","version":1,"default_agent":"gemnii"}`, nil},
+		{"unknown role agent", `{"type":"encampment-settings","version":1,"role_agents":{"witness":"nope"}}`, nil},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadOrCreateTownSettings(path)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("%s: LoadOrCreateTownSettings error = %v, want nil", tt.name, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: LoadOrCreateTownSettings error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Save rejects unknown agent names
+	settings := NewTownSettings()
+	settings.DefaultAgent = "gemnii"
+	if err := SaveTownSettings(path, settings); !errors.Is(err, ErrUnknownAgent) {
+		t.Errorf("SaveTownSettings with unknown default agent: error = %v, want ErrUnknownAgent", err)
+	}
+	settings = NewTownSettings()
+	settings.RoleAgents = map[string]string{"witness": "nope"}
+	if err := SaveTownSettings(path, settings); !errors.Is(err, ErrUnknownAgent) {
+		t.Errorf("SaveTownSettings with unknown role agent: error = %v, want ErrUnknownAgent", err)
+	}
+}
+
 func TestDefaultMergeQueueConfig(t *testing.T) {
 	t.Parallel()
 	cfg := DefaultMergeQueueConfig()
@@ -556,6 +717,116 @@ func TestLoadAccountsConfigNotFound(t *testing.T) {
 	}
 }
 
+func TestResolveAccountConfigDirWithStrategy(t *testing.T) {
+	t.Setenv("HD_ACCOUNT", "")
+
+	newPool := func(t *testing.T, strategy string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "warchief", "accounts.json")
+		cfg := NewAccountsConfig()
+		for _, h := range []string{"a", "b", "c"} {
+			cfg.Accounts[h] = Account{ConfigDir: "/accounts/" + h}
+		}
+		cfg.Default = "b"
+		cfg.Strategy = strategy
+		if err := SaveAccountsConfig(path, cfg); err != nil {
+			t.Fatalf("SaveAccountsConfig: %v", err)
+		}
+		return path
+	}
+	resolve := func(t *testing.T, path, flag string) string {
+		t.Helper()
+		dir, handle, err := ResolveAccountConfigDirWithStrategy(path, flag)
+		if err != nil {
+			t.Fatalf("ResolveAccountConfigDirWithStrategy: %v", err)
+		}
+		if dir != "/accounts/"+handle {
+			t.Errorf("config dir = %q for handle %q", dir, handle)
+		}
+		return handle
+	}
+
+	t.Run("default", func(t *testing.T) {
+		path := newPool(t, "")
+		for i := 0; i < 2; i++ {
+			if got := resolve(t, path, ""); got != "b" {
+				t.Errorf("resolve #%d = %q, want b", i, got)
+			}
+		}
+		if _, err := os.Stat(AccountsStatePath(path)); !os.IsNotExist(err) {
+			t.Error("default strategy should not write a state file")
+		}
+	})
+
+	t.Run("round-robin", func(t *testing.T) {
+		path := newPool(t, AccountStrategyRoundRobin)
+		var got []string
+		for i := 0; i < 4; i++ {
+			got = append(got, resolve(t, path, ""))
+		}
+		if want := "b,c,a,b"; strings.Join(got, ",") != want {
+			t.Errorf("round-robin order = %v, want %s", got, want)
+		}
+	})
+
+	t.Run("lru", func(t *testing.T) {
+		path := newPool(t, AccountStrategyLRU)
+		if got := resolve(t, path, "c"); got != "c" {
+			t.Fatalf("flag override = %q, want c", got)
+		}
+		var got []string
+		for i := 0; i < 3; i++ {
+			got = append(got, resolve(t, path, ""))
+		}
+		if want := "a,b,c"; strings.Join(got, ",") != want {
+			t.Errorf("lru order = %v, want %s", got, want)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		path := newPool(t, AccountStrategyRoundRobin)
+		t.Setenv("HD_ACCOUNT", "a")
+		if got := resolve(t, path, "c"); got != "a" {
+			t.Errorf("HD_ACCOUNT override = %q, want a", got)
+		}
+	})
+
+	t.Run("warband account", func(t *testing.T) {
+		path := newPool(t, AccountStrategyRoundRobin)
+		rigPath := filepath.Join(t.TempDir(), "customer")
+		settings := NewRigSettings()
+		settings.Account = "c"
+		if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+			t.Fatalf("SaveRigSettings: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, handle, err := ResolveRigAccountConfigDir(path, rigPath, ""); err != nil || handle != "c" {
+				t.Errorf("warband account #%d = %q, %v; want c", i, handle, err)
+			}
+		}
+		if _, handle, _ := ResolveRigAccountConfigDir(path, rigPath, "a"); handle != "a" {
+			t.Errorf("flag over warband account = %q, want a", handle)
+		}
+
+		settings.Account = "ghost"
+		if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+			t.Fatalf("SaveRigSettings: %v", err)
+		}
+		if _, _, err := ResolveRigAccountConfigDir(path, rigPath, ""); err == nil {
+			t.Error("unknown warband account: expected error")
+		}
+	})
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		cfg := NewAccountsConfig()
+		cfg.Strategy = "random"
+		if err := validateAccountsConfig(cfg); !errors.Is(err, ErrInvalidAccountStrategy) {
+			t.Errorf("validateAccountsConfig err = %v, want ErrInvalidAccountStrategy", err)
+		}
+	})
+}
+
 func TestMessagingConfigRoundTrip(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
@@ -957,6 +1228,46 @@ func TestBuildAgentStartupCommand(t *testing.T) {
 	}
 }
 
+func TestResolveAgentEnv(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "horde")
+
+	env := ResolveAgentEnv("witness", "horde", townRoot, rigPath)
+	want := map[string]string{
+		"HD_ROLE":         "witness",
+		"HD_WARBAND":      "horde",
+		"BD_ACTOR":        "horde/witness",
+		"GIT_AUTHOR_NAME": "horde/witness",
+		"HD_ROOT":         townRoot,
+	}
+	if len(env) != len(want) {
+		t.Errorf("ResolveAgentEnv = %v, want %v", env, want)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("ResolveAgentEnv[%s] = %q, want %q", k, env[k], v)
+		}
+	}
+
+	// The command exports exactly the resolved environment
+	cmd := BuildAgentStartupCommand("witness", "horde", townRoot, rigPath, "")
+	prefix, _, ok := strings.Cut(cmd, " && ")
+	if !ok {
+		t.Fatalf("no export prefix in %q", cmd)
+	}
+	exports := strings.Fields(strings.TrimPrefix(prefix, "export "))
+	if len(exports) != len(env) {
+		t.Errorf("command exports %v, want %v", exports, env)
+	}
+	for _, kv := range exports {
+		k, v, _ := strings.Cut(kv, "=")
+		if env[k] != v {
+			t.Errorf("command exports %s=%s, ResolveAgentEnv has %q", k, v, env[k])
+		}
+	}
+}
+
 func TestBuildRaiderStartupCommand(t *testing.T) {
 	t.Parallel()
 	cmd := BuildRaiderStartupCommand("horde", "toast", "", "")
@@ -1374,11 +1685,12 @@ func TestResolveRoleAgentConfig_FallsBackOnInvalidAgent(t *testing.T) {
 	townRoot := t.TempDir()
 	rigPath := filepath.Join(townRoot, "testrig")
 
-	// Configure encampment settings with an invalid agent for forge
+	// Configure encampment settings with an agent for forge whose binary is missing
 	townSettings := NewTownSettings()
 	townSettings.DefaultAgent = "claude"
+	townSettings.Agents["broken"] = &RuntimeConfig{Command: "nonexistent-binary-xyz"}
 	townSettings.RoleAgents = map[string]string{
-		constants.RoleForge: "nonexistent-agent-xyz", // Invalid agent
+		constants.RoleForge: "broken", // Agent whose binary is missing
 	}
 	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
 		t.Fatalf("SaveTownSettings: %v", err)
@@ -1397,6 +1709,76 @@ func TestResolveRoleAgentConfig_FallsBackOnInvalidAgent(t *testing.T) {
 	}
 }
 
+func TestResolveRoleAgentConfig_UnknownRoleAgentKeepsDefault(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	path := TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand-edited settings file with a misspelled agent name
+	data := `{"type":"encampment-settings","version":1,"default_agent":"gemini","role_agents":{"forge":"claud"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the misspelled role falls back; the rest of the file still applies
+	rc := ResolveRoleAgentConfig(constants.RoleForge, townRoot, "")
+	if rc.Command != "gemini" {
+		t.Errorf("expected fallback to default_agent gemini, got: %s", rc.Command)
+	}
+}
+
+func TestExplainAgentResolution(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	// "sh" exists on every test machine, so the custom agent validates
+	townSettings := NewTownSettings()
+	townSettings.Agents = map[string]*RuntimeConfig{
+		"fast":   {Command: "sh", Args: []string{"-c", "true"}},
+		"broken": {Command: "nonexistent-binary-xyz"},
+	}
+	townSettings.RoleAgents = map[string]string{
+		constants.RoleRaider: "fast",
+		constants.RoleForge:  "broken",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), NewRigSettings()); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	trace := ExplainAgentResolution(constants.RoleRaider, townRoot, rigPath)
+	if trace.Winner != "encampment role_agents[raider]" {
+		t.Errorf("Winner = %q, want encampment role_agents[raider]", trace.Winner)
+	}
+	if trace.AgentName != "fast" || trace.DefinedIn != "encampment agents" {
+		t.Errorf("AgentName/DefinedIn = %q/%q, want fast/encampment agents", trace.AgentName, trace.DefinedIn)
+	}
+	if trace.Runtime == nil || trace.Runtime.Command != "sh" {
+		t.Errorf("Runtime = %+v, want command sh", trace.Runtime)
+	}
+	if got := trace.Steps[0].Result; got != "unset" {
+		t.Errorf("warband role_agents result = %q, want unset", got)
+	}
+	if got := trace.Steps[len(trace.Steps)-1].Result; got != "not reached" {
+		t.Errorf("built-in default result = %q, want not reached", got)
+	}
+
+	// Invalid role agent is reported as skipped and resolution falls through
+	trace = ExplainAgentResolution(constants.RoleForge, townRoot, rigPath)
+	if !strings.HasPrefix(trace.Steps[1].Result, "skipped") {
+		t.Errorf("encampment role_agents[forge] result = %q, want skipped", trace.Steps[1].Result)
+	}
+	if trace.Winner != "encampment default_agent" || trace.AgentName != "claude" {
+		t.Errorf("Winner/AgentName = %q/%q, want encampment default_agent/claude", trace.Winner, trace.AgentName)
+	}
+}
+
 func TestGetRuntimeCommand_UsesRigAgentWhenRigPathProvided(t *testing.T) {
 	t.Parallel()
 	townRoot := t.TempDir()
@@ -1553,6 +1935,24 @@ func TestDaemonPatrolConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid heartbeat interval",
+			config: &DaemonPatrolConfig{
+				Type:      "daemon-scout-config",
+				Version:   1,
+				Heartbeat: &HeartbeatConfig{Enabled: true, Interval: "soon"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-positive scout interval",
+			config: &DaemonPatrolConfig{
+				Type:    "daemon-scout-config",
+				Version: 1,
+				Patrols: map[string]PatrolConfig{"witness": {Enabled: true, Interval: "0s"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1565,6 +1965,44 @@ func TestDaemonPatrolConfigValidation(t *testing.T) {
 	}
 }
 
+func TestDaemonPatrolConfigAccessors(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDaemonPatrolConfig()
+	if !cfg.IsHeartbeatEnabled() || cfg.GetHeartbeatInterval() != 3*time.Minute {
+		t.Errorf("default heartbeat = %v/%v, want enabled/3m", cfg.IsHeartbeatEnabled(), cfg.GetHeartbeatInterval())
+	}
+	if !cfg.IsPatrolEnabled("witness") || cfg.GetPatrolInterval("witness") != 5*time.Minute {
+		t.Errorf("default witness scout = %v/%v, want enabled/5m", cfg.IsPatrolEnabled("witness"), cfg.GetPatrolInterval("witness"))
+	}
+	if cfg.IsPatrolEnabled("unknown") {
+		t.Error("IsPatrolEnabled(unknown) = true, want false")
+	}
+
+	cfg = &DaemonPatrolConfig{
+		Heartbeat: &HeartbeatConfig{Enabled: false, Interval: "30s"},
+		Patrols: map[string]PatrolConfig{
+			"forge":  {Enabled: true, Interval: "90s"},
+			"shaman": {Enabled: true, Interval: "bogus"},
+		},
+	}
+	if cfg.IsHeartbeatEnabled() || cfg.GetHeartbeatInterval() != 30*time.Second {
+		t.Errorf("heartbeat = %v/%v, want disabled/30s", cfg.IsHeartbeatEnabled(), cfg.GetHeartbeatInterval())
+	}
+	if got := cfg.GetPatrolInterval("forge"); got != 90*time.Second {
+		t.Errorf("GetPatrolInterval(forge) = %v, want 90s", got)
+	}
+	if got := cfg.GetPatrolInterval("shaman"); got != 5*time.Minute {
+		t.Errorf("GetPatrolInterval(shaman) with invalid interval = %v, want 5m", got)
+	}
+
+	// Missing heartbeat section falls back to defaults
+	cfg = &DaemonPatrolConfig{}
+	if !cfg.IsHeartbeatEnabled() || cfg.GetHeartbeatInterval() != 3*time.Minute {
+		t.Errorf("missing heartbeat = %v/%v, want enabled/3m", cfg.IsHeartbeatEnabled(), cfg.GetHeartbeatInterval())
+	}
+}
+
 func TestLoadDaemonPatrolConfigNotFound(t *testing.T) {
 	t.Parallel()
 	_, err := LoadDaemonPatrolConfig("/nonexistent/path.json")
@@ -2107,6 +2545,9 @@ func TestRoleAgentsRoundTrip(t *testing.T) {
 			"witness": "claude-haiku",
 			"raider": "claude-sonnet",
 		}
+		for _, model := range []string{"opus", "haiku", "sonnet"} {
+			original.Agents["claude-"+model] = &RuntimeConfig{Command: "claude", Args: []string{"--model", model}}
+		}
 
 		if err := SaveTownSettings(townSettingsPath, original); err != nil {
 			t.Fatalf("SaveTownSettings: %v", err)
@@ -2429,6 +2870,38 @@ func TestEscalationConfigGetRouteForSeverity(t *testing.T) {
 	}
 }
 
+func TestEscalationConfigResolveAllRoutes(t *testing.T) {
+	t.Parallel()
+
+	cfg := &EscalationConfig{
+		Routes: map[string][]string{
+			SeverityLow:  {},
+			SeverityHigh: {"bead", "slack", "drums:warchief"},
+		},
+	}
+
+	routes := cfg.ResolveAllRoutes()
+	want := map[string]string{
+		SeverityLow:      "[]",
+		SeverityMedium:   "[bead drums:warchief]", // default
+		SeverityHigh:     "[bead slack drums:warchief]",
+		SeverityCritical: "[bead drums:warchief]", // default
+	}
+	if len(routes) != len(want) {
+		t.Errorf("ResolveAllRoutes returned %d routes, want %d", len(routes), len(want))
+	}
+	for severity, w := range want {
+		if got := fmt.Sprint(routes[severity]); got != w {
+			t.Errorf("route[%s] = %s, want %s", severity, got, w)
+		}
+	}
+
+	routes[SeverityHigh][0] = "changed"
+	if cfg.Routes[SeverityHigh][0] != "bead" {
+		t.Error("ResolveAllRoutes returned the config's own route slice")
+	}
+}
+
 func TestEscalationConfigGetMaxReescalations(t *testing.T) {
 	t.Parallel()
 
@@ -2596,3 +3069,330 @@ func TestBuildStartupCommandWithAgentOverride_IncludesGTRoot(t *testing.T) {
 		t.Errorf("expected HD_ROOT=%s in command, got: %q", townRoot, cmd)
 	}
 }
+
+func TestValidateEncampment(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+
+	rigs := &RigsConfig{
+		Version: CurrentRigsVersion,
+		Warbands: map[string]RigEntry{
+			"horde":   {RelicsConfig: &RelicsConfig{Prefix: "hd"}},
+			"missing": {RelicsConfig: &RelicsConfig{Prefix: "ms"}},
+			"renamed": {RelicsConfig: &RelicsConfig{Prefix: "rn"}},
+		},
+	}
+	if err := SaveRigsConfig(constants.WarchiefRigsPath(townRoot), rigs); err != nil {
+		t.Fatalf("SaveRigsConfig: %v", err)
+	}
+
+	horde := NewRigConfig("horde", "git@example.com:horde.git")
+	horde.Relics = &RelicsConfig{Prefix: "hd-"}
+	if err := SaveRigConfig(filepath.Join(townRoot, "horde", "config.json"), horde); err != nil {
+		t.Fatalf("SaveRigConfig: %v", err)
+	}
+	hordeSettings := NewRigSettings()
+	hordeSettings.Account = "ghost"
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "horde")), hordeSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	renamed := NewRigConfig("other", "git@example.com:renamed.git")
+	renamed.Relics = &RelicsConfig{Prefix: "xx"}
+	if err := SaveRigConfig(filepath.Join(townRoot, "renamed", "config.json"), renamed); err != nil {
+		t.Fatalf("SaveRigConfig: %v", err)
+	}
+
+	routes := `{"prefix":"hq-","path":"."}
+{"prefix":"hd-","path":"horde/warchief/warband"}
+{"prefix":"gone-","path":"gone/warchief/warband"}
+`
+	if err := os.MkdirAll(filepath.Join(townRoot, ".relics"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, ".relics", "routes.jsonl"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := ValidateEncampment(townRoot)
+	want := []string{
+		`warband "missing" is registered but`,
+		`name "other" doesn't match registered warband "renamed"`,
+		`relics prefix "xx" doesn't match "rn"`,
+		`warband "gone" is not registered`,
+		`warband "missing" has relics prefix "ms" but no route`,
+		`warband "renamed" has relics prefix "rn" but no route`,
+		`account "ghost" is not in`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("ValidateEncampment returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("errs[%d] = %q, want it to contain %q", i, err, want[i])
+		}
+		if !errors.Is(err, ErrInconsistent) {
+			t.Errorf("errs[%d] = %v, want ErrInconsistent", i, err)
+		}
+		var encErr *EncampmentError
+		if !errors.As(err, &encErr) || encErr.Path == "" {
+			t.Errorf("errs[%d] = %v, want *EncampmentError with a path", i, err)
+		}
+	}
+
+	if errs := ValidateEncampment(t.TempDir()); errs != nil {
+		t.Errorf("ValidateEncampment(empty encampment) = %v, want nil", errs)
+	}
+}
+
+func TestBuildStartupCommand_ResolvesSecrets(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	origLookup := keyringLookup
+	t.Cleanup(func() { keyringLookup = origLookup })
+	keyringLookup = func(name string) string {
+		if name == "KEYRING_TOKEN" {
+			return "from-keyring"
+		}
+		return ""
+	}
+	t.Setenv("ENV_TOKEN", "from-env")
+
+	rigSettings := NewRigSettings()
+	rigSettings.Agent = "mybot"
+	rigSettings.Agents = map[string]*RuntimeConfig{
+		"mybot": {
+			Command: "mybot",
+			Args:    []string{"--token=${secret:ENV_TOKEN}"},
+			Env:     map[string]string{"MYBOT_KEY": "${secret:KEYRING_TOKEN}"},
+		},
+	}
+	settingsPath := RigSettingsPath(rigPath)
+	if err := SaveRigSettings(settingsPath, rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd, err := BuildStartupCommandWithAgentOverride(map[string]string{"HD_ROLE": "witness"}, rigPath, "", "")
+	if err != nil {
+		t.Fatalf("BuildStartupCommandWithAgentOverride: %v", err)
+	}
+	for _, want := range []string{"MYBOT_KEY=from-keyring", "mybot --token=from-env"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("command %q missing %q", cmd, want)
+		}
+	}
+
+	// The resolved values are never written back to the settings file
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("reading settings: %v", err)
+	}
+	if strings.Contains(string(data), "from-") {
+		t.Errorf("settings file contains resolved secrets: %s", data)
+	}
+
+	// An unresolvable reference fails rather than exporting an empty value
+	rigSettings.Agents["mybot"].Env["MYBOT_KEY"] = "${secret:MISSING_TOKEN}"
+	if err := SaveRigSettings(settingsPath, rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	if _, err := BuildStartupCommandWithAgentOverride(map[string]string{"HD_ROLE": "witness"}, rigPath, "", ""); !errors.Is(err, ErrUnresolvedSecret) {
+		t.Errorf("err = %v, want ErrUnresolvedSecret", err)
+	}
+	cmd = BuildStartupCommand(map[string]string{"HD_ROLE": "witness"}, rigPath, "")
+	if !strings.Contains(cmd, "MISSING_TOKEN") || !strings.HasSuffix(cmd, "&& false") {
+		t.Errorf("BuildStartupCommand = %q, want a failing command naming the secret", cmd)
+	}
+}
+
+func TestBuildStartupCommand_QuotesSecretValues(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	const secret = `p@ss word$HOME;'q'&"dq"` + "`id`"
+	t.Setenv("TRICKY_TOKEN", secret)
+
+	// The agent just prints the exported value, so the shell's view of it
+	// is compared with the secret.
+	rigSettings := NewRigSettings()
+	rigSettings.Agent = "echoer"
+	rigSettings.Agents = map[string]*RuntimeConfig{
+		"echoer": {
+			Command: "printenv",
+			Args:    []string{"ECHOER_KEY"},
+			Env:     map[string]string{"ECHOER_KEY": "${secret:TRICKY_TOKEN}"},
+		},
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd, err := BuildStartupCommandWithAgentOverride(map[string]string{"HD_ROLE": "witness"}, rigPath, "", "")
+	if err != nil {
+		t.Fatalf("BuildStartupCommandWithAgentOverride: %v", err)
+	}
+	out, err := exec.Command("sh", "-c", cmd).Output()
+	if err != nil {
+		t.Fatalf("running %q: %v", cmd, err)
+	}
+	if got := strings.TrimSuffix(string(out), "\n"); got != secret {
+		t.Errorf("exported value = %q, want %q", got, secret)
+	}
+}
+
+func TestFindRoleAgentConflicts(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	// Custom agents backed by sh, so validation doesn't depend on installed CLIs
+	townSettings := NewTownSettings()
+	townSettings.Agents = map[string]*RuntimeConfig{
+		"alpha": {Command: "sh"},
+		"beta":  {Command: "sh"},
+	}
+	townSettings.RoleAgents = map[string]string{
+		constants.RoleRaider:  "alpha",
+		constants.RoleWitness: "beta",
+		constants.RoleForge:   "alpha",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	rigSettings := NewRigSettings()
+	rigSettings.RoleAgents = map[string]string{
+		constants.RoleRaider:  "beta",          // shadows alpha
+		constants.RoleWitness: "beta",          // same agent: no conflict
+		constants.RoleForge:   "missing-agent", // invalid: encampment still wins
+		constants.RoleCrew:    "alpha",         // not set in encampment
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	got := FindRoleAgentConflicts(townRoot, rigPath)
+	want := []RoleAgentConflict{
+		{Role: constants.RoleForge, TownAgent: "alpha", RigAgent: "missing-agent", Winner: "encampment"},
+		{Role: constants.RoleRaider, TownAgent: "alpha", RigAgent: "beta", Winner: "warband"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("FindRoleAgentConflicts = %+v, want %+v", got, want)
+	}
+
+	trace := ExplainAgentResolution(constants.RoleRaider, townRoot, rigPath)
+	if trace.Conflict == nil || trace.Conflict.TownAgent != "alpha" {
+		t.Errorf("trace.Conflict = %+v, want the raider conflict", trace.Conflict)
+	}
+}
+
+func TestResolveInitialPrompt(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "prompts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "prompts", "raider.md"), []byte("You are a {role} in {warband}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	townSettings := NewTownSettings()
+	townSettings.Agents = map[string]*RuntimeConfig{
+		"from-file":   {Command: "sh", PromptFile: "prompts/{role}.md"},
+		"inline":      {Command: "sh", InitialPrompt: "Watch over {warband}"},
+		"broken-file": {Command: "sh", PromptFile: "prompts/missing.md"},
+	}
+	townSettings.RoleAgents = map[string]string{
+		constants.RoleRaider:  "from-file",
+		constants.RoleWitness: "inline",
+		constants.RoleForge:   "broken-file",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	tests := []struct {
+		role string
+		want string
+	}{
+		{constants.RoleRaider, "You are a raider in testrig."},
+		{constants.RoleWitness, "Watch over testrig"},
+		{constants.RoleCrew, ""}, // default agent has no prompt
+	}
+	for _, tt := range tests {
+		got, err := ResolveInitialPrompt(tt.role, townRoot, rigPath)
+		if err != nil {
+			t.Errorf("ResolveInitialPrompt(%s): %v", tt.role, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveInitialPrompt(%s) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+
+	if _, err := ResolveInitialPrompt(constants.RoleForge, townRoot, rigPath); err == nil {
+		t.Error("ResolveInitialPrompt with a missing prompt file: expected error")
+	}
+}
+
+func TestRigSettingsWorkflowAccessors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		settings    *RigSettings
+		wantDefault string
+		wantAllowed []string
+		allowed     map[string]bool
+	}{
+		{
+			name:     "nil settings",
+			settings: nil,
+			allowed:  map[string]bool{"release": true},
+		},
+		{
+			name:     "nil workflow",
+			settings: &RigSettings{},
+			allowed:  map[string]bool{"release": true},
+		},
+		{
+			name: "default only",
+			settings: &RigSettings{Workflow: &WorkflowConfig{
+				DefaultFormula: "shiny",
+			}},
+			wantDefault: "shiny",
+			allowed:     map[string]bool{"shiny": true, "release": true},
+		},
+		{
+			name: "allowed list",
+			settings: &RigSettings{Workflow: &WorkflowConfig{
+				DefaultFormula:  "shiny",
+				AllowedFormulas: []string{"shiny", "release"},
+			}},
+			wantDefault: "shiny",
+			wantAllowed: []string{"shiny", "release"},
+			allowed:     map[string]bool{"shiny": true, "release": true, "deploy": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.GetDefaultFormula(); got != tt.wantDefault {
+				t.Errorf("GetDefaultFormula() = %q, want %q", got, tt.wantDefault)
+			}
+			if got := tt.settings.GetAllowedFormulas(); strings.Join(got, ",") != strings.Join(tt.wantAllowed, ",") {
+				t.Errorf("GetAllowedFormulas() = %v, want %v", got, tt.wantAllowed)
+			}
+			for name, want := range tt.allowed {
+				if got := tt.settings.IsFormulaAllowed(name); got != want {
+					t.Errorf("IsFormulaAllowed(%q) = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// rigSettingsMigrations upgrades RigSettings one schema version at a time.
+// The function at key N converts a version-N settings value to version N+1.
+// Add an entry here whenever CurrentRigSettingsVersion is bumped.
+var rigSettingsMigrations = map[int]func(*RigSettings) error{
+	// Version 0 is a legacy file written before type/version were recorded.
+	0: func(s *RigSettings) error {
+		if s.Type == "" {
+			s.Type = "warband-settings"
+		}
+		return nil
+	},
+}
+
+// MigrateRigSettings returns a copy of settings upgraded to
+// CurrentRigSettingsVersion, and whether any migration was applied.
+// The input is not modified. Settings from a newer schema than this binary
+// supports are rejected with ErrInvalidVersion.
+func MigrateRigSettings(settings *RigSettings) (*RigSettings, bool, error) {
+	if settings.Version > CurrentRigSettingsVersion {
+		return nil, false, fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentRigSettingsVersion)
+	}
+
+	migrated, err := cloneRigSettings(settings)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for migrated.Version < CurrentRigSettingsVersion {
+		migrate, ok := rigSettingsMigrations[migrated.Version]
+		if !ok {
+			return nil, false, fmt.Errorf("no migration from warband settings version %d", migrated.Version)
+		}
+		if err := migrate(migrated); err != nil {
+			return nil, false, fmt.Errorf("migrating warband settings from version %d: %w", migrated.Version, err)
+		}
+		migrated.Version++
+		changed = true
+	}
+
+	if err := validateRigSettings(migrated); err != nil {
+		return nil, false, err
+	}
+	return migrated, changed, nil
+}
+
+// cloneRigSettings deep-copies settings via a JSON round trip, which is
+// exactly the representation written to disk.
+func cloneRigSettings(settings *RigSettings) (*RigSettings, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("encoding settings: %w", err)
+	}
+	var clone RigSettings
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("decoding settings: %w", err)
+	}
+	// Keep include bookkeeping so saving the clone still omits included
+	// entries. The clone's agents are new pointers, so rebuild it.
+	if settings.included != nil {
+		clone.included = &includedEntries{
+			agents:     make(map[string]*RuntimeConfig),
+			roleAgents: settings.included.roleAgents,
+		}
+		for name, rc := range settings.included.agents {
+			if settings.Agents[name] == rc {
+				clone.included.agents[name] = clone.Agents[name]
+			}
+		}
+	}
+	return &clone, nil
+}
+
+// SettingsChange describes one field that differs between two settings values.
+// Path is the dotted JSON path (e.g., "merge_queue.on_conflict"). Old and New
+// are JSON-encoded values; an empty string means the field is absent.
+type SettingsChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// DiffRigSettings compares two warband settings values field by field, using
+// their on-disk JSON form, and returns the differences sorted by path.
+func DiffRigSettings(before, after *RigSettings) ([]SettingsChange, error) {
+	a, err := settingsToMap(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := settingsToMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []SettingsChange
+	diffJSONValues("", a, b, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// settingsToMap converts settings to a generic JSON object. Nil settings
+// become an empty object.
+func settingsToMap(settings *RigSettings) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if settings == nil {
+		return m, nil
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("encoding settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decoding settings: %w", err)
+	}
+	return m, nil
+}
+
+// diffJSONValues appends changes between a and b to changes, recursing into
+// objects so each changed leaf is reported with its full path.
+func diffJSONValues(path string, a, b interface{}, changes *[]SettingsChange) {
+	aMap, aIsMap := a.(map[string]interface{})
+	bMap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]bool)
+		for k := range aMap {
+			keys[k] = true
+		}
+		for k := range bMap {
+			keys[k] = true
+		}
+		for k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			diffJSONValues(child, aMap[k], bMap[k], changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	*changes = append(*changes, SettingsChange{Path: path, Old: encodeJSONValue(a), New: encodeJSONValue(b)})
+}
+
+// encodeJSONValue renders a decoded JSON value for display; nil becomes "".
+func encodeJSONValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
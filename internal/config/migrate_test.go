@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMigrateRigSettings(t *testing.T) {
+	t.Parallel()
+
+	legacy := &RigSettings{Agent: "gemini"}
+	migrated, changed, err := MigrateRigSettings(legacy)
+	if err != nil {
+		t.Fatalf("MigrateRigSettings: %v", err)
+	}
+	if !changed {
+		t.Error("changed = false, want true for version 0")
+	}
+	if migrated.Version != CurrentRigSettingsVersion || migrated.Type != "warband-settings" {
+		t.Errorf("migrated type/version = %q/%d, want warband-settings/%d", migrated.Type, migrated.Version, CurrentRigSettingsVersion)
+	}
+	if migrated.Agent != "gemini" {
+		t.Errorf("Agent = %q, want gemini (preserved)", migrated.Agent)
+	}
+	if legacy.Version != 0 || legacy.Type != "" {
+		t.Error("MigrateRigSettings modified its input")
+	}
+
+	current := NewRigSettings()
+	if _, changed, err := MigrateRigSettings(current); err != nil || changed {
+		t.Errorf("MigrateRigSettings(current) = changed %v, err %v; want unchanged, nil", changed, err)
+	}
+
+	future := &RigSettings{Type: "warband-settings", Version: CurrentRigSettingsVersion + 1}
+	if _, _, err := MigrateRigSettings(future); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("MigrateRigSettings(future) error = %v, want ErrInvalidVersion", err)
+	}
+}
+
+func TestDiffRigSettings(t *testing.T) {
+	t.Parallel()
+
+	before := NewRigSettings()
+	after := NewRigSettings()
+	if changes, err := DiffRigSettings(before, after); err != nil || len(changes) != 0 {
+		t.Fatalf("DiffRigSettings(equal) = %v, %v; want no changes", changes, err)
+	}
+
+	after.Agent = "codex"
+	after.MergeQueue.OnConflict = OnConflictAutoRebase
+	changes, err := DiffRigSettings(before, after)
+	if err != nil {
+		t.Fatalf("DiffRigSettings: %v", err)
+	}
+	want := []SettingsChange{
+		{Path: "agent", Old: "", New: `"codex"`},
+		{Path: "merge_queue.on_conflict", Old: `"` + before.MergeQueue.OnConflict + `"`, New: `"` + OnConflictAutoRebase + `"`},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
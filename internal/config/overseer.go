@@ -39,7 +39,7 @@ func LoadOverseerConfig(path string) (*OverseerConfig, error) {
 	}
 
 	var config OverseerConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(data, &config); err != nil {
 		return nil, fmt.Errorf("parsing overseer config: %w", err)
 	}
 
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandRolePattern expands placeholders in a pattern string.
+// Supported placeholders: {encampment}, {warband}, {name}, {role}
+func ExpandRolePattern(pattern, townRoot, warband, name, role string) string {
+	result := pattern
+	result = strings.ReplaceAll(result, "{encampment}", townRoot)
+	result = strings.ReplaceAll(result, "{warband}", warband)
+	result = strings.ReplaceAll(result, "{name}", name)
+	result = strings.ReplaceAll(result, "{role}", role)
+	return result
+}
+
+// ResolveInitialPrompt returns the initial prompt for role, from the agent
+// ResolveRoleAgentConfig picks for it: the contents of the agent's
+// prompt_file if set, else its initial_prompt, else "". A relative
+// prompt_file is taken from the encampment root, and may itself use the
+// ExpandRolePattern placeholders. Placeholders in the prompt are expanded
+// with {warband} set to rigPath's base name and {name} left empty.
+//
+// Pass the result as the prompt argument of BuildStartupCommand and friends.
+func ResolveInitialPrompt(role, townRoot, rigPath string) (string, error) {
+	rc := ResolveRoleAgentConfig(role, townRoot, rigPath)
+	warband := ""
+	if rigPath != "" {
+		warband = filepath.Base(rigPath)
+	}
+	expand := func(s string) string {
+		return ExpandRolePattern(s, townRoot, warband, "", role)
+	}
+
+	if rc.PromptFile == "" {
+		return expand(rc.InitialPrompt), nil
+	}
+
+	path := expandPath(expand(rc.PromptFile))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(townRoot, path)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path comes from the operator's own settings
+	if err != nil {
+		return "", fmt.Errorf("reading prompt file for %s: %w", role, err)
+	}
+	return expand(strings.TrimSpace(string(data))), nil
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ConfigSchema describes one config file type: its fields, as read from the
+// Go struct, plus the constraints its validator enforces.
+type ConfigSchema struct {
+	Name    string        `json:"name"`    // config type, as in the file's "type" field where it has one
+	File    string        `json:"file"`    // conventional location of the file
+	Version int           `json:"version"` // current (maximum supported) schema version
+	Fields  []FieldSchema `json:"fields"`
+}
+
+// FieldSchema describes one JSON field of a config file, or the elements of
+// an array or map field.
+type FieldSchema struct {
+	Name     string        `json:"name,omitempty"`     // JSON key; empty for array and map elements
+	Type     string        `json:"type"`               // string, integer, number, boolean, array, map or object
+	Format   string        `json:"format,omitempty"`   // for strings: "duration" or "date-time"
+	Required bool          `json:"required,omitempty"` // rejected by validation when empty
+	Enum     []string      `json:"enum,omitempty"`     // allowed values; empty is always allowed unless Required
+	Keys     []string      `json:"keys,omitempty"`     // allowed keys, for maps
+	Items    *FieldSchema  `json:"items,omitempty"`    // element schema, for arrays and maps
+	Fields   []FieldSchema `json:"fields,omitempty"`   // nested fields, for objects
+}
+
+// Field returns the nested field named by a dotted JSON path such as
+// "merge_queue.on_conflict", or nil if there is none. Map and array elements
+// are stepped through transparently.
+func (s ConfigSchema) Field(path string) *FieldSchema {
+	fields := s.Fields
+	var found *FieldSchema
+	for _, name := range strings.Split(path, ".") {
+		found = nil
+		for i := range fields {
+			if fields[i].Name == name {
+				found = &fields[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		elem := found
+		for elem.Items != nil {
+			elem = elem.Items
+		}
+		fields = elem.Fields
+	}
+	return found
+}
+
+// fieldRule is a constraint a validator places on a field, beyond its type.
+type fieldRule struct {
+	required bool
+	format   string
+	enum     []string
+	keys     []string
+}
+
+// schemaRules mirrors the checks in the validate* functions, keyed by the
+// struct that declares the field and then by JSON key. Rules attach to the
+// struct rather than the file so that, for example, merge_queue.on_conflict
+// is described the same way in encampment and warband settings.
+var schemaRules = map[reflect.Type]map[string]fieldRule{
+	reflect.TypeOf(TownConfig{}): {
+		"name": {required: true},
+	},
+	reflect.TypeOf(RigConfig{}): {
+		"name": {required: true},
+	},
+	reflect.TypeOf(OverseerConfig{}): {
+		"name": {required: true},
+	},
+	reflect.TypeOf(TownSettings{}): {
+		"role_agents": {keys: knownRoles},
+	},
+	reflect.TypeOf(RigSettings{}): {
+		"role_agents": {keys: knownRoles},
+	},
+	reflect.TypeOf(MergeQueueConfig{}): {
+		"on_conflict":   {enum: []string{OnConflictAssignBack, OnConflictAutoRebase}},
+		"poll_interval": {format: "duration"},
+	},
+	reflect.TypeOf(AccountsConfig{}): {
+		"strategy": {enum: []string{AccountStrategyDefault, AccountStrategyRoundRobin, AccountStrategyLRU}},
+	},
+	reflect.TypeOf(Account{}): {
+		"config_dir": {required: true},
+	},
+	reflect.TypeOf(HeartbeatConfig{}): {
+		"interval": {format: "duration"},
+	},
+	reflect.TypeOf(PatrolConfig{}): {
+		"interval": {format: "duration"},
+	},
+	reflect.TypeOf(EscalationConfig{}): {
+		"routes":          {keys: ValidSeverities()},
+		"stale_threshold": {format: "duration"},
+	},
+}
+
+// Schema describes every config file type Horde reads, in a form suitable
+// for generating settings editors or validating files written by other
+// tools. The result is freshly built on each call and safe to modify.
+func Schema() []ConfigSchema {
+	roots := []struct {
+		name, file string
+		version    int
+		v          any
+	}{
+		{"encampment", "warchief/encampment.json", CurrentTownVersion, TownConfig{}},
+		{"warchief-config", "warchief/config.json", CurrentWarchiefConfigVersion, WarchiefConfig{}},
+		{"warbands", "warchief/warbands.json", CurrentRigsVersion, RigsConfig{}},
+		{"accounts", "warchief/accounts.json", CurrentAccountsVersion, AccountsConfig{}},
+		{"daemon-scout-config", "warchief/" + DaemonPatrolConfigFileName, CurrentDaemonPatrolConfigVersion, DaemonPatrolConfig{}},
+		{"overseer", "warchief/overseer.json", CurrentOverseerVersion, OverseerConfig{}},
+		{"encampment-settings", "settings/config.json", CurrentTownSettingsVersion, TownSettings{}},
+		{"agents", "settings/agents.json", CurrentAgentRegistryVersion, AgentRegistry{}},
+		{"escalation", "settings/escalation.json", CurrentEscalationVersion, EscalationConfig{}},
+		{"messaging", "config/messaging.json", CurrentMessagingVersion, MessagingConfig{}},
+		{"warband", "<warband>/config.json", CurrentRigConfigVersion, RigConfig{}},
+		{"warband-settings", "<warband>/settings/config.json", CurrentRigSettingsVersion, RigSettings{}},
+	}
+
+	schemas := make([]ConfigSchema, 0, len(roots))
+	for _, r := range roots {
+		fields := structFields(reflect.TypeOf(r.v), map[reflect.Type]bool{})
+		for i := range fields {
+			// Validators accept only their own type name (or none).
+			if fields[i].Name == "type" {
+				fields[i].Enum = []string{r.name}
+			}
+		}
+		schemas = append(schemas, ConfigSchema{
+			Name:    r.name,
+			File:    r.file,
+			Version: r.version,
+			Fields:  fields,
+		})
+	}
+	return schemas
+}
+
+// structFields describes the JSON fields of struct type t. visiting guards
+// against self-referential types.
+func structFields(t reflect.Type, visiting map[reflect.Type]bool) []FieldSchema {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		field := typeSchema(sf.Type, visiting)
+		field.Name = name
+		if rule, ok := schemaRules[t][name]; ok {
+			field.Required = rule.required
+			if rule.format != "" {
+				field.Format = rule.format
+			}
+			field.Enum = append([]string(nil), rule.enum...)
+			field.Keys = append([]string(nil), rule.keys...)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// typeSchema describes a value of Go type t.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) FieldSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return FieldSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return FieldSchema{Type: "string"}
+	case reflect.Bool:
+		return FieldSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return FieldSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		items := typeSchema(t.Elem(), visiting)
+		return FieldSchema{Type: "array", Items: &items}
+	case reflect.Map:
+		items := typeSchema(t.Elem(), visiting)
+		return FieldSchema{Type: "map", Items: &items}
+	case reflect.Struct:
+		return FieldSchema{Type: "object", Fields: structFields(t, visiting)}
+	default:
+		return FieldSchema{Type: "object"}
+	}
+}
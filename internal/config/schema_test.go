@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	schemas := make(map[string]ConfigSchema)
+	for _, s := range Schema() {
+		if _, dup := schemas[s.Name]; dup {
+			t.Fatalf("duplicate schema %q", s.Name)
+		}
+		schemas[s.Name] = s
+	}
+
+	tests := []struct {
+		schema, path string
+		want         FieldSchema
+	}{
+		{"encampment", "type", FieldSchema{Name: "type", Type: "string", Enum: []string{"encampment"}}},
+		{"encampment", "name", FieldSchema{Name: "name", Type: "string", Required: true}},
+		{"encampment", "created_at", FieldSchema{Name: "created_at", Type: "string", Format: "date-time"}},
+		{"warband-settings", "merge_queue.on_conflict", FieldSchema{
+			Name: "on_conflict", Type: "string", Enum: []string{OnConflictAssignBack, OnConflictAutoRebase},
+		}},
+		{"encampment-settings", "merge_queue.poll_interval", FieldSchema{Name: "poll_interval", Type: "string", Format: "duration"}},
+		{"warband-settings", "role_agents", FieldSchema{
+			Name: "role_agents", Type: "map", Keys: knownRoles, Items: &FieldSchema{Type: "string"},
+		}},
+		{"escalation", "routes", FieldSchema{
+			Name: "routes", Type: "map", Keys: ValidSeverities(),
+			Items: &FieldSchema{Type: "array", Items: &FieldSchema{Type: "string"}},
+		}},
+		{"accounts", "accounts.config_dir", FieldSchema{Name: "config_dir", Type: "string", Required: true}},
+		{"daemon-scout-config", "patrols.interval", FieldSchema{Name: "interval", Type: "string", Format: "duration"}},
+		{"messaging", "queues.max_claims", FieldSchema{Name: "max_claims", Type: "integer"}},
+	}
+	for _, tt := range tests {
+		s, ok := schemas[tt.schema]
+		if !ok {
+			t.Fatalf("no schema %q", tt.schema)
+		}
+		got := s.Field(tt.path)
+		if got == nil {
+			t.Errorf("%s: no field %q", tt.schema, tt.path)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s.%s = %+v, want %+v", tt.schema, tt.path, *got, tt.want)
+		}
+	}
+
+	if got := schemas["encampment"].Version; got != CurrentTownVersion {
+		t.Errorf("encampment version = %d, want %d", got, CurrentTownVersion)
+	}
+	if schemas["warband"].Field("nonexistent") != nil {
+		t.Error("Field returned a schema for a nonexistent path")
+	}
+}
+
+// The schema's top-level keys must be exactly the keys the config type
+// serializes, so it can't drift from the structs.
+func TestSchemaMatchesJSON(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"encampment-settings": NewTownSettings(),
+		"warband-settings":    NewRigSettings(),
+		"messaging":           NewMessagingConfig(),
+		"escalation":          &EscalationConfig{StaleThreshold: "1h", MaxReescalations: 1},
+	}
+	for _, s := range Schema() {
+		v, ok := values[s.Name]
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal %s: %v", s.Name, err)
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			t.Fatalf("unmarshal %s: %v", s.Name, err)
+		}
+		for key := range raw {
+			if s.Field(key) == nil {
+				t.Errorf("%s: serialized key %q missing from schema", s.Name, key)
+			}
+		}
+		for _, f := range s.Fields {
+			if strings.Contains(f.Name, ".") || f.Name == "" {
+				t.Errorf("%s: bad field name %q", s.Name, f.Name)
+			}
+		}
+	}
+}
+
+func TestSchemaIsACopy(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range Schema() {
+		if s.Name == "warband-settings" {
+			s.Field("role_agents").Keys[0] = "mutated"
+		}
+	}
+	if knownRoles[0] == "mutated" {
+		t.Fatal("modifying Schema() result changed knownRoles")
+	}
+}
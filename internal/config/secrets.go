@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// ErrUnresolvedSecret indicates a ${secret:NAME} reference whose value is
+// neither in the environment nor in the OS keyring.
+var ErrUnresolvedSecret = errors.New("unresolved secret")
+
+// SecretKeyringService is the keyring service secrets are stored under, e.g.
+// `secret-tool store --label=... service horde account NAME` on Linux or
+// `security add-generic-password -s horde -a NAME -w` on macOS.
+const SecretKeyringService = "horde"
+
+// secretRefPattern matches a ${secret:NAME} reference in a config value.
+var secretRefPattern = regexp.MustCompile(`\$\{secret:([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// keyringLookup reads a secret from the OS keyring. Tests replace it.
+var keyringLookup = lookupKeyring
+
+// ResolveSecret returns the value of the secret NAME: the environment
+// variable NAME if set and non-empty, else the keyring entry for NAME under
+// SecretKeyringService. It returns an error wrapping ErrUnresolvedSecret if
+// neither has a value.
+func ResolveSecret(name string) (string, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, nil
+	}
+	if v := keyringLookup(name); v != "" {
+		return v, nil
+	}
+	return "", fmt.Errorf("%w: %s is not set in the environment or the %q keyring", ErrUnresolvedSecret, name, SecretKeyringService)
+}
+
+// resolveSecretRefs replaces every ${secret:NAME} reference in value with
+// the secret's value. Values without references are returned unchanged.
+func resolveSecretRefs(value string) (string, error) {
+	if !strings.Contains(value, "${secret:") {
+		return value, nil
+	}
+	var firstErr error
+	resolved := secretRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := secretRefPattern.FindStringSubmatch(ref)[1]
+		v, err := ResolveSecret(name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return v
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return resolved, nil
+}
+
+// withSecretsResolved returns a copy of rc with secret references in Args
+// and Env replaced by their values. rc itself is left untouched, so the
+// resolved values can't be written back to a settings file.
+func (rc *RuntimeConfig) withSecretsResolved() (*RuntimeConfig, error) {
+	resolved := *rc
+	if rc.Args != nil {
+		resolved.Args = make([]string, len(rc.Args))
+		for i, arg := range rc.Args {
+			v, err := resolveSecretRefs(arg)
+			if err != nil {
+				return nil, fmt.Errorf("agent arg %d: %w", i, err)
+			}
+			resolved.Args[i] = v
+		}
+	}
+	if rc.Env != nil {
+		resolved.Env = make(map[string]string, len(rc.Env))
+		for k, value := range rc.Env {
+			v, err := resolveSecretRefs(value)
+			if err != nil {
+				return nil, fmt.Errorf("agent env %s: %w", k, err)
+			}
+			resolved.Env[k] = v
+		}
+	}
+	return &resolved, nil
+}
+
+// lookupKeyring reads name from the OS keyring with the platform's CLI
+// (security on macOS, secret-tool elsewhere). It returns "" if the entry or
+// the tool is missing.
+func lookupKeyring(name string) string {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", SecretKeyringService, "-a", name, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", SecretKeyringService, "account", name)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\r\n")
+}
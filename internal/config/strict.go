@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrUnknownField indicates a config file contains a key that doesn't match
+// any field of its config type, usually a typo. Only reported in strict mode.
+var ErrUnknownField = errors.New("unknown field")
+
+// strictLoading makes every loader reject unknown fields. Off by default so
+// files written by newer versions still load.
+var strictLoading atomic.Bool
+
+// SetStrictLoading turns strict mode on or off for all config loaders and
+// returns the previous setting, so callers can restore it:
+//
+//	defer config.SetStrictLoading(config.SetStrictLoading(true))
+func SetStrictLoading(strict bool) bool {
+	return strictLoading.Swap(strict)
+}
+
+// unmarshalConfig decodes a config file. In strict mode unknown fields are
+// reported as ErrUnknownField naming the offending key.
+func unmarshalConfig(data []byte, v any) error {
+	if !strictLoading.Load() {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		// encoding/json has no typed error for this case.
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+		return err
+	}
+	// Match json.Unmarshal, which rejects anything after the value.
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Not parallel: strict mode is package-wide.
+func TestStrictLoading(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings", "config.json")
+	writeJSON(t, path, `{
+		"type": "warband-settings",
+		"version": 1,
+		"role_agent": {"witness": "claude"}
+	}`)
+
+	if _, err := LoadRigSettings(path); err != nil {
+		t.Fatalf("lenient LoadRigSettings: %v", err)
+	}
+
+	defer SetStrictLoading(SetStrictLoading(true))
+
+	_, err := LoadRigSettings(path)
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("strict LoadRigSettings err = %v, want ErrUnknownField", err)
+	}
+	if !strings.Contains(err.Error(), `"role_agent"`) {
+		t.Errorf("error %q should name the unknown field", err)
+	}
+
+	writeJSON(t, path, `{"type": "warband-settings", "version": 1, "role_agents": {"witness": "claude"}}`)
+	if _, err := LoadRigSettings(path); err != nil {
+		t.Errorf("strict LoadRigSettings of valid file: %v", err)
+	}
+
+	writeJSON(t, path, `{"type": "warband-settings", "version": 1} {}`)
+	if _, err := LoadRigSettings(path); err == nil {
+		t.Error("strict LoadRigSettings accepted trailing data")
+	}
+}
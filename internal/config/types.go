@@ -61,6 +61,17 @@ type TownSettings struct {
 	// Agent addresses like "horde/clan/jack" become "horde.clan.jack@{domain}".
 	// Default: "horde.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// MergeQueue is the encampment-wide merge queue default. Warband settings
+	// override it field by field; see ResolveMergeQueueConfig.
+	MergeQueue *MergeQueueConfig `json:"merge_queue,omitempty"`
+
+	// Include lists JSON files whose agents and role_agents are merged into
+	// these settings on load. Paths are relative to this file; entries
+	// defined here take precedence. Example: ["../shared-agents.json"]
+	Include []string `json:"include,omitempty"`
+
+	included *includedEntries // entries merged from Include (not saved)
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -198,6 +209,10 @@ type WorkflowConfig struct {
 	// DefaultFormula is the ritual to use when `hd ritual run` is called without arguments.
 	// If empty, no default is set and a ritual name must be provided.
 	DefaultFormula string `json:"default_formula,omitempty"`
+
+	// AllowedFormulas restricts which rituals `hd ritual run` may run in this warband.
+	// If empty, any ritual may run.
+	AllowedFormulas []string `json:"allowed_formulas,omitempty"`
 }
 
 // RigSettings represents per-warband behavioral configuration (settings/config.json).
@@ -229,6 +244,18 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific warband.
 	// Example: {"witness": "claude-haiku", "raider": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Account selects the account (a handle in warchief/accounts.json) that
+	// sessions in this warband use, e.g. for a warband that works against a
+	// customer's repo. It overrides the accounts config's default and pool
+	// strategy, but not HD_ACCOUNT or --account.
+	Account string `json:"account,omitempty"`
+
+	// Include lists JSON files whose agents and role_agents are merged into
+	// these settings on load, as for TownSettings.Include.
+	Include []string `json:"include,omitempty"`
+
+	included *includedEntries // entries merged from Include (not saved)
 }
 
 // CrewConfig represents clan workspace settings for a warband.
@@ -262,11 +289,23 @@ type RuntimeConfig struct {
 	// Empty array [] means no args (not "use defaults").
 	Args []string `json:"args"`
 
+	// Env holds extra environment variables exported when the agent starts.
+	// Horde's own variables (HD_ROLE, HD_ROOT, ...) take precedence.
+	// Args and Env values may reference secrets as ${secret:NAME}, resolved
+	// at startup from the environment or OS keyring (see ResolveSecret), so
+	// credentials never need to be stored here.
+	Env map[string]string `json:"env,omitempty"`
+
 	// InitialPrompt is an optional first message to send after startup.
 	// For claude, this is passed as the prompt argument.
 	// Empty by default (hooks handle context).
 	InitialPrompt string `json:"initial_prompt,omitempty"`
 
+	// PromptFile names a file holding the initial prompt, used instead of
+	// InitialPrompt by ResolveInitialPrompt. Relative paths are from the
+	// encampment root.
+	PromptFile string `json:"prompt_file,omitempty"`
+
 	// PromptMode controls how prompts are passed to the runtime.
 	// Supported values: "arg" (append prompt arg), "none" (ignore prompt).
 	// Default: "arg" for claude/generic, "none" for codex.
@@ -585,6 +624,17 @@ func quoteForShell(s string) string {
 	return `"` + escaped + `"`
 }
 
+// quoteEnvValue quotes an environment value for an `export K=V` prefix.
+// Values made only of characters the shell leaves alone are returned as-is;
+// anything else is single-quoted so spaces, $, backticks, quotes and
+// operators in it (e.g. a resolved secret) reach the agent verbatim.
+func quoteEnvValue(v string) string {
+	if v != "" && strings.Trim(v, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:@%+,=") == "" {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
 // ThemeConfig represents tmux theme settings for a warband.
 type ThemeConfig struct {
 	// Name picks from the default palette (e.g., "ocean", "forest").
@@ -712,10 +762,18 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 // This enables Horde to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
 	Version  int                `json:"version"`  // schema version
-	Accounts map[string]Account `json:"accounts"` // handle -> account details
-	Default  string             `json:"default"`  // default account handle
+	Accounts map[string]Account `json:"accounts"`           // handle -> account details
+	Default  string             `json:"default"`            // default account handle
+	Strategy string             `json:"strategy,omitempty"` // account selection strategy (default: "default")
 }
 
+// Account selection strategy constants, used by ResolveAccountConfigDirWithStrategy.
+const (
+	AccountStrategyDefault    = "default"     // always the default account
+	AccountStrategyRoundRobin = "round-robin" // accounts in turn, by handle
+	AccountStrategyLRU        = "lru"         // least recently used account
+)
+
 // Account represents a single Claude Code account.
 type Account struct {
 	Email       string `json:"email"`                 // account email
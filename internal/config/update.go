@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrConflict is returned by UpdateRigSettings when the settings file kept
+// changing while it was being updated.
+var ErrConflict = errors.New("config file changed concurrently")
+
+// updateAttempts is how many times UpdateRigSettings re-reads and retries
+// after a concurrent change before giving up with ErrConflict.
+const updateAttempts = 3
+
+// UpdateRigSettings applies mutate to the warband settings at path with
+// read-modify-write semantics: it loads the file (or starts from
+// NewRigSettings if there is none), calls mutate, validates, and replaces
+// the file atomically, but only if nobody else changed it since it was read,
+// judged by its modification time and size. On a concurrent change it starts
+// over from the new contents, so mutate must be safe to call more than once;
+// after a few attempts it returns ErrConflict. An error from mutate aborts
+// the update and is returned as is.
+func UpdateRigSettings(path string, mutate func(*RigSettings) error) error {
+	for attempt := 0; attempt < updateAttempts; attempt++ {
+		before, err := statFileVersion(path)
+		if err != nil {
+			return err
+		}
+
+		settings, err := LoadRigSettings(path)
+		if errors.Is(err, ErrNotFound) {
+			settings = NewRigSettings()
+		} else if err != nil {
+			return err
+		}
+		if err := mutate(settings); err != nil {
+			return err
+		}
+		data, err := encodeRigSettings(path, settings)
+		if err != nil {
+			return err
+		}
+
+		err = replaceIfUnchanged(path, data, before)
+		if errors.Is(err, ErrConflict) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrConflict, path)
+}
+
+// fileVersion identifies the state of a file for optimistic concurrency.
+type fileVersion struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// equal reports whether v and o are the same version.
+func (v fileVersion) equal(o fileVersion) bool {
+	return v.exists == o.exists && v.size == o.size && v.modTime.Equal(o.modTime)
+}
+
+// statFileVersion returns the current version of path; a missing file is a
+// version of its own.
+func statFileVersion(path string) (fileVersion, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fileVersion{}, nil
+	}
+	if err != nil {
+		return fileVersion{}, fmt.Errorf("checking %s: %w", path, err)
+	}
+	return fileVersion{exists: true, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// replaceIfUnchanged writes data to a temporary file beside path and renames
+// it over path, unless path no longer matches before, in which case it
+// returns ErrConflict and leaves path alone.
+func replaceIfUnchanged(path string, data []byte, before fileVersion) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // already failing
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil { //nolint:gosec // G302: settings files don't contain secrets
+		tmp.Close() //nolint:errcheck,gosec // already failing
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+
+	now, err := statFileVersion(path)
+	if err != nil {
+		return err
+	}
+	if !now.equal(before) {
+		return ErrConflict
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateRigSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates missing file", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "settings", "config.json")
+		err := UpdateRigSettings(path, func(s *RigSettings) error {
+			s.Workflow = &WorkflowConfig{DefaultFormula: "shiny"}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateRigSettings: %v", err)
+		}
+		got, err := LoadRigSettings(path)
+		if err != nil {
+			t.Fatalf("LoadRigSettings: %v", err)
+		}
+		if got.GetDefaultFormula() != "shiny" || got.Type != "warband-settings" {
+			t.Errorf("saved settings = %+v, want defaults plus workflow", got)
+		}
+	})
+
+	t.Run("retries after concurrent change", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := SaveRigSettings(path, NewRigSettings()); err != nil {
+			t.Fatal(err)
+		}
+
+		calls := 0
+		err := UpdateRigSettings(path, func(s *RigSettings) error {
+			calls++
+			if calls == 1 {
+				// Another writer gets in between our read and write.
+				other := NewRigSettings()
+				other.Theme = &ThemeConfig{Name: "ocean"}
+				if err := SaveRigSettings(path, other); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s.Workflow = &WorkflowConfig{DefaultFormula: "shiny"}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateRigSettings: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("mutate called %d times, want 2", calls)
+		}
+		got, err := LoadRigSettings(path)
+		if err != nil {
+			t.Fatalf("LoadRigSettings: %v", err)
+		}
+		if got.Theme == nil || got.Theme.Name != "ocean" || got.GetDefaultFormula() != "shiny" {
+			t.Errorf("saved settings lost an update: theme=%+v workflow=%+v", got.Theme, got.Workflow)
+		}
+	})
+
+	t.Run("gives up with ErrConflict", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := SaveRigSettings(path, NewRigSettings()); err != nil {
+			t.Fatal(err)
+		}
+
+		calls := 0
+		err := UpdateRigSettings(path, func(s *RigSettings) error {
+			calls++
+			other := NewRigSettings()
+			other.Namepool.Names = []string{strings.Repeat("n", calls)} // a different size every time
+			return SaveRigSettings(path, other)
+		})
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("err = %v, want ErrConflict", err)
+		}
+		if calls != updateAttempts {
+			t.Errorf("mutate called %d times, want %d", calls, updateAttempts)
+		}
+	})
+
+	t.Run("mutate error aborts", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "config.json")
+		boom := errors.New("boom")
+		if err := UpdateRigSettings(path, func(*RigSettings) error { return boom }); !errors.Is(err, boom) {
+			t.Errorf("err = %v, want boom", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("file written despite mutate error: %v", err)
+		}
+	})
+}
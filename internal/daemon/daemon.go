@@ -891,7 +891,10 @@ func (d *Daemon) restartRaiderSession(rigName, raiderName, sessionName string) e
 
 	// Launch Claude with environment exported inline
 	// Pass rigPath so warband agent settings are honored (not encampment-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+	startCmd, err := config.BuildStartupCommandWithAgentOverride(envVars, rigPath, "", "")
+	if err != nil {
+		return fmt.Errorf("building startup command: %w", err)
+	}
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
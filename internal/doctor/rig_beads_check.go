@@ -159,10 +159,10 @@ func (c *RigRelicsCheck) Fix(ctx *CheckContext) error {
 			fields := &relics.RigFields{
 				Repo:   gitURL,
 				Prefix: info.prefix,
-				State:  "active",
+				State:  relics.RigStateActive,
 			}
 
-			if _, err := bd.CreateRigBead(rigBeadID, rigName, fields); err != nil {
+			if _, err := bd.EnsureRigBead(rigBeadID, rigName, fields); err != nil {
 				return fmt.Errorf("creating %s: %w", rigBeadID, err)
 			}
 		}
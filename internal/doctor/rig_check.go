@@ -3,6 +3,7 @@ package doctor
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -1198,6 +1199,69 @@ func (c *BareRepoRefspecCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// RigSettingsFieldsCheck loads the warband's settings strictly, so misspelled
+// keys (which normal loading silently drops) are reported.
+type RigSettingsFieldsCheck struct {
+	BaseCheck
+}
+
+// NewRigSettingsFieldsCheck creates a new warband settings field check.
+func NewRigSettingsFieldsCheck() *RigSettingsFieldsCheck {
+	return &RigSettingsFieldsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "warband-settings-fields",
+			CheckDescription: "Check warband settings for unknown (misspelled) keys",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run loads settings/config.json with unknown fields rejected.
+func (c *RigSettingsFieldsCheck) Run(ctx *CheckContext) *CheckResult {
+	rigPath := ctx.RigPath()
+	if rigPath == "" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "No warband specified",
+		}
+	}
+
+	settingsPath := config.RigSettingsPath(rigPath)
+	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No warband settings file",
+		}
+	}
+
+	defer config.SetStrictLoading(config.SetStrictLoading(true))
+	if _, err := config.LoadRigSettings(settingsPath); err != nil {
+		if errors.Is(err, config.ErrUnknownField) {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusWarning,
+				Message: "Warband settings contain an unknown key (ignored at runtime)",
+				Details: []string{fmt.Sprintf("%s: %v", settingsPath, err)},
+				FixHint: "Check the key's spelling against the settings reference",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Warband settings failed to load",
+			Details: []string{fmt.Sprintf("%s: %v", settingsPath, err)},
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "Warband settings keys are all recognized",
+	}
+}
+
 // RigChecks returns all warband-level health checks.
 func RigChecks() []Check {
 	return []Check{
@@ -1212,5 +1276,6 @@ func RigChecks() []Check {
 		NewRaiderClonesValidCheck(),
 		NewRelicsConfigValidCheck(),
 		NewRelicsRedirectCheck(),
+		NewRigSettingsFieldsCheck(),
 	}
 }
@@ -112,19 +112,11 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix updates outdated and missing rituals.
 func (c *FormulaCheck) Fix(ctx *CheckContext) error {
-	updated, skipped, reinstalled, err := ritual.UpdateFormulas(ctx.TownRoot)
-	if err != nil {
+	// The doctor framework will re-run the check after fix,
+	// so the update result doesn't need to be reported here
+	if _, err := ritual.UpdateFormulas(ctx.TownRoot); err != nil {
 		return err
 	}
 
-	// Log what was done (caller will re-run check to show new status)
-	if updated > 0 || reinstalled > 0 || skipped > 0 {
-		// The doctor framework will re-run the check after fix
-		// so we don't need to log here
-		_ = updated
-		_ = reinstalled
-		_ = skipped
-	}
-
 	return nil
 }
@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/deeklead/horde/internal/config"
+)
+
+// RoleAgentsCheck warns when a warband's role_agents shadow a different
+// encampment role_agents entry. The warband setting wins, which surprises
+// operators who edit the encampment setting expecting it to apply.
+type RoleAgentsCheck struct {
+	BaseCheck
+}
+
+// NewRoleAgentsCheck creates a new role_agents shadowing check.
+func NewRoleAgentsCheck() *RoleAgentsCheck {
+	return &RoleAgentsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "role-agents",
+			CheckDescription: "Check for warband role_agents that shadow encampment role_agents",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run reports every warband role whose agent differs from the encampment's.
+func (c *RoleAgentsCheck) Run(ctx *CheckContext) *CheckResult {
+	var details []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigName := filepath.Base(rigPath)
+		for _, conflict := range config.FindRoleAgentConflicts(ctx.TownRoot, rigPath) {
+			winner := conflict.Winner
+			if winner == "" {
+				winner = "neither is valid"
+			} else {
+				winner += " wins"
+			}
+			details = append(details, fmt.Sprintf("%s: role_agents[%s] is %s in the warband but %s in the encampment (%s)",
+				rigName, conflict.Role, conflict.RigAgent, conflict.TownAgent, winner))
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No warband role_agents shadow the encampment's",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d role_agents setting(s) shadowed by a warband", len(details)),
+		Details: details,
+		FixHint: "Remove the warband entry to use the encampment's, or run 'hd warband explain-agent <role> <warband>'",
+	}
+}
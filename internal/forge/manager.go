@@ -538,9 +538,17 @@ func (m *Manager) getMergeConfig() MergeConfig {
 		return mergeConfig
 	}
 
-	// Apply merge_queue config if present
-	if settings.MergeQueue != nil {
-		mq := settings.MergeQueue
+	// Apply merge_queue config if present, layered over the encampment default
+	townRoot := filepath.Dir(m.warband.Path)
+	townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = nil
+	}
+	if settings.MergeQueue != nil || (townSettings != nil && townSettings.MergeQueue != nil) {
+		mq, err := config.ResolveMergeQueueConfig(townSettings, settings)
+		if err != nil {
+			return mergeConfig
+		}
 		mergeConfig.TestCommand = mq.TestCommand
 		mergeConfig.RunTests = mq.RunTests
 		mergeConfig.DeleteMergedBranches = mq.DeleteMergedBranches
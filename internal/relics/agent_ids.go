@@ -4,6 +4,8 @@ package relics
 import (
 	"fmt"
 	"strings"
+
+	"github.com/deeklead/horde/internal/constants"
 )
 
 // TownRelicsPrefix is the prefix used for encampment-level agent relics stored in ~/horde/.relics/.
@@ -236,11 +238,35 @@ func IsAgentSessionBead(beadID string) bool {
 	if !ok {
 		return false
 	}
-	// Known agent roles
-	switch role {
-	case "warchief", "shaman", "witness", "forge", "clan", "raider", "dog":
-		return true
-	default:
-		return false
+	return IsKnownAgentRole(role)
+}
+
+// knownAgentRoles lists the role types an agent bead may have: the agent
+// roles, plus "dog" for encampment-level dog agents.
+var knownAgentRoles = []string{
+	constants.RoleWarchief,
+	constants.RoleShaman,
+	constants.RoleWitness,
+	constants.RoleForge,
+	constants.RoleCrew,
+	constants.RoleRaider,
+	"dog",
+}
+
+// IsKnownAgentRole returns true if role is a recognized agent role type.
+func IsKnownAgentRole(role string) bool {
+	for _, r := range knownAgentRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAgentRole returns an error if role is not a recognized agent role type.
+func ValidateAgentRole(role string) error {
+	if IsKnownAgentRole(role) {
+		return nil
 	}
+	return fmt.Errorf("invalid agent role_type %q (must be one of: %s)", role, strings.Join(knownAgentRoles, ", "))
 }
@@ -89,3 +89,69 @@ func TestDogRoleBeadIDTown(t *testing.T) {
 		t.Errorf("DogRoleBeadIDTown() = %q, want %q", got, want)
 	}
 }
+
+// TestValidateAgentRole tests that only known role types are accepted.
+func TestValidateAgentRole(t *testing.T) {
+	for _, role := range []string{"warchief", "shaman", "witness", "forge", "clan", "raider", "dog"} {
+		if err := ValidateAgentRole(role); err != nil {
+			t.Errorf("ValidateAgentRole(%q) = %v, want nil", role, err)
+		}
+	}
+	for _, role := range []string{"", "raidr", "Raider", "polecat"} {
+		if err := ValidateAgentRole(role); err == nil {
+			t.Errorf("ValidateAgentRole(%q) = nil, want error", role)
+		}
+	}
+}
+
+// TestCreateAgentBeadRejectsInvalidRole tests that an invalid role type is
+// rejected before rl is invoked.
+func TestCreateAgentBeadRejectsInvalidRole(t *testing.T) {
+	bd := New(t.TempDir())
+	fields := &AgentFields{RoleType: "raidr", Warband: "horde", AgentState: "spawning"}
+
+	if _, err := bd.CreateAgentBead("hd-horde-raidr-toast", "Toast", fields); err == nil {
+		t.Error("CreateAgentBead with invalid role: expected error")
+	}
+	if _, err := bd.CreateOrReopenAgentBead("hd-horde-raidr-toast", "Toast", fields); err == nil {
+		t.Error("CreateOrReopenAgentBead with invalid role: expected error")
+	}
+}
+
+func TestIsKnownAgentState(t *testing.T) {
+	for _, state := range []string{"spawning", "working", "running", "idle", "done", "stuck", "awaiting-gate", "closed"} {
+		if !IsKnownAgentState(state) {
+			t.Errorf("IsKnownAgentState(%q) = false, want true", state)
+		}
+	}
+	for _, state := range []string{"", "Idle", "sleeping"} {
+		if IsKnownAgentState(state) {
+			t.Errorf("IsKnownAgentState(%q) = true, want false", state)
+		}
+	}
+}
+
+// TestSetAgentStateRejectsInvalidState tests that unknown states, and states
+// observed from tmux rather than stored, are rejected before rl is invoked.
+func TestSetAgentStateRejectsInvalidState(t *testing.T) {
+	bd := New(t.TempDir())
+	for _, state := range []string{"sleeping", AgentStateRunning, AgentStateIdle, AgentStateDead} {
+		if err := bd.SetAgentState("hd-horde-raider-toast", state); err == nil {
+			t.Errorf("SetAgentState(%q): expected error", state)
+		}
+	}
+}
+
+// TestAgentFieldsLastActivity tests that the heartbeat TouchAgent records
+// survives a format/parse round trip alongside the other fields.
+func TestAgentFieldsLastActivity(t *testing.T) {
+	fields := &AgentFields{RoleType: "raider", Warband: "horde", AgentState: "working", LastActivity: "2026-01-02T03:04:05Z"}
+	got := ParseAgentFields(FormatAgentDescription("Toast", fields))
+	if got.LastActivity != fields.LastActivity || got.AgentState != "working" {
+		t.Errorf("round trip = %+v, want last_activity %s", got, fields.LastActivity)
+	}
+
+	if got := ParseAgentFields(FormatAgentDescription("Toast", &AgentFields{RoleType: "raider"})); got.LastActivity != "" {
+		t.Errorf("unset last_activity parsed as %q, want empty", got.LastActivity)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/deeklead/horde/internal/runtime"
 )
@@ -20,8 +21,26 @@ import (
 var (
 	ErrNotInstalled = errors.New("bd not installed: run 'pip install relics-cli' or see https://github.com/anthropics/relics")
 	ErrNotFound     = errors.New("issue not found")
+	ErrNoPrefix     = errors.New("no issue prefix configured")
+
+	// ErrUnavailable means rl couldn't reach the relics database that holds
+	// an issue (database locked or unopenable, connection refused). Unlike
+	// ErrNotFound it says nothing about whether the issue exists, so callers
+	// may retry.
+	ErrUnavailable = errors.New("relics unavailable")
 )
 
+// unavailablePatterns are rl stderr fragments reporting that an existing
+// database couldn't be reached, as opposed to the issue not existing in it.
+// A missing .relics directory or an unrouted prefix isn't among them, since
+// retrying won't fix either.
+var unavailablePatterns = []string{
+	"database is locked",
+	"unable to open database",
+	"failed to open database",
+	"connection refused",
+}
+
 // Issue represents a relics issue.
 type Issue struct {
 	ID          string   `json:"id"`
@@ -47,6 +66,11 @@ type Issue struct {
 	RoleBead   string `json:"role_bead,omitempty"`   // Role definition bead (shared)
 	AgentState string `json:"agent_state,omitempty"` // Agent lifecycle state (spawning, working, done, stuck)
 
+	// Event relics only (type=event)
+	EventKind string `json:"event_kind,omitempty"` // e.g., "session.ended"
+	Target    string `json:"target,omitempty"`     // what the event is about, e.g., a work item ID
+	Payload   string `json:"payload,omitempty"`    // JSON event data
+
 	// Counts from list output
 	DependencyCount int `json:"dependency_count,omitempty"`
 	DependentCount  int `json:"dependent_count,omitempty"`
@@ -57,6 +81,16 @@ type Issue struct {
 	Dependents   []IssueDep `json:"dependents,omitempty"`
 }
 
+// HasLabel reports whether the issue carries the given label.
+func (i *Issue) HasLabel(label string) bool {
+	for _, l := range i.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 // IssueDep represents a dependency or dependent issue with its relation.
 type IssueDep struct {
 	ID             string `json:"id"`
@@ -69,20 +103,25 @@ type IssueDep struct {
 
 // ListOptions specifies filters for listing issues.
 type ListOptions struct {
-	Status     string // "open", "closed", "all"
-	Type       string // Deprecated: use Label instead. "task", "bug", "feature", "epic"
-	Label      string // Label filter (e.g., "gt:agent", "gt:merge-request")
-	Priority   int    // 0-4, -1 for no filter
-	Parent     string // filter by parent ID
-	Assignee   string // filter by assignee (e.g., "horde/Toast")
-	NoAssignee bool   // filter for issues with no assignee
+	Status     string   // "open", "closed", "all"
+	Type       string   // Deprecated: use Label instead. "task", "bug", "feature", "epic"
+	Label      string   // Label filter (e.g., "gt:agent", "gt:merge-request")
+	Labels     []string // Additional label filters; issues must have ALL of them
+	Priority   int      // 0-4, -1 for no filter
+	Parent     string   // filter by parent ID
+	Assignee   string   // filter by assignee (e.g., "horde/Toast")
+	NoAssignee bool     // filter for issues with no assignee
+	IssueType  string   // rl issue type filter (e.g., "event"), passed as --type
+	Limit      int      // max issues to return; 0 = unlimited
+	Offset     int      // skip this many issues before returning results (for paging)
 }
 
 // CreateOptions specifies options for creating an issue.
 type CreateOptions struct {
+	ID          string // Explicit issue ID (e.g., "hd-warband-horde"); generated by rl if empty
 	Title       string
-	Type        string // "task", "bug", "feature", "epic"
-	Priority    int    // 0-4
+	Type        string // "task", "bug", "feature", "epic"; see IssueTypes
+	Priority    int    // 0-4, or PriorityNone (-1) for no priority
 	Description string
 	Parent      string
 	Actor       string // Who is creating this issue (populates created_by)
@@ -113,6 +152,12 @@ type SyncStatus struct {
 type Relics struct {
 	workDir  string
 	relicsDir string // Optional RELICS_DIR override for cross-database access
+
+	prefixMu sync.Mutex
+	prefix   string // Cached issue prefix (see Prefix)
+
+	summaryMu sync.Mutex
+	summary   *RelicsSummary // Cached summary (see CachedSummary)
 }
 
 // New creates a new Relics wrapper for the given directory.
@@ -126,22 +171,68 @@ func NewWithRelicsDir(workDir, relicsDir string) *Relics {
 	return &Relics{workDir: workDir, relicsDir: relicsDir}
 }
 
+// Prefix returns the issue prefix (e.g., "hd", without the trailing hyphen)
+// from the resolved relics directory's config.yaml. The result is cached
+// after the first successful read.
+func (b *Relics) Prefix() (string, error) {
+	b.prefixMu.Lock()
+	defer b.prefixMu.Unlock()
+	if b.prefix != "" {
+		return b.prefix, nil
+	}
+
+	relicsDir := b.relicsDir
+	if relicsDir == "" {
+		relicsDir = ResolveRelicsDir(b.workDir)
+	}
+	configPath := filepath.Join(relicsDir, "config.yaml")
+	data, err := os.ReadFile(configPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return "", fmt.Errorf("reading relics config: %w", err)
+	}
+
+	// Simple line-by-line parse of "issue-prefix: <value>" or "prefix: <value>"
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, key := range []string{"issue-prefix:", "prefix:"} {
+			if value, ok := strings.CutPrefix(line, key); ok {
+				value = strings.Trim(strings.TrimSpace(value), `"'`)
+				if value = strings.TrimSuffix(value, "-"); value != "" {
+					b.prefix = value
+					return value, nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w in %s", ErrNoPrefix, configPath)
+}
+
 // run executes a rl command and returns stdout.
 func (b *Relics) run(args ...string) ([]byte, error) {
+	return b.runIn(b.workDir, b.relicsDir, args...)
+}
+
+// runIn executes a rl command in workDir against relicsDir (resolved from
+// workDir if empty) and returns stdout. It lets one Relics query other
+// warbands without constructing a wrapper per directory.
+func (b *Relics) runIn(workDir, relicsDir string, args ...string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
 	// The daemon is primarily useful for write coalescing, not reads.
 	// Use --allow-stale to prevent failures when db is out of sync with JSONL
 	// (e.g., after daemon is killed during shutdown before syncing).
 	fullArgs := append([]string{"--no-daemon", "--allow-stale"}, args...)
 	cmd := exec.Command("rl", fullArgs...) //nolint:gosec // G204: rl is a trusted internal tool
-	cmd.Dir = b.workDir
+	cmd.Dir = workDir
 
 	// Always explicitly set RELICS_DIR to prevent inherited env vars from
 	// causing prefix mismatches. Use explicit relicsDir if set, otherwise
 	// resolve from working directory.
-	relicsDir := b.relicsDir
 	if relicsDir == "" {
-		relicsDir = ResolveRelicsDir(b.workDir)
+		relicsDir = ResolveRelicsDir(workDir)
 	}
 	cmd.Env = append(os.Environ(), "RELICS_DIR="+relicsDir)
 
@@ -172,10 +263,17 @@ func (b *Relics) Run(args ...string) ([]byte, error) {
 }
 
 // wrapError wraps rl errors with context.
-// ZFC: Avoid parsing stderr to make decisions. Transport errors to agents instead.
-// Exception: ErrNotInstalled (exec.ErrNotFound) and ErrNotFound (issue lookup) are
-// acceptable as they enable basic error handling without decision-making.
 func (b *Relics) wrapError(err error, stderr string, args []string) error {
+	return WrapError(err, stderr, args)
+}
+
+// WrapError wraps the error from an rl invocation with args, classifying it
+// from stderr. It is exported for callers that run rl directly.
+// ZFC: Avoid parsing stderr to make decisions. Transport errors to agents instead.
+// Exception: ErrNotInstalled (exec.ErrNotFound), ErrUnavailable (retryable
+// lookup failure) and ErrNotFound (issue lookup) are acceptable as they enable
+// basic error handling without decision-making.
+func WrapError(err error, stderr string, args []string) error {
 	stderr = strings.TrimSpace(stderr)
 
 	// Check for rl not installed
@@ -183,6 +281,13 @@ func (b *Relics) wrapError(err error, stderr string, args []string) error {
 		return ErrNotInstalled
 	}
 
+	lower := strings.ToLower(stderr)
+	for _, pattern := range unavailablePatterns {
+		if strings.Contains(lower, pattern) {
+			return fmt.Errorf("%w: %s", ErrUnavailable, stderr)
+		}
+	}
+
 	// ErrNotFound is widely used for issue lookups - acceptable exception
 	// Match various "not found" error patterns from bd
 	if strings.Contains(stderr, "not found") || strings.Contains(stderr, "Issue not found") ||
@@ -198,6 +303,31 @@ func (b *Relics) wrapError(err error, stderr string, args []string) error {
 
 // List returns issues matching the given options.
 func (b *Relics) List(opts ListOptions) ([]*Issue, error) {
+	return b.listIn(b.workDir, b.relicsDir, opts)
+}
+
+// ListIn is like List but queries the relics database resolved from path
+// (e.g., another warband's warchief/warband directory) instead of b's own.
+func (b *Relics) ListIn(path string, opts ListOptions) ([]*Issue, error) {
+	return b.listIn(path, "", opts)
+}
+
+func (b *Relics) listIn(workDir, relicsDir string, opts ListOptions) ([]*Issue, error) {
+	out, err := b.runIn(workDir, relicsDir, listArgs(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing rl list output: %w", err)
+	}
+
+	return issues, nil
+}
+
+// listArgs builds the rl list arguments for the given options.
+func listArgs(opts ListOptions) []string {
 	args := []string{"list", "--json"}
 
 	if opts.Status != "" {
@@ -210,6 +340,15 @@ func (b *Relics) List(opts ListOptions) ([]*Issue, error) {
 		// Deprecated: convert type to label for backward compatibility
 		args = append(args, "--label=gt:"+opts.Type)
 	}
+	// rl ANDs repeated --label flags
+	for _, label := range opts.Labels {
+		if label != "" && label != opts.Label {
+			args = append(args, "--label="+label)
+		}
+	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
+	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
 	}
@@ -222,18 +361,15 @@ func (b *Relics) List(opts ListOptions) ([]*Issue, error) {
 	if opts.NoAssignee {
 		args = append(args, "--no-assignee")
 	}
-
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
+	// Always pass --limit: rl applies its own default cap otherwise
+	if opts.Limit >= 0 {
+		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
 	}
-
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing rl list output: %w", err)
+	if opts.Offset > 0 {
+		args = append(args, fmt.Sprintf("--offset=%d", opts.Offset))
 	}
 
-	return issues, nil
+	return args
 }
 
 // ListByAssignee returns all issues assigned to a specific assignee.
@@ -311,10 +447,22 @@ func (b *Relics) ReadyWithType(issueType string) ([]*Issue, error) {
 
 // Show returns detailed information about an issue.
 func (b *Relics) Show(id string) (*Issue, error) {
-	out, err := b.run("show", id, "--json")
+	return b.showIn(b.workDir, b.relicsDir, id)
+}
+
+// ShowIn is like Show but queries the relics database resolved from path.
+func (b *Relics) ShowIn(path, id string) (*Issue, error) {
+	return b.showIn(path, "", id)
+}
+
+func (b *Relics) showIn(workDir, relicsDir, id string) (*Issue, error) {
+	out, err := b.runIn(workDir, relicsDir, "show", id, "--json")
 	if err != nil {
 		return nil, err
 	}
+	if len(out) == 0 {
+		return nil, ErrNotFound
+	}
 
 	// rl show --json returns an array with one element
 	var issues []*Issue
@@ -331,16 +479,33 @@ func (b *Relics) Show(id string) (*Issue, error) {
 
 // ShowMultiple fetches multiple issues by ID in a single rl call.
 // Returns a map of ID to Issue. Missing IDs are not included in the map.
+// Returns an error wrapping ErrUnavailable if the lookup itself failed.
 func (b *Relics) ShowMultiple(ids []string) (map[string]*Issue, error) {
+	return b.showMultipleIn(b.workDir, b.relicsDir, ids)
+}
+
+// ShowMultipleIn is like ShowMultiple but queries the relics database
+// resolved from path.
+func (b *Relics) ShowMultipleIn(path string, ids []string) (map[string]*Issue, error) {
+	return b.showMultipleIn(path, "", ids)
+}
+
+func (b *Relics) showMultipleIn(workDir, relicsDir string, ids []string) (map[string]*Issue, error) {
 	if len(ids) == 0 {
 		return make(map[string]*Issue), nil
 	}
 
 	// rl show supports multiple IDs
 	args := append([]string{"show", "--json"}, ids...)
-	out, err := b.run(args...)
+	out, err := b.runIn(workDir, relicsDir, args...)
 	if err != nil {
-		// If rl fails, return empty map (some IDs might not exist)
+		if errors.Is(err, ErrUnavailable) {
+			return nil, err
+		}
+		// Otherwise return empty map (some IDs might not exist)
+		return make(map[string]*Issue), nil
+	}
+	if len(out) == 0 {
 		return make(map[string]*Issue), nil
 	}
 
@@ -372,41 +537,64 @@ func (b *Relics) Blocked() ([]*Issue, error) {
 	return issues, nil
 }
 
-// Create creates a new issue and returns it.
-// If opts.Actor is empty, it defaults to the BD_ACTOR environment variable.
-// This ensures created_by is populated for issue provenance tracking.
-func (b *Relics) Create(opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json"}
-
-	if opts.Title != "" {
-		args = append(args, "--title="+opts.Title)
-	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
+// CountByStatus returns a tally of issues matching opts, keyed by status
+// (e.g., "open", "in_progress", "closed"), plus a "blocked" key.
+// Uses a single rl list call for the status counts. The blocked count comes
+// from rl blocked (the same source as Blocked()), restricted to issues that
+// matched opts so filters apply consistently.
+func (b *Relics) CountByStatus(opts ListOptions) (map[string]int, error) {
+	issues, err := b.List(opts)
+	if err != nil {
+		return nil, err
 	}
-	if opts.Description != "" {
-		args = append(args, "--description="+opts.Description)
+
+	blocked, err := b.Blocked()
+	if err != nil {
+		return nil, err
 	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
+
+	return countByStatus(issues, blocked), nil
+}
+
+// countByStatus tallies issues by status and counts how many of them
+// appear in the blocked set under the "blocked" key.
+func countByStatus(issues, blocked []*Issue) map[string]int {
+	counts := map[string]int{"blocked": 0}
+	listed := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		counts[issue.Status]++
+		listed[issue.ID] = true
 	}
-	if opts.Ephemeral {
-		args = append(args, "--ephemeral")
+	for _, issue := range blocked {
+		if listed[issue.ID] {
+			counts["blocked"]++
+		}
 	}
-	// Default Actor from BD_ACTOR env var if not specified
-	actor := opts.Actor
-	if actor == "" {
-		actor = os.Getenv("BD_ACTOR")
+	return counts
+}
+
+// Create creates a new issue and returns it.
+// If opts.ID is set, the issue is created with that ID instead of a generated one.
+// If opts.Actor is empty, it defaults to the BD_ACTOR environment variable.
+// This ensures created_by is populated for issue provenance tracking.
+func (b *Relics) Create(opts CreateOptions) (*Issue, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
-	if actor != "" {
-		args = append(args, "--actor="+actor)
+	return b.create(opts)
+}
+
+// createCopy is Create for options whose Type was copied from an existing
+// issue, which rl has already accepted; see CreateOptions.validate.
+func (b *Relics) createCopy(opts CreateOptions) (*Issue, error) {
+	if err := opts.validate(false); err != nil {
+		return nil, err
 	}
+	return b.create(opts)
+}
 
-	out, err := b.run(args...)
+func (b *Relics) create(opts CreateOptions) (*Issue, error) {
+	out, err := b.run(createArgs(opts)...)
 	if err != nil {
 		return nil, err
 	}
@@ -419,12 +607,13 @@ func (b *Relics) Create(opts CreateOptions) (*Issue, error) {
 	return &issue, nil
 }
 
-// CreateWithID creates an issue with a specific ID.
-// This is useful for agent relics, role relics, and other relics that need
-// deterministic IDs rather than auto-generated ones.
-func (b *Relics) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json", "--id=" + id}
+// createArgs builds the rl create arguments for the given options.
+func createArgs(opts CreateOptions) []string {
+	args := []string{"create", "--json"}
 
+	if opts.ID != "" {
+		args = append(args, "--id="+opts.ID)
+	}
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
@@ -441,6 +630,9 @@ func (b *Relics) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Parent != "" {
 		args = append(args, "--parent="+opts.Parent)
 	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
 	// Default Actor from BD_ACTOR env var if not specified
 	actor := opts.Actor
 	if actor == "" {
@@ -449,23 +641,30 @@ func (b *Relics) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if actor != "" {
 		args = append(args, "--actor="+actor)
 	}
+	return args
+}
 
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
-	}
-
-	var issue Issue
-	if err := json.Unmarshal(out, &issue); err != nil {
-		return nil, fmt.Errorf("parsing rl create output: %w", err)
-	}
-
-	return &issue, nil
+// CreateWithID creates an issue with a specific ID.
+// This is useful for agent relics, role relics, and other relics that need
+// deterministic IDs rather than auto-generated ones. It is Create with
+// opts.ID set to id.
+func (b *Relics) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	opts.ID = id
+	return b.Create(opts)
 }
 
 // Update updates an existing issue.
 func (b *Relics) Update(id string, opts UpdateOptions) error {
-	args := []string{"update", id}
+	return b.UpdateMany([]string{id}, opts)
+}
+
+// UpdateMany applies the same update to several issues in a single rl call.
+// It does nothing if ids is empty.
+func (b *Relics) UpdateMany(ids []string, opts UpdateOptions) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := append([]string{"update"}, ids...)
 
 	if opts.Title != nil {
 		args = append(args, "--title="+*opts.Title)
@@ -572,12 +771,6 @@ func (b *Relics) RemoveDependency(issue, dependsOn string) error {
 	return err
 }
 
-// Sync syncs relics with remote.
-func (b *Relics) Sync() error {
-	_, err := b.run("sync")
-	return err
-}
-
 // SyncFromMain syncs relics updates from main branch.
 func (b *Relics) SyncFromMain() error {
 	_, err := b.run("sync", "--from-main")
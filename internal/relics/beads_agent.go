@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
 // AgentFields holds structured fields for agent relics.
@@ -20,6 +21,7 @@ type AgentFields struct {
 	CleanupStatus     string // ZFC: raider self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
 	ActiveMR          string // Currently active merge request bead ID (for traceability)
 	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
+	LastActivity      string // RFC3339 time of the agent's last heartbeat (TouchAgent)
 }
 
 // Notification level constants
@@ -29,6 +31,66 @@ const (
 	NotifyMuted   = "muted"   // Silent/DND mode - batch for later
 )
 
+// Agent state constants for the agent_state field.
+const (
+	AgentStateSpawning     = "spawning"      // Session being created
+	AgentStateWorking      = "working"       // Agent has started on its work
+	AgentStateRunning      = "running"       // Session alive
+	AgentStateIdle         = "idle"          // Alive but not processing work
+	AgentStateDone         = "done"          // Work complete, awaiting cleanup
+	AgentStateStuck        = "stuck"         // Agent reported it cannot proceed
+	AgentStateAwaitingGate = "awaiting-gate" // Waiting on an external trigger
+	AgentStateStopped      = "stopped"       // Session stopped cleanly
+	AgentStateDead         = "dead"          // Session gone unexpectedly
+	AgentStateClosed       = "closed"        // Bead closed (see CloseAndClearAgentBead)
+)
+
+var knownAgentStates = []string{
+	AgentStateSpawning,
+	AgentStateWorking,
+	AgentStateRunning,
+	AgentStateIdle,
+	AgentStateDone,
+	AgentStateStuck,
+	AgentStateAwaitingGate,
+	AgentStateStopped,
+	AgentStateDead,
+	AgentStateClosed,
+}
+
+// IsKnownAgentState reports whether state is a recognized agent_state value.
+func IsKnownAgentState(state string) bool {
+	for _, s := range knownAgentStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// storedAgentStates are the agent_state values that may be recorded in
+// relics. Running, idle, and dead are observable from tmux and are never
+// stored (gt-zecmc: "discover, don't track").
+var storedAgentStates = []string{
+	AgentStateSpawning,
+	AgentStateWorking,
+	AgentStateDone,
+	AgentStateStuck,
+	AgentStateAwaitingGate,
+	AgentStateStopped,
+	AgentStateClosed,
+}
+
+// IsStoredAgentState reports whether state may be recorded in relics.
+func IsStoredAgentState(state string) bool {
+	for _, s := range storedAgentStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
 // FormatAgentDescription creates a description string from agent fields.
 func FormatAgentDescription(title string, fields *AgentFields) string {
 	if fields == nil {
@@ -78,6 +140,12 @@ func FormatAgentDescription(title string, fields *AgentFields) string {
 		lines = append(lines, "notification_level: null")
 	}
 
+	if fields.LastActivity != "" {
+		lines = append(lines, fmt.Sprintf("last_activity: %s", fields.LastActivity))
+	} else {
+		lines = append(lines, "last_activity: null")
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -119,6 +187,8 @@ func ParseAgentFields(description string) *AgentFields {
 			fields.ActiveMR = value
 		case "notification_level":
 			fields.NotificationLevel = value
+		case "last_activity":
+			fields.LastActivity = value
 		}
 	}
 
@@ -129,7 +199,14 @@ func ParseAgentFields(description string) *AgentFields {
 // The ID format is: <prefix>-<warband>-<role>-<name> (e.g., gt-horde-raider-Toast)
 // Use AgentBeadID() helper to generate correct IDs.
 // The created_by field is populated from BD_ACTOR env var for provenance tracking.
+// Returns an error without creating anything if fields.RoleType is not a known role.
 func (b *Relics) CreateAgentBead(id, title string, fields *AgentFields) (*Issue, error) {
+	if fields != nil {
+		if err := ValidateAgentRole(fields.RoleType); err != nil {
+			return nil, err
+		}
+	}
+
 	description := FormatAgentDescription(title, fields)
 
 	args := []string{"create", "--json",
@@ -323,6 +400,27 @@ func (b *Relics) ClearBannerBead(agentBeadID string) error {
 	return nil
 }
 
+// TouchAgent records now as the agent bead's last_activity field. Raiders
+// call it as a heartbeat (hd agents heartbeat) so the worker age shown in raid
+// status reflects actual liveness rather than whenever rl last happened to
+// write the bead.
+func (b *Relics) TouchAgent(id string) error {
+	// First get current issue to preserve other fields
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	fields := ParseAgentFields(issue.Description)
+	fields.LastActivity = time.Now().UTC().Format(time.RFC3339)
+	description := FormatAgentDescription(issue.Title, fields)
+
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return fmt.Errorf("touching agent %s: %w", id, err)
+	}
+	return nil
+}
+
 // UpdateAgentCleanupStatus updates the cleanup_status field in an agent bead.
 // This is called by the raider to self-report its git state (ZFC compliance).
 // Valid statuses: clean, has_uncommitted, has_stash, has_unpushed
@@ -388,6 +486,17 @@ func (b *Relics) UpdateAgentNotificationLevel(id string, level string) error {
 	return b.Update(id, UpdateOptions{Description: &description})
 }
 
+// SetAgentState records a mid-life agent_state transition (e.g.,
+// working→done) through UpdateAgentState, so it lands in the agent_state
+// column rather than the description and leaves the other fields alone.
+// Only stored states are accepted (see IsStoredAgentState).
+func (b *Relics) SetAgentState(id, newState string) error {
+	if !IsStoredAgentState(newState) {
+		return fmt.Errorf("invalid agent state %q: must be one of %s", newState, strings.Join(storedAgentStates, ", "))
+	}
+	return b.UpdateAgentState(id, newState, nil)
+}
+
 // GetAgentNotificationLevel returns the notification level for an agent.
 // Returns "normal" if not set (the default).
 func (b *Relics) GetAgentNotificationLevel(id string) (string, error) {
@@ -449,7 +558,7 @@ func (b *Relics) CloseAndClearAgentBead(id, reason string) error {
 	fields.BannerBead = ""     // Clear banner_bead
 	fields.ActiveMR = ""     // Clear active_mr
 	fields.CleanupStatus = "" // Clear cleanup_status
-	fields.AgentState = "closed"
+	fields.AgentState = AgentStateClosed
 
 	// Update description with cleared fields
 	description := FormatAgentDescription(issue.Title, fields)
@@ -0,0 +1,51 @@
+// Package relics provides checks that a bead ID belongs to a relics database.
+package relics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrWrongWarband indicates a bead ID was used against a relics database
+// other than the one that owns its prefix.
+var ErrWrongWarband = errors.New("bead belongs to another warband")
+
+// BelongsHere reports whether id carries this database's issue prefix, i.e.
+// whether rl commands for it should be run against this Relics at all.
+// Cross-warband callers can check it first instead of reading a not-found
+// error from the wrong database as "the bead doesn't exist".
+//
+// Returns an error if the prefix can't be read or id has no prefix.
+func (b *Relics) BelongsHere(id string) (bool, error) {
+	if ExtractPrefix(id) == "" {
+		return false, fmt.Errorf("bead ID %q has no prefix", id)
+	}
+	prefix, err := b.Prefix()
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(id, prefix+"-"), nil
+}
+
+// CheckBelongsHere is BelongsHere as a precondition: it returns nil if id
+// belongs to this database, and otherwise an error wrapping ErrWrongWarband
+// that names the owning warband from the routes in townRoot, e.g.
+// "bead gt-x belongs to warband horde, not this database (prefix hd)".
+func (b *Relics) CheckBelongsHere(townRoot, id string) error {
+	ok, err := b.BelongsHere(id)
+	if err != nil || ok {
+		return err
+	}
+	prefix, _ := b.Prefix() // cached by BelongsHere
+
+	rigName, _, err := ResolveWarbandForID(townRoot, id)
+	if err != nil {
+		return fmt.Errorf("%w: bead %s is not in this database (prefix %s)", ErrWrongWarband, id, prefix)
+	}
+	owner := "warband " + rigName
+	if rigName == "" {
+		owner = "the encampment"
+	}
+	return fmt.Errorf("%w: bead %s belongs to %s, not this database (prefix %s)", ErrWrongWarband, id, owner, prefix)
+}
@@ -0,0 +1,42 @@
+package relics
+
+// Children returns the issues whose parent is parentID, of any status, in
+// the order rl lists them. Parent/child is the structural epic hierarchy set
+// by CreateOptions.Parent, separate from tracks/blocks dependencies (see
+// DependencyTree).
+func (b *Relics) Children(parentID string) ([]*Issue, error) {
+	return b.List(ListOptions{Status: "all", Parent: parentID, Priority: -1})
+}
+
+// Descendants returns every issue below parentID: its children, their
+// children, and so on, breadth-first. Each issue appears once, even if the
+// hierarchy loops back on itself.
+func (b *Relics) Descendants(parentID string) ([]*Issue, error) {
+	return collectDescendants(parentID, b.Children)
+}
+
+// collectDescendants walks the hierarchy below root breadth-first, calling
+// children once per issue.
+func collectDescendants(root string, children func(id string) ([]*Issue, error)) ([]*Issue, error) {
+	var result []*Issue
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		kids, err := children(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, kid := range kids {
+			if seen[kid.ID] {
+				continue
+			}
+			seen[kid.ID] = true
+			result = append(result, kid)
+			queue = append(queue, kid.ID)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+// Package relics provides race-free claiming of issues.
+package relics
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// claimLockFile serializes Claim calls against the same relics database.
+const claimLockFile = "claim.lock"
+
+// Claim moves an open, unassigned issue to in_progress with the given
+// assignee. It returns false, with no error, if the issue is already
+// assigned or no longer open, so two callers racing for the same issue
+// can't both win. Claiming an issue the assignee already holds succeeds.
+//
+// rl has no conditional update, so claims are serialized with a lock file
+// in the relics directory, and the issue is re-read after the write to
+// catch assignments made outside Claim.
+func (b *Relics) Claim(id, assignee string) (bool, error) {
+	if assignee == "" {
+		return false, fmt.Errorf("%w: assignee is required to claim %s", ErrInvalidOptions, id)
+	}
+
+	relicsDir := b.relicsDir
+	if relicsDir == "" {
+		relicsDir = ResolveRelicsDir(b.workDir)
+	}
+	lock := flock.New(filepath.Join(relicsDir, claimLockFile))
+	if err := lock.Lock(); err != nil {
+		return false, fmt.Errorf("locking claims: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return false, err
+	}
+	switch claimState(issue, assignee) {
+	case claimHeld:
+		return true, nil
+	case claimTaken:
+		return false, nil
+	}
+
+	status := "in_progress"
+	if err := b.Update(id, UpdateOptions{Status: &status, Assignee: &assignee}); err != nil {
+		return false, err
+	}
+
+	issue, err = b.Show(id)
+	if err != nil {
+		return false, err
+	}
+	return claimState(issue, assignee) == claimHeld, nil
+}
+
+// Claim states of an issue relative to a would-be assignee.
+const (
+	claimOpen  = iota // open and unassigned: can be claimed
+	claimHeld         // in progress and assigned to the assignee
+	claimTaken        // anything else: assigned elsewhere or not open
+)
+
+// claimState reports whether assignee can claim issue, already holds it, or
+// has lost it to someone else.
+func claimState(issue *Issue, assignee string) int {
+	switch {
+	case issue.Status == "in_progress" && issue.Assignee == assignee:
+		return claimHeld
+	case issue.Status == "open" && issue.Assignee == "":
+		return claimOpen
+	default:
+		return claimTaken
+	}
+}
@@ -153,3 +153,35 @@ func (b *Relics) ListDelegationsFrom(parent string) ([]*Delegation, error) {
 
 	return delegations, nil
 }
+
+// DelegationChain returns the delegation lineage of a work unit: the
+// delegation that handed id its work, then the one that handed that parent
+// its work, and so on up to a work unit that wasn't delegated. The first
+// element's Child is id and the last element's Parent is the root. Returns
+// an empty chain if id wasn't delegated, and an error if the chain loops.
+//
+// Delegations are recorded with AddDelegation.
+func (b *Relics) DelegationChain(id string) ([]Delegation, error) {
+	return delegationChain(id, b.GetDelegation)
+}
+
+// delegationChain is the walking core of DelegationChain.
+func delegationChain(id string, get func(child string) (*Delegation, error)) ([]Delegation, error) {
+	var chain []Delegation
+	seen := map[string]bool{id: true}
+	for child := id; ; {
+		d, err := get(child)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			return chain, nil
+		}
+		chain = append(chain, *d)
+		if seen[d.Parent] {
+			return nil, fmt.Errorf("delegation chain of %s loops back to %s", id, d.Parent)
+		}
+		seen[d.Parent] = true
+		child = d.Parent
+	}
+}
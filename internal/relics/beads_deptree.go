@@ -0,0 +1,130 @@
+// Package relics provides dependency tree resolution for tree rendering.
+package relics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDepTreeDepth is the depth cap DependencyTree uses when called with
+// maxDepth <= 0.
+const DefaultDepTreeDepth = 10
+
+// DepNode is an issue in a dependency tree built by DependencyTree.
+type DepNode struct {
+	Issue          *Issue     // The issue; a stub holding only the ID if Missing
+	DependencyType string     // How the parent depends on this issue (e.g., "tracks", "blocks"); empty for the root
+	Children       []*DepNode // Dependencies, in the order rl reports them
+	Missing        bool       // Issue couldn't be fetched (deleted, or in an unrouted warband)
+	Cycle          bool       // Issue already appears above this node; its dependencies are not repeated
+	Truncated      bool       // Depth cap reached with dependencies left unexpanded
+
+	parent *DepNode
+}
+
+// DependencyTree resolves id and its dependencies, recursively, into a tree
+// for rendering multi-level hierarchies (raids tracking epics with sub-tasks
+// and blockers, and so on). Issues are fetched one batched rl show per level.
+//
+// maxDepth caps how many levels below the root are expanded (<= 0 means
+// DefaultDepTreeDepth). An issue that already appears on the path from the
+// root is marked Cycle and not expanded again. References in the
+// external:<warband>:<id> form are normalized to the plain issue ID.
+func (b *Relics) DependencyTree(id string, maxDepth int) (*DepNode, error) {
+	root, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	return buildDepTree(root, maxDepth, b.ShowMultiple)
+}
+
+// buildDepTree expands root breadth-first, calling fetch once per level with
+// the issue IDs that level needs. Issues fetched for earlier levels are reused.
+func buildDepTree(root *Issue, maxDepth int, fetch func(ids []string) (map[string]*Issue, error)) (*DepNode, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultDepTreeDepth
+	}
+
+	issues := map[string]*Issue{root.ID: root}
+	tree := &DepNode{Issue: root}
+	level := []*DepNode{tree}
+
+	for depth := 0; len(level) > 0; depth++ {
+		var expandable []*DepNode
+		for _, node := range level {
+			if node.Missing || node.Cycle || len(node.Issue.Dependencies) == 0 {
+				continue
+			}
+			if depth == maxDepth {
+				node.Truncated = true
+				continue
+			}
+			expandable = append(expandable, node)
+		}
+		if len(expandable) == 0 {
+			break
+		}
+
+		var toFetch []string
+		queued := make(map[string]bool)
+		for _, node := range expandable {
+			for _, dep := range node.Issue.Dependencies {
+				depID := normalizeDepID(dep.ID)
+				if _, ok := issues[depID]; !ok && !queued[depID] {
+					queued[depID] = true
+					toFetch = append(toFetch, depID)
+				}
+			}
+		}
+		if len(toFetch) > 0 {
+			fetched, err := fetch(toFetch)
+			if err != nil {
+				return nil, fmt.Errorf("fetching dependencies at depth %d: %w", depth+1, err)
+			}
+			for depID, issue := range fetched {
+				issues[depID] = issue
+			}
+		}
+
+		var next []*DepNode
+		for _, node := range expandable {
+			for _, dep := range node.Issue.Dependencies {
+				depID := normalizeDepID(dep.ID)
+				child := &DepNode{DependencyType: dep.DependencyType, parent: node}
+				if issue, ok := issues[depID]; ok {
+					child.Issue = issue
+				} else {
+					child.Issue = &Issue{ID: depID, Title: dep.Title, Status: dep.Status}
+					child.Missing = true
+				}
+				child.Cycle = child.onPathAbove(depID)
+				node.Children = append(node.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return tree, nil
+}
+
+// onPathAbove reports whether id belongs to one of n's ancestors.
+func (n *DepNode) onPathAbove(id string) bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p.Issue.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDepID strips the external:<warband>: prefix used for cross-warband
+// dependencies, returning the plain issue ID.
+func normalizeDepID(id string) string {
+	if strings.HasPrefix(id, "external:") {
+		if parts := strings.SplitN(id, ":", 3); len(parts) == 3 {
+			return parts[2]
+		}
+	}
+	return id
+}
@@ -0,0 +1,53 @@
+package relics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/deeklead/horde/internal/config"
+	"github.com/deeklead/horde/internal/constants"
+)
+
+// RelicsLocation is a warband's relics database, as found by DiscoverRelicsDirs.
+type RelicsLocation struct {
+	Warband   string // warband name (e.g., "horde")
+	Path      string // warband root, a working directory for rl
+	RelicsDir string // resolved .relics directory, after any redirect
+}
+
+// DiscoverRelicsDirs returns the relics location of every warband registered
+// in the encampment's warbands.json, sorted by warband name. Each warband's
+// .relics is resolved with ResolveRelicsDir, so tracked relics that redirect
+// to warchief/warband/.relics are found wherever they live. Warbands without
+// a relics directory are skipped, and an encampment with no warbands.json has
+// no locations.
+func DiscoverRelicsDirs(townRoot string) ([]RelicsLocation, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.WarchiefRigsPath(townRoot))
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading warbands: %w", err)
+	}
+
+	var locations []RelicsLocation
+	for name := range rigsConfig.Warbands {
+		rigPath := filepath.Join(townRoot, name)
+		relicsDir := ResolveRelicsDir(rigPath)
+		if info, err := os.Stat(relicsDir); err != nil || !info.IsDir() {
+			continue
+		}
+		locations = append(locations, RelicsLocation{
+			Warband:   name,
+			Path:      rigPath,
+			RelicsDir: relicsDir,
+		})
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].Warband < locations[j].Warband
+	})
+	return locations, nil
+}
@@ -0,0 +1,61 @@
+// Package relics provides duplicate agent bead detection.
+package relics
+
+import (
+	"sort"
+	"strings"
+)
+
+// FindDuplicateAgents returns the agent identities in the warband that are
+// backed by more than one bead, mapped to the sorted IDs of those beads.
+// Identities are keyed as "warband/role[/name]", e.g. "horde/clan/max", so
+// beads under different prefixes (hd-horde-clan-max, hq-horde-clan-max)
+// group together. Closed beads count, since a closed duplicate is what an
+// interrupted recreate leaves behind; tombstones don't.
+//
+// An empty rigName selects encampment-level agents (warchief, shaman, dogs).
+func (b *Relics) FindDuplicateAgents(rigName string) (map[string][]string, error) {
+	agents, err := b.ListAll(ListOptions{Label: "gt:agent", Status: "all", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	return duplicateAgents(agents, rigName), nil
+}
+
+// duplicateAgents is the grouping core of FindDuplicateAgents.
+func duplicateAgents(agents []*Issue, rigName string) map[string][]string {
+	byIdentity := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, issue := range agents {
+		if issue.Status == "tombstone" || seen[issue.ID] {
+			continue
+		}
+		seen[issue.ID] = true
+		warband, role, name, ok := ParseAgentBeadID(issue.ID)
+		if !ok || warband != rigName || !IsKnownAgentRole(role) {
+			continue
+		}
+		key := agentIdentity(warband, role, name)
+		byIdentity[key] = append(byIdentity[key], issue.ID)
+	}
+
+	duplicates := make(map[string][]string)
+	for key, ids := range byIdentity {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			duplicates[key] = ids
+		}
+	}
+	return duplicates
+}
+
+// agentIdentity joins the non-empty parts of a parsed agent bead ID.
+func agentIdentity(warband, role, name string) string {
+	var parts []string
+	for _, p := range []string{warband, role, name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
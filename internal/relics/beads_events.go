@@ -0,0 +1,91 @@
+// Package relics provides migration of legacy event relics.
+package relics
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MigrationResult reports what MigrateLegacyEvents found and did.
+type MigrationResult struct {
+	Scanned       int              // event relics examined, of any kind
+	AlreadyClosed int              // matching events that were already closed
+	Open          []*Issue         // matching events that were open when scanned
+	Closed        []string         // IDs closed by the migration (none on a dry run)
+	Failed        map[string]error // IDs that could not be closed
+}
+
+// MigrateLegacyEvents closes every open event relic whose event_kind is
+// category (e.g., "session.ended"), recording reason as the close reason.
+// With dryRun, it only reports the open events. Failures to close individual
+// events are collected in the result rather than stopping the migration.
+func (b *Relics) MigrateLegacyEvents(category, reason string, dryRun bool) (*MigrationResult, error) {
+	result := &MigrationResult{Failed: make(map[string]error)}
+	err := b.ForEachEvent(func(event *Issue) bool {
+		result.scan(event, category)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		result.close(func(id string) error { return b.CloseWithReason(reason, id) })
+	}
+	return result, nil
+}
+
+// scan counts event and records it as open if it is an open event of the
+// given category.
+func (r *MigrationResult) scan(event *Issue, category string) {
+	r.Scanned++
+	if event.EventKind != category {
+		return
+	}
+	if event.Status == "closed" {
+		r.AlreadyClosed++
+		return
+	}
+	r.Open = append(r.Open, event)
+}
+
+// close closes the open events found by scan with closeEvent.
+func (r *MigrationResult) close(closeEvent func(id string) error) {
+	for _, event := range r.Open {
+		if err := closeEvent(event.ID); err != nil {
+			r.Failed[event.ID] = err
+			continue
+		}
+		r.Closed = append(r.Closed, event.ID)
+	}
+}
+
+// ForEachEvent calls fn with the full details of each event relic, of any
+// status, a page at a time like ListAll. It stops early if fn returns false.
+func (b *Relics) ForEachEvent(fn func(*Issue) bool) error {
+	return forEachPage(ListOptions{Status: "all", IssueType: "event", Priority: -1}, b.listEvents, fn)
+}
+
+// listEvents lists one page of event relics with their details. rl list
+// doesn't include event_kind or the payload, so the page is fetched again
+// with one rl show.
+func (b *Relics) listEvents(opts ListOptions) ([]*Issue, error) {
+	listed, err := b.List(opts)
+	if err != nil || len(listed) == 0 {
+		return nil, err
+	}
+
+	ids := make([]string, len(listed))
+	for i, issue := range listed {
+		ids[i] = issue.ID
+	}
+	out, err := b.run(append([]string{"show", "--json"}, ids...)...)
+	if err != nil {
+		return nil, fmt.Errorf("showing events: %w", err)
+	}
+	var events []*Issue
+	if err := json.Unmarshal(out, &events); err != nil {
+		return nil, fmt.Errorf("parsing rl show output: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,141 @@
+// Package relics provides generic key-value metadata blocks in issue descriptions.
+package relics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Metadata blocks are delimited by HTML comments, which markdown renderers
+// hide, so blocks with different names never collide:
+//
+//	<!-- hd:meta deploy -->
+//	env: staging
+//	version: 1.2.3
+//	<!-- /hd:meta deploy -->
+const (
+	metadataOpen  = "<!-- hd:meta %s -->"
+	metadataClose = "<!-- /hd:meta %s -->"
+)
+
+// GetMetadata returns the key-value pairs in the issue's metadata block
+// named block. It returns an empty map if the issue has no such block.
+func (b *Relics) GetMetadata(id, block string) (map[string]string, error) {
+	if err := validateMetadataBlock(block); err != nil {
+		return nil, err
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := metadataBlock(issue.Description, block)
+	return ParseKeyValueFields(body), nil
+}
+
+// SetMetadata replaces the issue's metadata block named block with kv,
+// adding the block at the end of the description if it isn't there yet.
+// The rest of the description, including other blocks, is preserved. An
+// empty kv removes the block.
+func (b *Relics) SetMetadata(id, block string, kv map[string]string) error {
+	if err := validateMetadataBlock(block); err != nil {
+		return err
+	}
+	for k, v := range kv {
+		if k == "" || strings.ContainsAny(k, ":\n") || strings.TrimSpace(k) != k {
+			return fmt.Errorf("%w: invalid metadata key %q", ErrInvalidOptions, k)
+		}
+		if strings.Contains(v, "\n") {
+			return fmt.Errorf("%w: metadata value for %q contains a newline", ErrInvalidOptions, k)
+		}
+	}
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	description := UpdateDescriptionFields(issue.Description, block, kv)
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
+// ParseKeyValueFields parses "key: value" lines into a map. Keys and values
+// are trimmed; lines without a colon or with an empty value are skipped.
+// When a key repeats, the last value wins.
+func ParseKeyValueFields(text string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" || value == "" {
+			continue
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// UpdateDescriptionFields returns description with its metadata block named
+// block replaced in place by kv, formatted as sorted "key: value" lines. If
+// the block doesn't exist it is appended; if kv is empty it is removed.
+func UpdateDescriptionFields(description, block string, kv map[string]string) string {
+	open, closing := fmt.Sprintf(metadataOpen, block), fmt.Sprintf(metadataClose, block)
+
+	var formatted string
+	if len(kv) > 0 {
+		keys := make([]string, 0, len(kv))
+		for k := range kv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		lines := []string{open}
+		for _, k := range keys {
+			lines = append(lines, k+": "+kv[k])
+		}
+		lines = append(lines, closing)
+		formatted = strings.Join(lines, "\n")
+	}
+
+	before, after := description, ""
+	if start := strings.Index(description, open); start != -1 {
+		before = description[:start]
+		if end := strings.Index(description[start:], closing); end != -1 {
+			after = description[start+end+len(closing):]
+		}
+	}
+
+	// Join the pieces with blank lines, dropping the empty ones
+	var parts []string
+	for _, part := range []string{strings.TrimRight(before, "\n"), formatted, strings.TrimLeft(after, "\n")} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// metadataBlock returns the text between block's delimiters in description.
+func metadataBlock(description, block string) (string, bool) {
+	open, closing := fmt.Sprintf(metadataOpen, block), fmt.Sprintf(metadataClose, block)
+	start := strings.Index(description, open)
+	if start == -1 {
+		return "", false
+	}
+	body := description[start+len(open):]
+	end := strings.Index(body, closing)
+	if end == -1 {
+		return "", false
+	}
+	return body[:end], true
+}
+
+// validateMetadataBlock checks that block is usable as a block name: a
+// single word that can't end the delimiter comment early.
+func validateMetadataBlock(block string) error {
+	if block == "" || strings.ContainsAny(block, " \t\n") || strings.Contains(block, "--") {
+		return fmt.Errorf("%w: invalid metadata block name %q", ErrInvalidOptions, block)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Package relics provides orphaned agent bead detection.
+package relics
+
+import (
+	"sort"
+
+	"github.com/deeklead/horde/internal/session"
+)
+
+// SessionChecker reports whether a tmux session exists.
+// *tmux.Tmux satisfies this interface.
+type SessionChecker interface {
+	HasSession(name string) (bool, error)
+}
+
+// FindOrphanedAgentBeads returns the IDs of open agent beads for the warband
+// whose tmux session is not running, sorted. Only warband-level agents
+// (witness, forge, clan, raider) are considered; encampment agents and dogs
+// are ignored. Beads whose session can't be checked are not reported.
+func (b *Relics) FindOrphanedAgentBeads(t SessionChecker, rigName string) ([]string, error) {
+	agents, err := b.ListAgentRelics()
+	if err != nil {
+		return nil, err
+	}
+	return orphanedAgentBeads(agents, t, rigName), nil
+}
+
+// orphanedAgentBeads is the session-matching core of FindOrphanedAgentBeads.
+func orphanedAgentBeads(agents map[string]*Issue, t SessionChecker, rigName string) []string {
+	var orphaned []string
+	for id, issue := range agents {
+		if issue.Status == "closed" {
+			continue
+		}
+		warband, role, name, ok := ParseAgentBeadID(id)
+		if !ok || warband != rigName {
+			continue
+		}
+		sessionName := agentSessionName(warband, role, name)
+		if sessionName == "" {
+			continue
+		}
+		running, err := t.HasSession(sessionName)
+		if err != nil || running {
+			continue
+		}
+		orphaned = append(orphaned, id)
+	}
+	sort.Strings(orphaned)
+	return orphaned
+}
+
+// agentSessionName returns the tmux session name for a warband-level agent,
+// or "" if the role has no per-warband session.
+func agentSessionName(warband, role, name string) string {
+	switch role {
+	case "witness":
+		return session.WitnessSessionName(warband)
+	case "forge":
+		return session.ForgeSessionName(warband)
+	case "clan":
+		if name != "" {
+			return session.CrewSessionName(warband, name)
+		}
+	case "raider":
+		if name != "" {
+			return session.RaiderSessionName(warband, name)
+		}
+	}
+	return ""
+}
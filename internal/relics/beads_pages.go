@@ -0,0 +1,45 @@
+// Package relics provides paged iteration over large issue lists.
+package relics
+
+// listPageSize is the page size ListAll and ForEachEvent use when
+// ListOptions.Limit is unset.
+const listPageSize = 500
+
+// ListAll returns every issue matching opts, fetching them a page at a time
+// rather than with one unbounded rl list. opts.Limit sets the page size
+// (default 500) and opts.Offset where to start.
+func (b *Relics) ListAll(opts ListOptions) ([]*Issue, error) {
+	var all []*Issue
+	err := forEachPage(opts, b.List, func(issue *Issue) bool {
+		all = append(all, issue)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// forEachPage pages through list with increasing offsets, calling fn for
+// each issue, until a short page signals the end of the results. Only one
+// page is held at a time, and it stops early if fn returns false.
+func forEachPage(opts ListOptions, list func(ListOptions) ([]*Issue, error), fn func(*Issue) bool) error {
+	if opts.Limit <= 0 {
+		opts.Limit = listPageSize
+	}
+	for {
+		page, err := list(opts)
+		if err != nil {
+			return err
+		}
+		for _, issue := range page {
+			if !fn(issue) {
+				return nil
+			}
+		}
+		if len(page) < opts.Limit {
+			return nil
+		}
+		opts.Offset += len(page)
+	}
+}
@@ -112,6 +112,8 @@ func cleanRelicsRuntimeFiles(relicsDir string) error {
 		"*.db", "*.db-*", "*.db?*",
 		// Daemon runtime
 		"daemon.lock", "daemon.log", "daemon.pid", "bd.sock",
+		// Claim serialization (see Relics.Claim)
+		claimLockFile,
 		// Sync state
 		"sync-state.json", "last-touched", "metadata.json",
 		// Version tracking
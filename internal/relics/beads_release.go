@@ -0,0 +1,59 @@
+// Package relics provides bulk release of a dead agent's work.
+package relics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReleaseResult describes the issues ReleaseAgentWork released.
+type ReleaseResult struct {
+	Assignee string
+	Released []string // IDs moved back to open (or that would be, in a dry run), sorted
+	DryRun   bool
+}
+
+// ReleaseAgentWork moves every in_progress issue assigned to assignee back
+// to open and clears its assignee, in a single rl update. It is meant for
+// agents that died mid-task; unlike Release, it needs no issue IDs.
+//
+// With dryRun set, the issues are found but left untouched.
+func (b *Relics) ReleaseAgentWork(assignee string, dryRun bool) (ReleaseResult, error) {
+	result := ReleaseResult{Assignee: assignee, DryRun: dryRun}
+	if assignee == "" {
+		return result, fmt.Errorf("%w: assignee is required to release work", ErrInvalidOptions)
+	}
+
+	issues, err := b.ListAll(ListOptions{
+		Status:   "in_progress",
+		Assignee: assignee,
+		Priority: -1,
+	})
+	if err != nil {
+		return result, fmt.Errorf("listing work for %s: %w", assignee, err)
+	}
+	result.Released = agentWork(issues, assignee)
+	if dryRun || len(result.Released) == 0 {
+		return result, nil
+	}
+
+	open, none := "open", ""
+	if err := b.UpdateMany(result.Released, UpdateOptions{Status: &open, Assignee: &none}); err != nil {
+		return ReleaseResult{Assignee: assignee}, fmt.Errorf("releasing work for %s: %w", assignee, err)
+	}
+	return result, nil
+}
+
+// agentWork returns the sorted IDs of the in_progress issues in issues held
+// by exactly assignee. rl's --assignee filter is trusted only to narrow the
+// list, so an agent's work is never confused with a similarly named one's.
+func agentWork(issues []*Issue, assignee string) []string {
+	var ids []string
+	for _, issue := range issues {
+		if issue.Status == "in_progress" && issue.Assignee == assignee {
+			ids = append(ids, issue.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
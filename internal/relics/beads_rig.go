@@ -2,17 +2,35 @@
 package relics
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 )
 
+// Warband operational states stored in the state field of the identity bead.
+// Parking is normally local and lives in the wisp layer; RigStateParked is
+// only reported for a global status:parked label (see RigBeadState).
+const (
+	RigStateActive      = "active"
+	RigStateDocked      = "docked"
+	RigStateParked      = "parked"
+	RigStateArchived    = "archived"
+	RigStateMaintenance = "maintenance"
+)
+
+// Warband status labels. The layered warband config reads and writes the
+// global "status" key as a status:<value> label on the identity bead, so
+// docking keeps RigDockedLabel in step with the state field.
+const (
+	RigDockedLabel = "status:docked"
+	RigParkedLabel = "status:parked"
+)
+
 // RigFields contains the fields specific to warband identity relics.
 type RigFields struct {
 	Repo   string // Git URL for the warband's repository
 	Prefix string // Relics prefix for this warband (e.g., "hd", "rl")
-	State  string // Operational state: active, archived, maintenance
+	State  string // Operational state: active, docked, archived, maintenance
 }
 
 // FormatRigDescription formats the description field for a warband identity bead.
@@ -21,23 +39,115 @@ func FormatRigDescription(name string, fields *RigFields) string {
 		return ""
 	}
 
-	var lines []string
-	lines = append(lines, fmt.Sprintf("Warband identity bead for %s.", name))
-	lines = append(lines, "")
+	header := fmt.Sprintf("Warband identity bead for %s.", name)
+	formatted := FormatRigFields(fields)
+	if formatted == "" {
+		return header + "\n"
+	}
+	return header + "\n\n" + formatted
+}
+
+// FormatRigFields formats RigFields as key: value lines.
+// Only non-empty fields are included.
+func FormatRigFields(fields *RigFields) string {
+	if fields == nil {
+		return ""
+	}
 
+	var lines []string
 	if fields.Repo != "" {
-		lines = append(lines, fmt.Sprintf("repo: %s", fields.Repo))
+		lines = append(lines, "repo: "+fields.Repo)
 	}
 	if fields.Prefix != "" {
-		lines = append(lines, fmt.Sprintf("prefix: %s", fields.Prefix))
+		lines = append(lines, "prefix: "+fields.Prefix)
 	}
 	if fields.State != "" {
-		lines = append(lines, fmt.Sprintf("state: %s", fields.State))
+		lines = append(lines, "state: "+fields.State)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// SetRigFields updates an issue's description with the given warband fields.
+// Existing warband field lines are replaced; other content (such as the
+// identity header) is preserved. Returns the new description string.
+func SetRigFields(issue *Issue, fields *RigFields) string {
+	if issue == nil {
+		return FormatRigFields(fields)
+	}
+
+	rigKeys := map[string]bool{
+		"repo":   true,
+		"prefix": true,
+		"state":  true,
+	}
+
+	var otherLines []string
+	for _, line := range strings.Split(issue.Description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if colonIdx := strings.Index(trimmed, ":"); colonIdx != -1 {
+			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
+			if rigKeys[key] {
+				continue
+			}
+		}
+		otherLines = append(otherLines, line)
+	}
+
+	// Trim trailing blank lines so the fields follow a single separator.
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatRigFields(fields)
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
+// RigBeadState returns the operational state recorded on a warband identity
+// bead. A status:docked or status:parked label (set by docking or by the
+// global "status" config key) takes precedence over the state field.
+func RigBeadState(issue *Issue) string {
+	if issue == nil {
+		return ""
+	}
+	if issue.HasLabel(RigDockedLabel) {
+		return RigStateDocked
+	}
+	if issue.HasLabel(RigParkedLabel) {
+		return RigStateParked
+	}
+	return ParseRigFields(issue.Description).State
+}
+
+// SetRigState records state on the warband identity bead, keeping its other
+// fields. Docking also adds the status:docked label, and any other state
+// removes it, so the config layer's "status" key agrees with the state field.
+func (b *Relics) SetRigState(id, state string) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	fields := ParseRigFields(issue.Description)
+	fields.State = state
+	description := SetRigFields(issue, fields)
+
+	opts := UpdateOptions{Description: &description}
+	switch docked := issue.HasLabel(RigDockedLabel); {
+	case state == RigStateDocked && !docked:
+		opts.AddLabels = []string{RigDockedLabel}
+	case state != RigStateDocked && docked:
+		opts.RemoveLabels = []string{RigDockedLabel}
+	}
+	return b.Update(id, opts)
+}
+
 // ParseRigFields extracts warband fields from an issue's description.
 func ParseRigFields(description string) *RigFields {
 	fields := &RigFields{}
@@ -77,31 +187,50 @@ func ParseRigFields(description string) *RigFields {
 // Use RigBeadID() helper to generate correct IDs.
 // The created_by field is populated from BD_ACTOR env var for provenance tracking.
 func (b *Relics) CreateRigBead(id, title string, fields *RigFields) (*Issue, error) {
-	description := FormatRigDescription(title, fields)
+	return b.Create(CreateOptions{
+		ID:          id,
+		Title:       title,
+		Type:        "warband",
+		Priority:    PriorityNone,
+		Description: FormatRigDescription(title, fields),
+	})
+}
 
-	args := []string{"create", "--json",
-		"--id=" + id,
-		"--title=" + title,
-		"--description=" + description,
-		"--labels=gt:warband",
+// EnsureRigBead makes sure the warband identity bead exists, is open, and
+// carries the given fields. It creates the bead if absent, reopens it if
+// closed, and otherwise updates its title and description in place.
+// This is the warband analog of CreateOrReopenAgentBead: re-running warband
+// setup or a doctor repair never trips the UNIQUE constraint on the ID.
+func (b *Relics) EnsureRigBead(id, name string, fields *RigFields) (*Issue, error) {
+	existing, err := b.Show(id)
+	if errors.Is(err, ErrNotFound) {
+		return b.CreateRigBead(id, name, fields)
 	}
-
-	// Default actor from BD_ACTOR env var for provenance tracking
-	if actor := os.Getenv("BD_ACTOR"); actor != "" {
-		args = append(args, "--actor="+actor)
+	if err != nil {
+		return nil, fmt.Errorf("looking up warband identity bead: %w", err)
 	}
 
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
+	if existing.Status == "closed" {
+		if _, err := b.run("reopen", id, "--reason=re-adding warband"); err != nil {
+			if !strings.Contains(err.Error(), "already open") {
+				return nil, fmt.Errorf("reopening warband identity bead: %w", err)
+			}
+		}
 	}
 
-	var issue Issue
-	if err := json.Unmarshal(out, &issue); err != nil {
-		return nil, fmt.Errorf("parsing rl create output: %w", err)
+	updateOpts := UpdateOptions{Title: &name}
+	if fields != nil {
+		description := FormatRigDescription(name, fields)
+		updateOpts.Description = &description
+	}
+	if !existing.HasLabel("gt:warband") {
+		updateOpts.AddLabels = []string{"gt:warband"}
+	}
+	if err := b.Update(id, updateOpts); err != nil {
+		return nil, fmt.Errorf("updating warband identity bead: %w", err)
 	}
 
-	return &issue, nil
+	return b.Show(id)
 }
 
 // RigBeadIDWithPrefix generates a warband identity bead ID using the specified prefix.
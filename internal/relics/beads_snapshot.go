@@ -0,0 +1,130 @@
+// Package relics provides agent bead snapshots.
+package relics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AgentSnapshot is the captured state of a warband's agent beads, taken by
+// SnapshotAgents and applied by RestoreAgents. It marshals to JSON so it can
+// be saved before a risky operation (prune, reset, experiments with agent
+// state) and restored afterwards.
+type AgentSnapshot struct {
+	Warband string               `json:"warband"`
+	TakenAt time.Time            `json:"taken_at"`
+	Agents  []AgentSnapshotEntry `json:"agents"`
+}
+
+// AgentSnapshotEntry is one agent bead in an AgentSnapshot.
+type AgentSnapshotEntry struct {
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	Status      string       `json:"status"`
+	Description string       `json:"description"`
+	Fields      *AgentFields `json:"fields"`
+}
+
+// SnapshotAgents captures the ID, title, status, and parsed fields of every
+// agent bead in the warband, open or closed, sorted by ID. Tombstones are
+// skipped since they can't be restored. An empty rigName selects
+// encampment-level agents (warchief, shaman, dogs).
+func (b *Relics) SnapshotAgents(rigName string) (AgentSnapshot, error) {
+	agents, err := b.ListAll(ListOptions{Label: "gt:agent", Status: "all", Priority: -1})
+	if err != nil {
+		return AgentSnapshot{}, err
+	}
+	return snapshotAgents(agents, rigName, time.Now()), nil
+}
+
+// snapshotAgents is the filtering core of SnapshotAgents.
+func snapshotAgents(agents []*Issue, rigName string, now time.Time) AgentSnapshot {
+	snap := AgentSnapshot{Warband: rigName, TakenAt: now}
+	seen := make(map[string]bool)
+	for _, issue := range agents {
+		if issue.Status == "tombstone" || seen[issue.ID] {
+			continue
+		}
+		seen[issue.ID] = true
+		warband, _, _, ok := ParseAgentBeadID(issue.ID)
+		if !ok || warband != rigName {
+			continue
+		}
+		snap.Agents = append(snap.Agents, AgentSnapshotEntry{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Status:      issue.Status,
+			Description: issue.Description,
+			Fields:      ParseAgentFields(issue.Description),
+		})
+	}
+	sort.Slice(snap.Agents, func(i, j int) bool {
+		return snap.Agents[i].ID < snap.Agents[j].ID
+	})
+	return snap
+}
+
+// RestoreAgents puts each agent bead in snap back to its captured state.
+// Beads that no longer exist are recreated; closed ones are reopened
+// (CreateOrReopenAgentBead), which dodges the tombstone bug that blocks
+// recreating deleted agent beads. The captured description, status,
+// agent_state, and slots are then reapplied, and beads captured closed are
+// closed again.
+//
+// Agent beads created after the snapshot are left alone. A failure on one
+// bead doesn't stop the others; all failures are returned joined.
+func (b *Relics) RestoreAgents(snap AgentSnapshot) error {
+	var errs []error
+	for _, entry := range snap.Agents {
+		if err := b.restoreAgent(entry); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", entry.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreAgent restores a single snapshot entry.
+func (b *Relics) restoreAgent(entry AgentSnapshotEntry) error {
+	fields := entry.Fields
+	if fields == nil {
+		fields = ParseAgentFields(entry.Description)
+	}
+
+	// Recreate or reopen; this also rewrites the field block and resets the
+	// role and banner slots to the captured values.
+	if _, err := b.CreateOrReopenAgentBead(entry.ID, entry.Title, fields); err != nil {
+		return err
+	}
+
+	// Restore the description verbatim, which may carry text beyond the
+	// field block that CreateOrReopenAgentBead writes.
+	if entry.Description != "" && entry.Description != FormatAgentDescription(entry.Title, fields) {
+		description := entry.Description
+		if err := b.Update(entry.ID, UpdateOptions{Description: &description}); err != nil {
+			return err
+		}
+	}
+
+	switch entry.Status {
+	case "closed":
+		if _, err := b.run("close", entry.ID, "--reason=restored from snapshot"); err != nil {
+			return err
+		}
+		return nil
+	case "", "open":
+	default:
+		status := entry.Status
+		if err := b.Update(entry.ID, UpdateOptions{Status: &status}); err != nil {
+			return err
+		}
+	}
+
+	if fields.AgentState != "" {
+		if err := b.UpdateAgentState(entry.ID, fields.AgentState, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package relics provides detection of merge requests whose branches are gone.
+package relics
+
+import "sort"
+
+// BranchChecker reports whether a local branch exists.
+// *git.Git satisfies this interface.
+type BranchChecker interface {
+	BranchExists(name string) (bool, error)
+}
+
+// StaleMR is a merge-request bead that references a branch which no longer
+// exists, typically because it was already merged and deleted.
+type StaleMR struct {
+	Issue         *Issue
+	Fields        *MRFields
+	MissingSource bool // Fields.Branch is not a branch
+	MissingTarget bool // Fields.Target is set but not a branch
+}
+
+// FindStaleMRs returns the beads in mrs whose source branch, or target
+// branch if one is set, doesn't exist according to g, sorted by ID. Beads
+// without MR fields or a source branch are ignored, as are branches that
+// can't be checked, so an unreachable repo never marks entries stale. Each
+// distinct branch is checked once.
+func FindStaleMRs(mrs []*Issue, g BranchChecker) []*StaleMR {
+	exists := make(map[string]bool)
+	missing := func(branch string) bool {
+		if branch == "" {
+			return false
+		}
+		ok, seen := exists[branch]
+		if !seen {
+			var err error
+			if ok, err = g.BranchExists(branch); err != nil {
+				ok = true
+			}
+			exists[branch] = ok
+		}
+		return !ok
+	}
+
+	var stale []*StaleMR
+	for _, issue := range mrs {
+		fields := ParseMRFields(issue)
+		if fields == nil || fields.Branch == "" {
+			continue
+		}
+		s := &StaleMR{
+			Issue:         issue,
+			Fields:        fields,
+			MissingSource: missing(fields.Branch),
+			MissingTarget: missing(fields.Target),
+		}
+		if s.MissingSource || s.MissingTarget {
+			stale = append(stale, s)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Issue.ID < stale[j].Issue.ID
+	})
+	return stale
+}
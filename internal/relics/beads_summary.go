@@ -0,0 +1,90 @@
+// Package relics provides an aggregate summary of a relics database.
+package relics
+
+import "time"
+
+// RelicsSummary is a one-pass aggregate of a relics database, for status
+// lines and dashboards. Work counts exclude agent beads, which are counted
+// separately in LiveAgents.
+type RelicsSummary struct {
+	Open       int       `json:"open"`
+	InProgress int       `json:"in_progress"`
+	Closed     int       `json:"closed"`
+	Blocked    int       `json:"blocked"`     // issues rl blocked reports as blocked by dependencies
+	LiveAgents int       `json:"live_agents"` // open agent beads not stopped, dead, or closed
+	TakenAt    time.Time `json:"taken_at"`
+}
+
+// Summary returns the issue counts and live agent count for b's database.
+// It costs one rl list (paged, see ListAll) and one rl blocked call.
+func (b *Relics) Summary() (RelicsSummary, error) {
+	issues, err := b.ListAll(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return RelicsSummary{}, err
+	}
+	blocked, err := b.Blocked()
+	if err != nil {
+		return RelicsSummary{}, err
+	}
+	return summarize(issues, blocked, time.Now()), nil
+}
+
+// CachedSummary is Summary, reusing the last result for up to maxAge.
+// The cache lives on b, so it only helps long-lived callers (the dashboard,
+// the daemon) that keep one Relics around.
+func (b *Relics) CachedSummary(maxAge time.Duration) (RelicsSummary, error) {
+	b.summaryMu.Lock()
+	defer b.summaryMu.Unlock()
+	if b.summary != nil && time.Since(b.summary.TakenAt) < maxAge {
+		return *b.summary, nil
+	}
+
+	summary, err := b.Summary()
+	if err != nil {
+		return RelicsSummary{}, err
+	}
+	b.summary = &summary
+	return summary, nil
+}
+
+// summarize is the counting core of Summary.
+func summarize(issues, blocked []*Issue, now time.Time) RelicsSummary {
+	summary := RelicsSummary{TakenAt: now}
+	var work []*Issue
+	for _, issue := range issues {
+		if issue.Status == "tombstone" {
+			continue
+		}
+		if issue.HasLabel("gt:agent") || issue.Type == "agent" {
+			if isLiveAgent(issue) {
+				summary.LiveAgents++
+			}
+			continue
+		}
+		work = append(work, issue)
+	}
+
+	counts := countByStatus(work, blocked)
+	summary.Open = counts["open"]
+	summary.InProgress = counts["in_progress"]
+	summary.Closed = counts["closed"]
+	summary.Blocked = counts["blocked"]
+	return summary
+}
+
+// isLiveAgent reports whether an agent bead is open and its agent_state
+// (the column, else the description field) isn't stopped, dead, or closed.
+func isLiveAgent(issue *Issue) bool {
+	if issue.Status == "closed" {
+		return false
+	}
+	state := issue.AgentState
+	if state == "" {
+		state = ParseAgentFields(issue.Description).AgentState
+	}
+	switch state {
+	case AgentStateStopped, AgentStateDead, AgentStateClosed:
+		return false
+	}
+	return true
+}
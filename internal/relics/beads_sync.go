@@ -0,0 +1,88 @@
+// Package relics provides a typed wrapper around rl sync.
+package relics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Common sync errors. Sync returns these (wrapped with rl's output) so
+// callers can decide whether to retry, warn, or give up.
+var (
+	ErrSyncConflict = errors.New("rl sync: JSONL merge conflict")
+	ErrSyncLocked   = errors.New("rl sync: database is locked")
+)
+
+// SyncOptions specifies options for syncing the relics database with JSONL.
+type SyncOptions struct {
+	ImportOnly bool // Only import JSONL into the database; don't export or commit
+	AllowStale bool // Skip the staleness check (useful while a daemon is writing)
+	NoDaemon   bool // Run directly instead of through the rl daemon
+}
+
+// syncArgs builds the rl argument list for a sync with the given options.
+func syncArgs(opts SyncOptions) []string {
+	var args []string
+	if opts.NoDaemon {
+		args = append(args, "--no-daemon")
+	}
+	if opts.AllowStale {
+		args = append(args, "--allow-stale")
+	}
+	args = append(args, "sync")
+	if opts.ImportOnly {
+		args = append(args, "--import-only")
+	}
+	return args
+}
+
+// Sync runs rl sync in the relics workspace.
+// Unlike run, it doesn't force --no-daemon/--allow-stale (callers choose via
+// opts) and doesn't require output on stdout, since sync may print nothing.
+func (b *Relics) Sync(opts SyncOptions) error {
+	args := syncArgs(opts)
+	cmd := exec.Command("rl", args...) //nolint:gosec // G204: rl is a trusted internal tool
+	cmd.Dir = b.workDir
+
+	relicsDir := b.relicsDir
+	if relicsDir == "" {
+		relicsDir = ResolveRelicsDir(b.workDir)
+	}
+	cmd.Env = append(os.Environ(), "RELICS_DIR="+relicsDir)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return classifySyncError(err, output.String(), args)
+	}
+	return nil
+}
+
+// classifySyncError maps well-known rl sync failures onto sentinel errors,
+// keeping rl's output as context. Unrecognized failures are wrapped as-is.
+// Sync doesn't use wrapError: its "not found" mapping is for issue lookups.
+func classifySyncError(err error, output string, args []string) error {
+	if execErr, ok := err.(*exec.Error); ok && errors.Is(execErr.Err, exec.ErrNotFound) {
+		return ErrNotInstalled
+	}
+
+	output = strings.TrimSpace(output)
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "conflict"):
+		return fmt.Errorf("%w: %s", ErrSyncConflict, output)
+	case strings.Contains(lower, "database is locked"):
+		return fmt.Errorf("%w: %s", ErrSyncLocked, output)
+	}
+
+	if output != "" {
+		return fmt.Errorf("bd %s: %s", strings.Join(args, " "), output)
+	}
+	return fmt.Errorf("bd %s: %w", strings.Join(args, " "), err)
+}
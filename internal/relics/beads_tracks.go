@@ -0,0 +1,59 @@
+// Package relics provides batch creation of raid tracks relations.
+package relics
+
+import "fmt"
+
+// AddTracksBatch adds a non-blocking "tracks" relation from raidID to each of
+// issueIDs. All relations are requested in a single rl dep add; only if that
+// call fails are the issues retried one at a time, so a single bad ID costs
+// extra subprocesses without losing the rest of the batch.
+//
+// added lists the issues now tracked, in the order given (duplicates and
+// empty IDs are dropped). failed maps each issue that couldn't be tracked to
+// its error. err is reserved for problems with the request itself, such as a
+// missing raid ID.
+func (b *Relics) AddTracksBatch(raidID string, issueIDs []string) (added []string, failed map[string]error, err error) {
+	if raidID == "" {
+		return nil, nil, fmt.Errorf("%w: raid ID is required to add tracks", ErrInvalidOptions)
+	}
+	added, failed = addTracks(raidID, issueIDs, b.run)
+	return added, failed, nil
+}
+
+// addTracks implements AddTracksBatch on top of run, which executes one rl
+// invocation.
+func addTracks(raidID string, issueIDs []string, run func(args ...string) ([]byte, error)) ([]string, map[string]error) {
+	seen := make(map[string]bool, len(issueIDs))
+	var ids []string
+	for _, id := range issueIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	failed := make(map[string]error)
+	if len(ids) == 0 {
+		return nil, failed
+	}
+
+	args := append(append([]string{"dep", "add", raidID}, ids...), "--type=tracks")
+	_, err := run(args...)
+	if err == nil {
+		return ids, failed
+	}
+	if len(ids) == 1 {
+		failed[ids[0]] = err
+		return nil, failed
+	}
+
+	// The batch was rejected as a whole; find out which issues are at fault.
+	var added []string
+	for _, id := range ids {
+		if _, err := run("dep", "add", raidID, id, "--type=tracks"); err != nil {
+			failed[id] = err
+			continue
+		}
+		added = append(added, id)
+	}
+	return added, failed
+}
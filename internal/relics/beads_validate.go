@@ -0,0 +1,58 @@
+package relics
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidOptions indicates create options that would be rejected by rl,
+// or silently misfiled (e.g., a type that doesn't form a valid label).
+var ErrInvalidOptions = errors.New("invalid issue options")
+
+// Priority bounds for CreateOptions.Priority. Lower numbers are more urgent.
+const (
+	PriorityHighest = 0
+	PriorityLowest  = 4
+	// PriorityNone leaves the issue's priority unset.
+	PriorityNone = -1
+)
+
+// IssueTypes are the values CreateOptions.Type accepts: the rl work types,
+// the molecule step types, and the Horde types stored as gt:<type> labels.
+var IssueTypes = []string{
+	"task", "bug", "feature", "epic", "chore",
+	"wait", "timer",
+	"agent", "role", "warband", "merge-request", "message", "event", "raid", "totem",
+}
+
+// Validate checks the options before they are passed to rl create: the title
+// must be non-blank, Type (if set) one of IssueTypes, and Priority between
+// PriorityHighest and PriorityLowest, or PriorityNone. Errors wrap
+// ErrInvalidOptions.
+func (o CreateOptions) Validate() error {
+	return o.validate(true)
+}
+
+// validate is Validate, but with knownType false any Type that forms a valid
+// gt:<type> label is allowed. That is for types copied from issues rl already
+// holds (e.g., a totem's gate or escalation steps), which needn't be in
+// IssueTypes.
+func (o CreateOptions) validate(knownType bool) error {
+	if strings.TrimSpace(o.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidOptions)
+	}
+	if knownType && o.Type != "" && !slices.Contains(IssueTypes, o.Type) {
+		return fmt.Errorf("%w: unknown type %q (valid types: %s)",
+			ErrInvalidOptions, o.Type, strings.Join(IssueTypes, ", "))
+	}
+	if strings.ContainsAny(o.Type, ", \t\n") {
+		return fmt.Errorf("%w: type %q is not a valid label", ErrInvalidOptions, o.Type)
+	}
+	if o.Priority != PriorityNone && (o.Priority < PriorityHighest || o.Priority > PriorityLowest) {
+		return fmt.Errorf("%w: priority %d out of range (%d-%d, or %d for none)",
+			ErrInvalidOptions, o.Priority, PriorityHighest, PriorityLowest, PriorityNone)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ package relics
 import (
 	"fmt"
 	"strings"
+
+	"github.com/deeklead/horde/internal/config"
 )
 
 // Note: AgentFields, ParseAgentFields, FormatAgentDescription, and CreateAgentBead are in relics.go
@@ -421,6 +423,76 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 	return formatted + "\n\n" + strings.Join(otherLines, "\n")
 }
 
+// MRCloseReasons lists the close_reason values accepted by MRFields.Validate.
+var MRCloseReasons = []string{"merged", "rejected", "conflict", "superseded"}
+
+// Validate checks MR field invariants before writing them to a bead:
+// a branch must be a plausible git branch name and requires a target,
+// source_issue must look like a bead ID, and close_reason must be one of
+// MRCloseReasons. ParseMRFields stays permissive so existing data can
+// always be read.
+func (f *MRFields) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if f.Branch != "" {
+		if !looksLikeBranch(f.Branch) {
+			return fmt.Errorf("invalid MR branch %q", f.Branch)
+		}
+		if f.Target == "" {
+			return fmt.Errorf("MR branch %q has no target", f.Branch)
+		}
+	}
+	if f.Target != "" && !looksLikeBranch(f.Target) {
+		return fmt.Errorf("invalid MR target %q", f.Target)
+	}
+	if f.SourceIssue != "" && !looksLikeBeadID(f.SourceIssue) {
+		return fmt.Errorf("invalid MR source_issue %q: expected a bead ID like hd-abc", f.SourceIssue)
+	}
+	if f.CloseReason != "" {
+		valid := false
+		for _, r := range MRCloseReasons {
+			if f.CloseReason == r {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid MR close_reason %q (valid: %s)", f.CloseReason, strings.Join(MRCloseReasons, ", "))
+		}
+	}
+	return nil
+}
+
+// SetMRFieldsValidated is SetMRFields that first runs fields.Validate.
+// Use it when writing MR fields; malformed fields are rejected instead of
+// silently stranding the bead in the merge queue.
+func SetMRFieldsValidated(issue *Issue, fields *MRFields) (string, error) {
+	if err := fields.Validate(); err != nil {
+		return "", err
+	}
+	return SetMRFields(issue, fields), nil
+}
+
+// looksLikeBranch reports whether s is plausible as a git branch name.
+// It rejects the common mistakes (whitespace, leading dash, "..", and the
+// characters git forbids in refs) rather than reimplementing git check-ref-format.
+func looksLikeBranch(s string) bool {
+	if s == "" || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "/") ||
+		strings.HasSuffix(s, "/") || strings.HasSuffix(s, ".lock") ||
+		strings.Contains(s, "..") || strings.Contains(s, "//") || strings.Contains(s, "@{") {
+		return false
+	}
+	return !strings.ContainsAny(s, " \t\n~^:?*[\\")
+}
+
+// looksLikeBeadID reports whether s has the prefix-id shape of a bead ID
+// (e.g., "hd-abc", "hq-cv-xyz").
+func looksLikeBeadID(s string) bool {
+	prefix := ExtractPrefix(s)
+	return prefix != "" && len(s) > len(prefix) && !strings.ContainsAny(s, " \t\n")
+}
+
 // SynthesisFields holds structured fields for synthesis relics.
 // These fields track the synthesis step in a raid workflow.
 type SynthesisFields struct {
@@ -656,11 +728,7 @@ func FormatRoleConfig(config *RoleConfig) string {
 
 // ExpandRolePattern expands placeholders in a pattern string.
 // Supported placeholders: {encampment}, {warband}, {name}, {role}
+// See config.ExpandRolePattern, which role prompts share.
 func ExpandRolePattern(pattern, townRoot, warband, name, role string) string {
-	result := pattern
-	result = strings.ReplaceAll(result, "{encampment}", townRoot)
-	result = strings.ReplaceAll(result, "{warband}", warband)
-	result = strings.ReplaceAll(result, "{name}", name)
-	result = strings.ReplaceAll(result, "{role}", role)
-	return result
+	return config.ExpandRolePattern(pattern, townRoot, warband, name, role)
 }
@@ -323,7 +323,8 @@ func (b *Relics) instantiateFromChildren(mol *Issue, parent *Issue, templates []
 			childOpts.Type = "task"
 		}
 
-		child, err := b.Create(childOpts)
+		// Template types (gate, escalation, ...) come from rl, not IssueTypes
+		child, err := b.createCopy(childOpts)
 		if err != nil {
 			// Attempt to clean up created issues on failure (best-effort cleanup)
 			for _, created := range createdIssues {
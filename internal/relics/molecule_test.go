@@ -1,6 +1,7 @@
 package relics
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -787,3 +788,27 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+// TestInstantiateFromChildren_TemplateTypes verifies totem template steps
+// keep types rl accepted but IssueTypes doesn't list: validation lets them
+// through, so instantiation only fails once rl itself runs.
+func TestInstantiateFromChildren_TemplateTypes(t *testing.T) {
+	mol := &Issue{ID: "hd-mol"}
+	parent := &Issue{ID: "hd-epic", Priority: 2}
+	for _, typ := range []string{"gate", "molecule", "escalation"} {
+		templates := []*Issue{{ID: "hd-mol.1", Title: "Step", Type: typ}}
+		_, err := New(t.TempDir()).instantiateFromChildren(mol, parent, templates, InstantiateOptions{})
+		if errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%s step: %v, want it to pass validation", typ, err)
+		}
+	}
+
+	// A copied type must still form a valid label
+	opts := CreateOptions{Title: "Step", Type: "gate,urgent"}
+	if err := opts.validate(false); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("validate(%q) = %v, want ErrInvalidOptions", opts.Type, err)
+	}
+	if err := (CreateOptions{Title: "Step", Type: "gate"}).Validate(); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("Validate(gate) = %v, want ErrInvalidOptions for callers' own types", err)
+	}
+}
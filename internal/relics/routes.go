@@ -53,6 +53,21 @@ func LoadRoutes(relicsDir string) ([]Route, error) {
 	return routes, scanner.Err()
 }
 
+// RouteExists reports whether the encampment's routes.jsonl already maps
+// route.Prefix to route.Path.
+func RouteExists(townRoot string, route Route) bool {
+	routes, err := LoadRoutes(filepath.Join(townRoot, ".relics"))
+	if err != nil {
+		return false
+	}
+	for _, r := range routes {
+		if r.Prefix == route.Prefix && r.Path == route.Path {
+			return true
+		}
+	}
+	return false
+}
+
 // AppendRoute appends a route to routes.jsonl in the encampment's relics directory.
 // If the prefix already exists, it updates the path.
 func AppendRoute(townRoot string, route Route) error {
@@ -231,6 +246,38 @@ func GetRigPathForPrefix(townRoot, prefix string) string {
 	return ""
 }
 
+// ResolveWarbandForID resolves which warband owns a bead ID by matching its
+// prefix against routes.jsonl in the encampment's relics directory.
+// Returns the warband name (first path component of the route) and the
+// resolved relics directory for that route, following any redirect.
+// For encampment-level routes (path="."), rigName is empty and relicsDir is
+// the encampment relics directory.
+// The townRoot should be the Horde root directory (e.g., ~/horde).
+func ResolveWarbandForID(townRoot, id string) (rigName, relicsDir string, err error) {
+	prefix := ExtractPrefix(id)
+	if prefix == "" {
+		return "", "", fmt.Errorf("bead ID %q has no prefix", id)
+	}
+
+	routes, err := LoadRoutes(filepath.Join(townRoot, ".relics"))
+	if err != nil {
+		return "", "", fmt.Errorf("loading routes: %w", err)
+	}
+
+	for _, r := range routes {
+		if r.Prefix != prefix {
+			continue
+		}
+		if r.Path == "." {
+			return "", ResolveRelicsDir(townRoot), nil // Encampment-level relics
+		}
+		parts := strings.SplitN(r.Path, "/", 2)
+		return parts[0], ResolveRelicsDir(filepath.Join(townRoot, r.Path)), nil
+	}
+
+	return "", "", fmt.Errorf("no route for prefix %q (bead %s)", prefix, id)
+}
+
 // ResolveHookDir determines the directory for running rl update on a bead.
 // Since rl update doesn't support routing or redirects, we must resolve the
 // actual warband directory from the bead's prefix. hookWorkDir is only used as
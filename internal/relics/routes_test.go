@@ -189,6 +189,49 @@ func TestResolveHookDir(t *testing.T) {
 	}
 }
 
+func TestResolveWarbandForID(t *testing.T) {
+	tmpDir := t.TempDir()
+	relicsDir := filepath.Join(tmpDir, ".relics")
+	if err := os.MkdirAll(relicsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	routesContent := `{"prefix": "ap-", "path": "ai_platform/warchief/warband"}
+{"prefix": "hq-", "path": "."}
+`
+	if err := os.WriteFile(filepath.Join(relicsDir, "routes.jsonl"), []byte(routesContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id            string
+		wantRig       string
+		wantRelicsDir string
+		wantErr       bool
+	}{
+		{"ap-qtsup.16", "ai_platform", filepath.Join(tmpDir, "ai_platform/warchief/warband/.relics"), false},
+		{"hq-cv-abc", "", relicsDir, false},
+		{"xx-unknown", "", "", true},
+		{"nohyphen", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.id, func(t *testing.T) {
+			rigName, gotDir, err := ResolveWarbandForID(tmpDir, tc.id)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ResolveWarbandForID(%q) error = %v, wantErr %v", tc.id, err, tc.wantErr)
+			}
+			if rigName != tc.wantRig {
+				t.Errorf("rigName = %q, want %q", rigName, tc.wantRig)
+			}
+			if gotDir != tc.wantRelicsDir {
+				t.Errorf("relicsDir = %q, want %q", gotDir, tc.wantRelicsDir)
+			}
+		})
+	}
+}
+
 func TestAgentBeadIDsWithPrefix(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,43 @@
+package ritual
+
+// resolveAliases folds dependency aliases into each item's canonical field.
+// Workflow steps and expansion templates use needs, and synthesis uses
+// depends_on; authors often mix them up, so the other spelling is accepted
+// and merged. Alias fields are cleared afterwards, so validation, planning,
+// and WriteTOML only ever see the canonical field. Soft needs are also added
+// to needs, which always lists every dependency.
+func (f *Ritual) resolveAliases() {
+	for i := range f.Steps {
+		s := &f.Steps[i]
+		s.Needs = mergeRefs(s.Needs, s.DependsOn)
+		s.Needs = mergeRefs(s.Needs, s.SoftNeeds)
+		s.DependsOn = nil
+	}
+	for i := range f.Template {
+		t := &f.Template[i]
+		t.Needs = mergeRefs(t.Needs, t.DependsOn)
+		t.DependsOn = nil
+	}
+	if f.Synthesis != nil {
+		f.Synthesis.DependsOn = mergeRefs(f.Synthesis.DependsOn, f.Synthesis.Needs)
+		f.Synthesis.Needs = nil
+	}
+}
+
+// mergeRefs appends the references in alias that are not already in refs.
+func mergeRefs(refs, alias []string) []string {
+	if len(alias) == 0 {
+		return refs
+	}
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		seen[ref] = true
+	}
+	for _, ref := range alias {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,35 @@
+package ritual
+
+import "fmt"
+
+// ResolveArtifacts adds the needs edges implied by artifact declarations:
+// a workflow step that consumes an artifact needs every other step that
+// produces it. Parse calls this automatically, so TopologicalSort, ReadySteps,
+// and Waves respect data dependencies without explicit needs. It is safe to
+// call more than once.
+//
+// Returns an error if a step consumes an artifact that no step produces.
+func (f *Ritual) ResolveArtifacts() error {
+	producers := make(map[string][]string)
+	for _, step := range f.Steps {
+		for _, artifact := range step.Produces {
+			producers[artifact] = append(producers[artifact], step.ID)
+		}
+	}
+
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		for _, artifact := range step.Consumes {
+			ids, ok := producers[artifact]
+			if !ok {
+				return fmt.Errorf("step %q: no producer for artifact %s", step.ID, artifact)
+			}
+			for _, id := range ids {
+				if id != step.ID {
+					step.Needs = mergeRefs(step.Needs, []string{id})
+				}
+			}
+		}
+	}
+	return nil
+}
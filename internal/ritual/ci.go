@@ -0,0 +1,120 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CIJob is one ritual item projected onto a CI job graph, such as GitHub
+// Actions jobs or GitLab CI stages. It is a neutral form: a formatter maps it
+// to a concrete CI syntax and decides what command each job runs.
+type CIJob struct {
+	ID        string   // job ID derived from Step, safe as a CI job key
+	Step      string   // ritual item ID
+	Name      string   // display name: the item's title, or its ID
+	Needs     []string // job IDs that must finish first
+	SoftNeeds []string // subset of Needs that only order the job; run it even if they fail
+	Wave      int      // dependency depth, usable as a stage index
+	Optional  bool     // the job's failure shouldn't fail the pipeline
+	Group     string   // concurrency group; jobs sharing one must not overlap
+}
+
+// ToCIJobs projects the ritual's dependency graph into CI jobs, one per item
+// plus one for a raid or aspect synthesis, in TopologicalSort order. Needs
+// mirror GetDependencies, so GitHub Actions' needs graph (or GitLab's)
+// reproduces the ritual's ordering; soft needs map naturally onto
+// "if: always()" and Optional onto "continue-on-error".
+//
+// Job IDs keep letters, digits, '-' and '_' from the item ID, replace
+// anything else with '_', and are made unique.
+func (f *Ritual) ToCIJobs() ([]CIJob, error) {
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	waves, err := f.WaveIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	jobIDs := make(map[string]string, len(order)+1)
+	used := make(map[string]bool, len(order)+1)
+	jobID := func(id string) string {
+		if j, ok := jobIDs[id]; ok {
+			return j
+		}
+		j := ciJobID(id)
+		for base, i := j, 2; used[j]; i++ {
+			j = fmt.Sprintf("%s_%d", base, i)
+		}
+		jobIDs[id] = j
+		used[j] = true
+		return j
+	}
+	for _, id := range order {
+		jobID(id)
+	}
+
+	jobs := make([]CIJob, 0, len(order)+1)
+	for _, id := range order {
+		job := CIJob{
+			ID:   jobID(id),
+			Step: id,
+			Name: f.itemTitle(id),
+			Wave: waves[id],
+		}
+		if job.Name == "" {
+			job.Name = id
+		}
+		step := f.GetStep(id)
+		for _, dep := range f.GetDependencies(id) {
+			job.Needs = append(job.Needs, jobID(dep))
+			if step != nil && step.NeedType(dep) == NeedSoft {
+				job.SoftNeeds = append(job.SoftNeeds, jobID(dep))
+			}
+		}
+		if step != nil && f.Type == TypeWorkflow {
+			job.Optional = step.Optional
+			job.Group = step.ConcurrencyGroup
+		}
+		jobs = append(jobs, job)
+	}
+
+	if f.hasSynthesisNode() {
+		job := CIJob{
+			ID:   jobID(synthesisNodeID),
+			Step: synthesisNodeID,
+			Name: f.Synthesis.Title,
+		}
+		if job.Name == "" {
+			job.Name = synthesisNodeID
+		}
+		for _, dep := range f.synthesisDeps() {
+			job.Needs = append(job.Needs, jobID(dep))
+			if waves[dep]+1 > job.Wave {
+				job.Wave = waves[dep] + 1
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// ciJobID reduces id to characters CI systems accept in a job key: a letter
+// or '_' followed by letters, digits, '-' and '_'.
+func ciJobID(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	j := b.String()
+	if j == "" || !(j[0] == '_' || (j[0] >= 'a' && j[0] <= 'z') || (j[0] >= 'A' && j[0] <= 'Z')) {
+		j = "_" + j
+	}
+	return j
+}
@@ -0,0 +1,50 @@
+package ritual
+
+// ConnectedComponents splits the ritual's items into groups that share no
+// dependencies, directly or through other items. Each group can be
+// scheduled independently; more than one group in a workflow often means
+// unrelated pipelines were merged into one file.
+//
+// Components are ordered by their first item, and items within a component
+// keep declaration order. Raid legs and aspects have no dependencies on each
+// other (synthesis is not an item), so each forms its own component.
+func (f *Ritual) ConnectedComponents() [][]string {
+	ids := f.GetAllIDs()
+	parent := make(map[string]string, len(ids))
+	for _, id := range ids {
+		parent[id] = id
+	}
+
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	for _, id := range ids {
+		for _, dep := range f.GetDependencies(id) {
+			if _, ok := parent[dep]; !ok {
+				continue // unknown reference; Validate reports it
+			}
+			if a, b := find(id), find(dep); a != b {
+				parent[a] = b
+			}
+		}
+	}
+
+	var components [][]string
+	index := make(map[string]int, len(ids))
+	for _, id := range ids {
+		root := find(id)
+		i, ok := index[root]
+		if !ok {
+			i = len(components)
+			index[root] = i
+			components = append(components, nil)
+		}
+		components[i] = append(components[i], id)
+	}
+	return components
+}
@@ -0,0 +1,65 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchedulableSteps is ReadySteps for a scheduler that dispatches steps while
+// others are still running. It returns the ready steps that aren't in
+// running and can start now without breaking mutual exclusion: at most one
+// step per concurrency_group, and none from a group that a running step
+// already holds. Within a group, the earliest step in ritual order wins.
+//
+// Steps without a concurrency_group are limited only by their needs. For
+// non-workflow rituals it is ReadySteps minus running.
+func (f *Ritual) SchedulableSteps(completed, running map[string]bool) []string {
+	var ready []string
+	for _, id := range f.ReadySteps(completed) {
+		if !running[id] {
+			ready = append(ready, id)
+		}
+	}
+	return f.exclusive(ready, running)
+}
+
+// exclusive filters candidates down to the steps that can run alongside
+// each other and the steps in running: the first candidate of each
+// concurrency group, unless a running step already holds that group.
+func (f *Ritual) exclusive(candidates []string, running map[string]bool) []string {
+	if f.Type != TypeWorkflow {
+		return candidates
+	}
+
+	held := make(map[string]bool)
+	for id := range running {
+		if step := f.GetStep(id); step != nil && step.ConcurrencyGroup != "" {
+			held[step.ConcurrencyGroup] = true
+		}
+	}
+
+	var out []string
+	for _, id := range candidates {
+		if step := f.GetStep(id); step != nil && step.ConcurrencyGroup != "" {
+			if held[step.ConcurrencyGroup] {
+				continue
+			}
+			held[step.ConcurrencyGroup] = true
+		}
+		out = append(out, id)
+	}
+	return out
+}
+
+// validateConcurrencyGroup checks a step's concurrency_group: if set, it
+// must be a single non-blank word.
+func validateConcurrencyGroup(step Step) error {
+	group := step.ConcurrencyGroup
+	if group == "" {
+		return nil
+	}
+	if strings.TrimSpace(group) == "" || strings.ContainsAny(group, " \t\n") {
+		return fmt.Errorf("step %q: invalid concurrency_group %q", step.ID, group)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package ritual
+
+import "slices"
+
+// CommonAncestors returns the items that both a and b transitively depend
+// on, in declaration order: the points a diamond-shaped workflow branches
+// from before a and b rejoin. It returns nil if either item doesn't exist
+// or they share no ancestors. Neither a nor b is its own ancestor, so if b
+// depends on a, a is not reported.
+func (f *Ritual) CommonAncestors(a, b string) []string {
+	ancestorsA, ancestorsB := f.ancestors(a), f.ancestors(b)
+	if ancestorsA == nil || ancestorsB == nil {
+		return nil
+	}
+
+	var common []string
+	for _, id := range f.GetAllIDs() {
+		if ancestorsA[id] && ancestorsB[id] {
+			common = append(common, id)
+		}
+	}
+	return common
+}
+
+// DiamondPoints returns the join items, those that directly depend on more
+// than one item, in declaration order. They are where parallel branches
+// reconverge, and so the natural places for synthesis or merge steps; pass
+// two of a join's needs to CommonAncestors to find where they split.
+// Raid legs and aspects have no dependencies on each other (synthesis is
+// not an item), so those rituals have none.
+func (f *Ritual) DiamondPoints() []string {
+	var joins []string
+	for _, id := range f.GetAllIDs() {
+		if len(dedupe(f.GetDependencies(id))) > 1 {
+			joins = append(joins, id)
+		}
+	}
+	return joins
+}
+
+// ancestors returns the set of items id transitively depends on, or nil if
+// id isn't an item.
+func (f *Ritual) ancestors(id string) map[string]bool {
+	if !slices.Contains(f.GetAllIDs(), id) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range f.GetDependencies(current) {
+			if !seen[dep] {
+				seen[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return seen
+}
@@ -102,6 +102,9 @@
 //	order, err := f.TopologicalSort()
 //	// Returns: ["test", "build", "publish"]
 //
+// When several steps are ready at the same time, the one declared first in
+// the ritual comes first, so the order is the same on every run.
+//
 // For raid and aspect rituals (which are parallel), TopologicalSort
 // returns all items in their original order.
 //
@@ -114,11 +117,23 @@
 //	ready := f.ReadySteps(completed)
 //	// Returns: ["build"] (test is done, build can run)
 //
+// InitialReady is shorthand for the first iteration, with nothing completed.
+//
+// # Execution
+//
+// Executor drives that loop for callers. It dispatches each wave of ready
+// steps concurrently (up to ParallelLimit), waits for the wave, and applies
+// the OnFailure policy. The runner callback does the actual work:
+//
+//	results, err := ritual.NewExecutor(f).Execute(ctx, runStep)
+//	// results["publish"].Status is completed, failed, or skipped
+//
 // # Embedded Rituals
 //
 // The package includes embedded ritual files that can be provisioned
 // to a relics workspace. Use ProvisionFormulas for initial setup and
 // UpdateFormulas for safe updates that preserve user modifications.
+// PlanProvision previews what either would do without writing anything.
 //
 // # Thread Safety
 //
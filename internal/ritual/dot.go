@@ -0,0 +1,79 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderDOT renders the ritual's dependency graph in Graphviz DOT, for
+// piping into dot(1). It draws the same graph as RenderMermaid: nodes are
+// labeled with item titles (falling back to IDs), edges point from a
+// dependency to its dependent, synthesis is a double box, aspects are
+// shaded, and optional steps and soft needs are dashed.
+func (f *Ritual) RenderDOT() string {
+	var b strings.Builder
+	name := f.Name
+	if name == "" {
+		name = "ritual"
+	}
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(name))
+	b.WriteString("    rankdir=TB;\n")
+	b.WriteString("    node [shape=box];\n")
+
+	ids := f.GetAllIDs()
+	for _, id := range ids {
+		attrs := []string{"label=" + dotQuote(dotLabel(f.itemTitle(id), id))}
+		if f.Type == TypeAspect {
+			attrs = append(attrs, `style=filled`, `fillcolor="#e8f0fe"`)
+		}
+		if step := f.GetStep(id); step != nil && f.Type == TypeWorkflow && step.Optional {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "    %s%s;\n", dotQuote(id), dotAttrs(attrs))
+	}
+
+	if f.hasSynthesisNode() {
+		fmt.Fprintf(&b, "    %s [label=%s, peripheries=2, style=filled, fillcolor=\"#fff4d6\"];\n",
+			dotQuote(synthesisNodeID), dotQuote(dotLabel(f.Synthesis.Title, synthesisNodeID)))
+	}
+
+	for _, id := range ids {
+		step := f.GetStep(id)
+		for _, dep := range f.GetDependencies(id) {
+			var attrs []string
+			if step != nil && step.NeedType(dep) == NeedSoft {
+				attrs = append(attrs, "style=dashed")
+			}
+			fmt.Fprintf(&b, "    %s -> %s%s;\n", dotQuote(dep), dotQuote(id), dotAttrs(attrs))
+		}
+	}
+	for _, dep := range f.synthesisDeps() {
+		fmt.Fprintf(&b, "    %s -> %s;\n", dotQuote(dep), dotQuote(synthesisNodeID))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a quoted DOT ID.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// dotLabel returns title, or id if title is empty, on a single line.
+func dotLabel(title, id string) string {
+	if title == "" {
+		title = id
+	}
+	return strings.ReplaceAll(title, "\n", " ")
+}
+
+// dotAttrs formats a node or edge attribute list, or returns "" if there
+// are no attributes.
+func dotAttrs(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Generate rituals directory from canonical source at .relics/rituals/
@@ -19,7 +20,8 @@ var formulasFS embed.FS
 // InstalledRecord tracks which rituals were installed and their checksums.
 // Stored in .relics/rituals/.installed.json
 type InstalledRecord struct {
-	Rituals map[string]string `json:"rituals"` // filename -> sha256 at install time
+	Rituals map[string]string `json:"rituals"`           // filename -> sha256 at install time
+	Content map[string]string `json:"content,omitempty"` // filename -> ContentHash at install time
 }
 
 // FormulaStatus represents the status of a single ritual during health check.
@@ -75,7 +77,7 @@ func loadInstalledRecord(formulasDir string) (*InstalledRecord, error) {
 	path := filepath.Join(formulasDir, ".installed.json")
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
-		return &InstalledRecord{Rituals: make(map[string]string)}, nil
+		return &InstalledRecord{Rituals: make(map[string]string), Content: make(map[string]string)}, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("reading installed record: %w", err)
@@ -87,6 +89,9 @@ func loadInstalledRecord(formulasDir string) (*InstalledRecord, error) {
 	if r.Rituals == nil {
 		r.Rituals = make(map[string]string)
 	}
+	if r.Content == nil {
+		r.Content = make(map[string]string)
+	}
 	return &r, nil
 }
 
@@ -100,13 +105,45 @@ func saveInstalledRecord(formulasDir string, record *InstalledRecord) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// computeFileHash computes SHA256 hash of a file.
-func computeFileHash(path string) (string, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", err
+// classifyFormula compares the ritual filename in formulasDir against its
+// embedded version (whose hash is embeddedHash) and the installed record,
+// returning a FormulaStatus status and the hash of the file on disk. For
+// status "error", err says why the file couldn't be read.
+//
+// A file that differs from what was installed only in formatting, as judged
+// by ContentHash, is treated as unmodified. Records written before content
+// hashes were tracked fall back to a byte comparison.
+func classifyFormula(formulasDir, filename, embeddedHash string, installed *InstalledRecord) (status, currentHash string, err error) {
+	installedHash, wasInstalled := installed.Rituals[filename]
+	current, err := os.ReadFile(filepath.Join(formulasDir, filename))
+	switch {
+	case os.IsNotExist(err) && wasInstalled:
+		// We installed it before, user deleted it
+		return "missing", "", nil
+	case os.IsNotExist(err):
+		return "new", "", nil
+	case err != nil:
+		return "error", "", err
+	}
+
+	currentHash = computeHash(current)
+	if currentHash == embeddedHash {
+		return "ok", currentHash, nil
 	}
-	return computeHash(data), nil
+	currentContent := ContentHash(current)
+	if embedded, err := formulasFS.ReadFile("rituals/" + filename); err == nil && currentContent == ContentHash(embedded) {
+		// Only reformatted relative to the embedded version
+		return "ok", currentHash, nil
+	}
+	if wasInstalled && (currentHash == installedHash || currentContent == installed.Content[filename]) {
+		// User hasn't modified, safe to update
+		return "outdated", currentHash, nil
+	}
+	if wasInstalled {
+		return "modified", currentHash, nil
+	}
+	// Not tracked (e.g., from an older hd version), so safe to update
+	return "untracked", currentHash, nil
 }
 
 // ProvisionFormulas creates the .relics/rituals/ directory with embedded rituals.
@@ -114,66 +151,14 @@ func computeFileHash(path string) (string, error) {
 // If a ritual already exists, it is skipped (no overwrite).
 // Returns the number of rituals provisioned.
 func ProvisionFormulas(relicsPath string) (int, error) {
-	embedded, err := getEmbeddedFormulas()
-	if err != nil {
-		return 0, err
-	}
-
-	entries, err := formulasFS.ReadDir("rituals")
-	if err != nil {
-		return 0, fmt.Errorf("reading rituals directory: %w", err)
-	}
-
-	// Create .relics/rituals/ directory
-	formulasDir := filepath.Join(relicsPath, ".relics", "rituals")
-	if err := os.MkdirAll(formulasDir, 0755); err != nil {
-		return 0, fmt.Errorf("creating rituals directory: %w", err)
-	}
-
-	// Load existing installed record (or create new)
-	installed, err := loadInstalledRecord(formulasDir)
+	plan, err := PlanProvision(relicsPath)
 	if err != nil {
 		return 0, err
 	}
 
 	count := 0
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		destPath := filepath.Join(formulasDir, entry.Name())
-
-		// Skip if ritual already exists (don't overwrite user customizations)
-		if _, err := os.Stat(destPath); err == nil {
-			continue
-		} else if !os.IsNotExist(err) {
-			// Log unexpected errors but continue
-			continue
-		}
-
-		content, err := formulasFS.ReadFile("rituals/" + entry.Name())
-		if err != nil {
-			return count, fmt.Errorf("reading %s: %w", entry.Name(), err)
-		}
-
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
-			return count, fmt.Errorf("writing %s: %w", entry.Name(), err)
-		}
-
-		// Record the hash we installed
-		if hash, ok := embedded[entry.Name()]; ok {
-			installed.Rituals[entry.Name()] = hash
-		}
-		count++
-	}
-
-	// Save updated installed record
-	if err := saveInstalledRecord(formulasDir, installed); err != nil {
-		return count, fmt.Errorf("saving installed record: %w", err)
-	}
-
-	return count, nil
+	err = plan.apply([]ProvisionAction{ActionCreate}, func(PlannedFormula) { count++ })
+	return count, err
 }
 
 // CheckFormulaHealth checks the status of all rituals.
@@ -198,48 +183,22 @@ func CheckFormulaHealth(relicsPath string) (*HealthReport, error) {
 			EmbeddedHash: embeddedHash,
 		}
 
-		installedHash, wasInstalled := installed.Rituals[filename]
-		status.InstalledHash = installedHash
-
-		destPath := filepath.Join(formulasDir, filename)
-		currentHash, err := computeFileHash(destPath)
-
-		if os.IsNotExist(err) {
-			// File doesn't exist
-			if wasInstalled {
-				// We installed it before, user deleted it
-				status.Status = "missing"
-				report.Missing++
-			} else {
-				// New ritual, never installed
-				status.Status = "new"
-				report.New++
-			}
-		} else if err != nil {
-			// Some other error reading file
-			status.Status = "error"
-		} else {
-			status.CurrentHash = currentHash
-
-			if currentHash == embeddedHash {
-				// File matches embedded - all good
-				status.Status = "ok"
-				report.OK++
-			} else if wasInstalled && currentHash == installedHash {
-				// File matches what we installed, but embedded has changed
-				// User hasn't modified, safe to update
-				status.Status = "outdated"
-				report.Outdated++
-			} else if wasInstalled {
-				// File was tracked and user modified it - don't overwrite
-				status.Status = "modified"
-				report.Modified++
-			} else {
-				// File exists but not tracked (e.g., from older hd version)
-				// Safe to update since we have no record of user modification
-				status.Status = "untracked"
-				report.Untracked++
-			}
+		status.InstalledHash = installed.Rituals[filename]
+		status.Status, status.CurrentHash, _ = classifyFormula(formulasDir, filename, embeddedHash, installed)
+
+		switch status.Status {
+		case "ok":
+			report.OK++
+		case "outdated":
+			report.Outdated++
+		case "modified":
+			report.Modified++
+		case "missing":
+			report.Missing++
+		case "new":
+			report.New++
+		case "untracked":
+			report.Untracked++
 		}
 
 		report.Rituals = append(report.Rituals, status)
@@ -248,87 +207,81 @@ func CheckFormulaHealth(relicsPath string) (*HealthReport, error) {
 	return report, nil
 }
 
-// UpdateFormulas updates rituals that are safe to update (outdated, missing, or untracked).
-// Skips user-modified rituals (tracked files that user changed).
-// Returns counts of updated, skipped (modified), and reinstalled (missing).
-func UpdateFormulas(relicsPath string) (updated, skipped, reinstalled int, err error) {
-	embedded, err := getEmbeddedFormulas()
-	if err != nil {
-		return 0, 0, 0, err
+// UpdateResult describes what UpdateFormulas did to each embedded ritual.
+// Each slice holds ritual filenames, sorted for stable output.
+type UpdateResult struct {
+	Added       []string // new rituals that were never installed before
+	Updated     []string // outdated or untracked rituals overwritten with the embedded version
+	Reinstalled []string // previously installed rituals the user had deleted
+	Skipped     []string // rituals left alone because the user modified them locally
+	Unreadable  []string // rituals left alone because the file on disk couldn't be read
+}
+
+// Changed returns true if any ritual file was written.
+func (r *UpdateResult) Changed() bool {
+	return len(r.Added)+len(r.Updated)+len(r.Reinstalled) > 0
+}
+
+// Summary returns a one-line human-readable description of the update,
+// e.g. "3 rituals updated, 1 skipped (locally modified)".
+func (r *UpdateResult) Summary() string {
+	var parts []string
+	if n := len(r.Added); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s added", n, pluralRituals(n)))
+	}
+	if n := len(r.Updated); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s updated", n, pluralRituals(n)))
+	}
+	if n := len(r.Reinstalled); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s reinstalled", n, pluralRituals(n)))
+	}
+	if n := len(r.Skipped); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped (locally modified)", n))
 	}
+	if n := len(r.Unreadable); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped (unreadable)", n))
+	}
+	if len(parts) == 0 {
+		return "all rituals up to date"
+	}
+	return strings.Join(parts, ", ")
+}
 
-	formulasDir := filepath.Join(relicsPath, ".relics", "rituals")
-	if err := os.MkdirAll(formulasDir, 0755); err != nil {
-		return 0, 0, 0, fmt.Errorf("creating rituals directory: %w", err)
+func pluralRituals(n int) string {
+	if n == 1 {
+		return "ritual"
 	}
+	return "rituals"
+}
 
-	installed, err := loadInstalledRecord(formulasDir)
+// UpdateFormulas updates rituals that are safe to update (outdated, missing, untracked, or new).
+// Skips user-modified rituals, detected by comparing the file's current hash against
+// the hash recorded in .installed.json when it was provisioned.
+// Returns which rituals were added, updated, reinstalled, and skipped.
+func UpdateFormulas(relicsPath string) (*UpdateResult, error) {
+	plan, err := PlanProvision(relicsPath)
 	if err != nil {
-		return 0, 0, 0, err
+		return nil, err
 	}
 
-	for filename, embeddedHash := range embedded {
-		installedHash, wasInstalled := installed.Rituals[filename]
-		destPath := filepath.Join(formulasDir, filename)
-		currentHash, fileErr := computeFileHash(destPath)
-
-		shouldInstall := false
-		isMissing := false
-		isModified := false
-
-		if os.IsNotExist(fileErr) {
-			// File doesn't exist - install it
-			shouldInstall = true
-			if wasInstalled {
-				isMissing = true
-			}
-		} else if fileErr != nil {
-			// Error reading file, skip
-			continue
-		} else if currentHash == embeddedHash {
-			// Already up to date
-			continue
-		} else if wasInstalled && currentHash == installedHash {
-			// User hasn't modified, safe to update
-			shouldInstall = true
-		} else if wasInstalled {
-			// Tracked file was modified by user - skip
-			isModified = true
-		} else {
-			// Untracked file (e.g., from older hd version) - safe to update
-			shouldInstall = true
-		}
-
-		if isModified {
-			skipped++
-			continue
-		}
-
-		if shouldInstall {
-			content, err := formulasFS.ReadFile("rituals/" + filename)
-			if err != nil {
-				return updated, skipped, reinstalled, fmt.Errorf("reading %s: %w", filename, err)
-			}
-
-			if err := os.WriteFile(destPath, content, 0644); err != nil {
-				return updated, skipped, reinstalled, fmt.Errorf("writing %s: %w", filename, err)
-			}
-
-			// Update installed record
-			installed.Rituals[filename] = embeddedHash
-
-			if isMissing {
-				reinstalled++
-			} else {
-				updated++
-			}
+	result := &UpdateResult{}
+	for _, f := range plan.Rituals {
+		switch f.Action {
+		case ActionSkip:
+			result.Skipped = append(result.Skipped, f.Name)
+		case ActionError:
+			result.Unreadable = append(result.Unreadable, f.Name)
 		}
 	}
-
-	// Save updated installed record
-	if err := saveInstalledRecord(formulasDir, installed); err != nil {
-		return updated, skipped, reinstalled, fmt.Errorf("saving installed record: %w", err)
-	}
-
-	return updated, skipped, reinstalled, nil
+	err = plan.apply([]ProvisionAction{ActionCreate, ActionUpdate}, func(f PlannedFormula) {
+		switch f.Status {
+		case "new":
+			result.Added = append(result.Added, f.Name)
+		case "missing":
+			result.Reinstalled = append(result.Reinstalled, f.Name)
+		default:
+			result.Updated = append(result.Updated, f.Name)
+		}
+	})
+	return result, err
 }
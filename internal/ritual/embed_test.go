@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -32,6 +33,31 @@ func TestGetEmbeddedFormulas(t *testing.T) {
 	}
 }
 
+// TestEmbeddedFormulasWithinParseLimits verifies that no embedded ritual
+// trips the default parse limits. Rituals that only parse once rl resolves
+// extends/compose fail validation either way and are not counted against
+// the limits.
+func TestEmbeddedFormulasWithinParseLimits(t *testing.T) {
+	entries, err := formulasFS.ReadDir("rituals")
+	if err != nil {
+		t.Fatalf("reading embedded rituals: %v", err)
+	}
+	for _, entry := range entries {
+		data, err := formulasFS.ReadFile("rituals/" + entry.Name())
+		if err != nil {
+			t.Fatalf("reading %s: %v", entry.Name(), err)
+		}
+		_, err = Parse(data)
+		if errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: %v", entry.Name(), err)
+		} else if err != nil {
+			if _, unlimited := ParseWithOptions(data, ParseOptions{}); unlimited == nil {
+				t.Errorf("%s: fails with default limits but not without: %v", entry.Name(), err)
+			}
+		}
+	}
+}
+
 // TestProvisionFormulas_FreshInstall tests provisioning to an empty directory.
 func TestProvisionFormulas_FreshInstall(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -306,19 +332,25 @@ func TestUpdateFormulas_UpdatesOutdated(t *testing.T) {
 	}
 
 	// Run update
-	updated, skipped, reinstalled, err := UpdateFormulas(tmpDir)
+	result, err := UpdateFormulas(tmpDir)
 	if err != nil {
 		t.Fatalf("UpdateFormulas() error: %v", err)
 	}
 
-	if updated != 1 {
-		t.Errorf("updated = %d, want 1", updated)
+	if len(result.Updated) != 1 || result.Updated[0] != targetFormula {
+		t.Errorf("Updated = %v, want [%s]", result.Updated, targetFormula)
 	}
-	if skipped != 0 {
-		t.Errorf("skipped = %d, want 0", skipped)
+	if len(result.Skipped) != 0 {
+		t.Errorf("Skipped = %v, want none", result.Skipped)
 	}
-	if reinstalled != 0 {
-		t.Errorf("reinstalled = %d, want 0", reinstalled)
+	if len(result.Reinstalled) != 0 {
+		t.Errorf("Reinstalled = %v, want none", result.Reinstalled)
+	}
+	if len(result.Added) != 0 {
+		t.Errorf("Added = %v, want none", result.Added)
+	}
+	if got := result.Summary(); got != "1 ritual updated" {
+		t.Errorf("Summary() = %q, want %q", got, "1 ritual updated")
 	}
 
 	// Verify file was updated
@@ -365,13 +397,16 @@ func TestUpdateFormulas_SkipsModified(t *testing.T) {
 	}
 
 	// Run update - should skip the modified ritual
-	_, skipped, _, err := UpdateFormulas(tmpDir)
+	result, err := UpdateFormulas(tmpDir)
 	if err != nil {
 		t.Fatalf("UpdateFormulas() error: %v", err)
 	}
 
-	if skipped != 1 {
-		t.Errorf("skipped = %d, want 1", skipped)
+	if len(result.Skipped) != 1 || result.Skipped[0] != targetFormula {
+		t.Errorf("Skipped = %v, want [%s]", result.Skipped, targetFormula)
+	}
+	if result.Changed() {
+		t.Errorf("Changed() = true, want false (only a modified ritual): %s", result.Summary())
 	}
 
 	// Verify file was NOT changed
@@ -416,13 +451,13 @@ func TestUpdateFormulas_ReinstallsMissing(t *testing.T) {
 	}
 
 	// Run update
-	_, _, reinstalled, err := UpdateFormulas(tmpDir)
+	result, err := UpdateFormulas(tmpDir)
 	if err != nil {
 		t.Fatalf("UpdateFormulas() error: %v", err)
 	}
 
-	if reinstalled != 1 {
-		t.Errorf("reinstalled = %d, want 1", reinstalled)
+	if len(result.Reinstalled) != 1 {
+		t.Errorf("Reinstalled = %v, want 1 entry", result.Reinstalled)
 	}
 
 	// Verify file was restored
@@ -448,23 +483,25 @@ func TestUpdateFormulas_InstallsNew(t *testing.T) {
 	}
 
 	// Run update - should install all rituals as "new"
-	updated, skipped, reinstalled, err := UpdateFormulas(tmpDir)
+	result, err := UpdateFormulas(tmpDir)
 	if err != nil {
 		t.Fatalf("UpdateFormulas() error: %v", err)
 	}
 
-	// All rituals should be installed
+	// All rituals should be installed as newly added
 	embedded, err := getEmbeddedFormulas()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	total := updated + reinstalled
-	if total != len(embedded) {
-		t.Errorf("total installed = %d, want %d", total, len(embedded))
+	if len(result.Added) != len(embedded) {
+		t.Errorf("Added = %d, want %d", len(result.Added), len(embedded))
+	}
+	if len(result.Updated)+len(result.Reinstalled) != 0 {
+		t.Errorf("Updated/Reinstalled = %v/%v, want none", result.Updated, result.Reinstalled)
 	}
-	if skipped != 0 {
-		t.Errorf("skipped = %d, want 0", skipped)
+	if len(result.Skipped) != 0 {
+		t.Errorf("Skipped = %v, want none", result.Skipped)
 	}
 }
 
@@ -607,20 +644,20 @@ func TestUpdateFormulas_UpdatesUntracked(t *testing.T) {
 	}
 
 	// Run update - should update all untracked rituals
-	updated, skipped, reinstalled, err := UpdateFormulas(tmpDir)
+	result, err := UpdateFormulas(tmpDir)
 	if err != nil {
 		t.Fatalf("UpdateFormulas() error: %v", err)
 	}
 
 	// All untracked files should be updated (counted as "updated", not "reinstalled")
-	if updated != len(embedded) {
-		t.Errorf("updated = %d, want %d", updated, len(embedded))
+	if len(result.Updated) != len(embedded) {
+		t.Errorf("Updated = %d, want %d", len(result.Updated), len(embedded))
 	}
-	if skipped != 0 {
-		t.Errorf("skipped = %d, want 0", skipped)
+	if len(result.Skipped) != 0 {
+		t.Errorf("Skipped = %v, want none", result.Skipped)
 	}
-	if reinstalled != 0 {
-		t.Errorf("reinstalled = %d, want 0", reinstalled)
+	if len(result.Reinstalled) != 0 {
+		t.Errorf("Reinstalled = %v, want none", result.Reinstalled)
 	}
 
 	// Verify files now match embedded
@@ -746,3 +783,286 @@ func TestCheckFormulaHealth_MixedScenarios(t *testing.T) {
 		t.Errorf("ritual %s status = %q, want %q", modifiedFormula, statusMap[modifiedFormula], "modified")
 	}
 }
+
+// TestPlanProvision tests that the plan reports each ritual's action
+// without writing anything.
+func TestPlanProvision(t *testing.T) {
+	tmpDir := t.TempDir()
+	formulasDir := filepath.Join(tmpDir, ".relics", "rituals")
+
+	// Fresh workspace: everything would be created, nothing is written
+	plan, err := PlanProvision(tmpDir)
+	if err != nil {
+		t.Fatalf("PlanProvision() error: %v", err)
+	}
+	if len(plan.Rituals) == 0 {
+		t.Skip("no embedded rituals")
+	}
+	if got := plan.Count(ActionCreate); got != len(plan.Rituals) {
+		t.Errorf("Count(create) = %d, want %d", got, len(plan.Rituals))
+	}
+	if _, err := os.Stat(formulasDir); !os.IsNotExist(err) {
+		t.Errorf("PlanProvision created %s", formulasDir)
+	}
+
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatalf("ProvisionFormulas() error: %v", err)
+	}
+	if len(plan.Rituals) < 3 {
+		t.Skip("need at least 3 embedded rituals")
+	}
+
+	// One modified by the user, one deleted, one left untracked
+	modified, deleted, untracked := plan.Rituals[0].Name, plan.Rituals[1].Name, plan.Rituals[2].Name
+	modifiedPath := filepath.Join(formulasDir, modified)
+	if err := os.WriteFile(modifiedPath, []byte("# customized\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(formulasDir, deleted)); err != nil {
+		t.Fatal(err)
+	}
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(installed.Rituals, untracked)
+	if err := saveInstalledRecord(formulasDir, installed); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(formulasDir, untracked), []byte("# old version\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err = PlanProvision(tmpDir)
+	if err != nil {
+		t.Fatalf("PlanProvision() error: %v", err)
+	}
+	want := map[string]ProvisionAction{modified: ActionSkip, deleted: ActionCreate, untracked: ActionUpdate}
+	for _, f := range plan.Rituals {
+		wantAction, ok := want[f.Name]
+		if !ok {
+			wantAction = ActionNone
+		}
+		if f.Action != wantAction {
+			t.Errorf("%s: Action = %s (status %s), want %s", f.Name, f.Action, f.Status, wantAction)
+		}
+	}
+	if got := plan.Summary(); got != "1 ritual to create, 1 ritual to update, 1 skipped (locally modified)" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// Planning wrote nothing; the user's change is still there
+	content, err := os.ReadFile(modifiedPath)
+	if err != nil || string(content) != "# customized\n" {
+		t.Errorf("modified ritual changed by PlanProvision: %q, %v", content, err)
+	}
+}
+
+// TestPlanProvisionUnreadable tests that a ritual that can't be read is
+// reported on its own rather than as locally modified.
+func TestPlanProvisionUnreadable(t *testing.T) {
+	tmpDir := t.TempDir()
+	formulasDir := filepath.Join(tmpDir, ".relics", "rituals")
+
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatalf("ProvisionFormulas() error: %v", err)
+	}
+	plan, err := PlanProvision(tmpDir)
+	if err != nil {
+		t.Fatalf("PlanProvision() error: %v", err)
+	}
+	if len(plan.Rituals) == 0 {
+		t.Skip("no embedded rituals")
+	}
+
+	// A directory in place of the file makes the read fail
+	unreadable := plan.Rituals[0].Name
+	path := filepath.Join(formulasDir, unreadable)
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err = PlanProvision(tmpDir)
+	if err != nil {
+		t.Fatalf("PlanProvision() error: %v", err)
+	}
+	for _, f := range plan.Rituals {
+		if f.Name != unreadable {
+			continue
+		}
+		if f.Action != ActionError {
+			t.Errorf("%s: Action = %s (status %s), want %s", f.Name, f.Action, f.Status, ActionError)
+		}
+		if f.Err == nil {
+			t.Errorf("%s: Err = nil, want the read error", f.Name)
+		}
+	}
+	if got := plan.Summary(); got != "1 skipped (unreadable)" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	result, err := UpdateFormulas(tmpDir)
+	if err != nil {
+		t.Fatalf("UpdateFormulas() error: %v", err)
+	}
+	if len(result.Skipped) != 0 || len(result.Unreadable) != 1 || result.Unreadable[0] != unreadable {
+		t.Errorf("UpdateFormulas() Skipped = %v, Unreadable = %v", result.Skipped, result.Unreadable)
+	}
+}
+
+// TestContentHash tests that formatting differences don't change a ritual's
+// content hash but real edits do.
+func TestContentHash(t *testing.T) {
+	base := []byte(`ritual = "hash-test"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "a"
+title = "A"
+
+[[steps]]
+id = "b"
+title = "B"
+needs = ["a"]
+`)
+	reformatted := []byte(`# A comment the canonical form drops
+ritual    = "hash-test"
+version = 1
+type = "workflow"
+
+
+[[steps]]
+  id = "a"
+  title = " A "
+
+[[steps]]
+  id = "b"
+  title = "B"
+  needs = [ "a", "a" ]
+`)
+	edited := []byte(`ritual = "hash-test"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "a"
+title = "A"
+
+[[steps]]
+id = "b"
+title = "B, renamed"
+needs = ["a"]
+`)
+
+	if ContentHash(base) != ContentHash(reformatted) {
+		t.Error("reformatting changed the content hash")
+	}
+	if ContentHash(base) == ContentHash(edited) {
+		t.Error("editing a title didn't change the content hash")
+	}
+
+	// Unparseable content falls back to the byte hash
+	junk := []byte("not a ritual")
+	if got, want := ContentHash(junk), computeHash(junk); got != want {
+		t.Errorf("ContentHash(junk) = %s, want byte hash %s", got, want)
+	}
+}
+
+// TestPlanProvision_Reformatted tests that reformatting an installed ritual
+// isn't treated as a user modification.
+func TestPlanProvision_Reformatted(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatalf("ProvisionFormulas() error: %v", err)
+	}
+	formulasDir := filepath.Join(tmpDir, ".relics", "rituals")
+
+	embedded, err := getEmbeddedFormulas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target string
+	for name := range embedded {
+		if content, _ := formulasFS.ReadFile("rituals/" + name); ContentHash(content) != computeHash(content) {
+			target = name // parses, so it has a canonical form
+			break
+		}
+	}
+	if target == "" {
+		t.Skip("no parseable embedded rituals")
+	}
+
+	// Add a comment and trailing blank lines
+	path := filepath.Join(formulasDir, target)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reformatted := append([]byte("# local note\n"), content...)
+	reformatted = append(reformatted, "\n\n"...)
+	if err := os.WriteFile(path, reformatted, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := PlanProvision(tmpDir)
+	if err != nil {
+		t.Fatalf("PlanProvision() error: %v", err)
+	}
+	for _, f := range plan.Rituals {
+		if f.Name == target && (f.Status != "ok" || f.Action != ActionNone) {
+			t.Errorf("%s: Status = %s, Action = %s, want ok/none", f.Name, f.Status, f.Action)
+		}
+	}
+
+	// Provisioning records the content hash alongside the byte hash
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed.Content[target] != ContentHash(content) {
+		t.Errorf("installed content hash for %s = %q, want %q", target, installed.Content[target], ContentHash(content))
+	}
+}
+
+// TestClassifyFormula_Reformatted tests that a reformatted copy of an older
+// installed version is outdated, not modified, when its content hash was
+// recorded.
+func TestClassifyFormula_Reformatted(t *testing.T) {
+	embedded, err := getEmbeddedFormulas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target string
+	for name := range embedded {
+		target = name
+		break
+	}
+	if target == "" {
+		t.Skip("no embedded rituals")
+	}
+
+	installedContent := []byte("ritual = \"old\"\ntype = \"workflow\"\n\n[[steps]]\nid = \"a\"\ntitle = \"A\"\n")
+	onDisk := []byte("# reformatted\nritual = \"old\"\ntype = \"workflow\"\n\n[[steps]]\n  id = \"a\"\n  title = \"A\"\n")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, target), onDisk, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	record := &InstalledRecord{
+		Rituals: map[string]string{target: computeHash(installedContent)},
+		Content: map[string]string{target: ContentHash(installedContent)},
+	}
+	if status, _, _ := classifyFormula(dir, target, embedded[target], record); status != "outdated" {
+		t.Errorf("with content hash: status = %s, want outdated", status)
+	}
+
+	// Records from before content hashes were tracked compare bytes only
+	record.Content = map[string]string{}
+	if status, _, _ := classifyFormula(dir, target, embedded[target], record); status != "modified" {
+		t.Errorf("without content hash: status = %s, want modified", status)
+	}
+}
@@ -0,0 +1,23 @@
+package ritual
+
+import "regexp"
+
+// envVarName matches the names allowed as keys of a step's env table.
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// StepEnv returns a copy of the [steps.env] table of workflow step id, for
+// the caller to merge over the agent's base environment when dispatching
+// the step. Values may contain {placeholders}, which Render fills in like
+// the step's other text. Returns nil if the step has no env or doesn't
+// exist.
+func (f *Ritual) StepEnv(id string) map[string]string {
+	step := f.GetStep(id)
+	if step == nil || len(step.Env) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(step.Env))
+	for k, v := range step.Env {
+		env[k] = v
+	}
+	return env
+}
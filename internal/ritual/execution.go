@@ -0,0 +1,60 @@
+package ritual
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultShell is the interpreter a step's command runs under when the step
+// sets no shell.
+const DefaultShell = "sh"
+
+// StepDir returns the directory workflow step id runs in: its cwd joined to
+// root, the execution root (typically the ritual file's directory), or root
+// itself if the step sets no cwd. It returns an error if the step doesn't
+// exist or its cwd escapes root.
+func (f *Ritual) StepDir(id, root string) (string, error) {
+	step := f.GetStep(id)
+	if step == nil {
+		return "", fmt.Errorf("unknown step: %s", id)
+	}
+	if err := validateStepCwd(step.Cwd); err != nil {
+		return "", fmt.Errorf("step %q: %w", id, err)
+	}
+	return filepath.Join(root, step.Cwd), nil
+}
+
+// StepShell returns the interpreter workflow step id's command runs under,
+// as `<shell> -c <command>`: the step's shell, or DefaultShell if it sets
+// none or doesn't exist.
+func (f *Ritual) StepShell(id string) string {
+	if step := f.GetStep(id); step != nil && step.Shell != "" {
+		return step.Shell
+	}
+	return DefaultShell
+}
+
+// validateStepCwd checks that cwd is a relative path that stays inside the
+// execution root once cleaned.
+func validateStepCwd(cwd string) error {
+	if cwd == "" {
+		return nil
+	}
+	if filepath.IsAbs(cwd) {
+		return fmt.Errorf("cwd %q must be relative to the execution root", cwd)
+	}
+	if clean := filepath.Clean(cwd); clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("cwd %q escapes the execution root", cwd)
+	}
+	return nil
+}
+
+// validateStepShell checks that shell, if set, is a single command name or
+// path; arguments belong in the step's command.
+func validateStepShell(shell string) error {
+	if shell != "" && (strings.TrimSpace(shell) == "" || strings.ContainsAny(shell, " \t\n")) {
+		return fmt.Errorf("invalid shell %q: must be a command name or path", shell)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package ritual
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FailurePolicy controls what an Executor does after a step fails.
+type FailurePolicy string
+
+const (
+	// FailStop dispatches no further waves once a step fails.
+	// Steps already running in the current wave are allowed to finish.
+	FailStop FailurePolicy = "stop"
+	// FailContinue keeps dispatching steps that don't depend on a failed step.
+	FailContinue FailurePolicy = "continue"
+)
+
+// StepStatus is the outcome of a single step in an execution.
+type StepStatus string
+
+const (
+	// StepCompleted means the runner returned nil for the step.
+	StepCompleted StepStatus = "completed"
+	// StepFailed means the runner returned an error for the step.
+	StepFailed StepStatus = "failed"
+	// StepSkipped means the step never ran, because a dependency failed,
+	// the failure policy stopped execution, or the context was cancelled.
+	StepSkipped StepStatus = "skipped"
+)
+
+// StepResult records the outcome of a step.
+type StepResult struct {
+	Status StepStatus
+	Err    error // runner error for failed steps, nil otherwise
+}
+
+// StepRunner executes a single step. The Executor owns scheduling; the runner
+// does the actual work (e.g., dispatching an agent) and returns when the step
+// is finished.
+type StepRunner func(ctx context.Context, stepID string) error
+
+// Executor drives a ritual to completion wave by wave: each wave dispatches
+// every ready step concurrently, waits for all of them, then recomputes the
+// ready set from the steps that completed. Ready steps that share a
+// concurrency_group are spread over successive waves, one per wave.
+type Executor struct {
+	Ritual *Ritual
+
+	// ParallelLimit caps how many steps run at once within a wave.
+	// Zero or negative means no limit.
+	ParallelLimit int
+
+	// OnFailure selects the failure policy. Empty means FailStop.
+	OnFailure FailurePolicy
+
+	// OnWave, if set, is called with the step IDs of each wave just before
+	// it is dispatched, e.g. to report progress.
+	OnWave func(wave []string)
+}
+
+// NewExecutor creates an Executor for the ritual with the default policy
+// (no parallel limit, stop on failure).
+func NewExecutor(f *Ritual) *Executor {
+	return &Executor{Ritual: f, OnFailure: FailStop}
+}
+
+// Execute runs every step of the ritual (as reported by GetAllIDs) through
+// runner and returns a result for each one. Steps that never ran are reported
+// as StepSkipped.
+//
+// A failed optional step is reported as StepFailed but counts as satisfied
+// for its dependents, and doesn't trigger the failure policy. A soft need
+// (see NeedList) only waits for its step to finish or be skipped, so under
+// FailContinue a soft dependent still runs after its predecessor fails.
+//
+// The returned error is nil only if every required step completed. Otherwise
+// it reports the context error, or the first failed required step in ritual
+// order.
+func (e *Executor) Execute(ctx context.Context, runner StepRunner) (map[string]StepResult, error) {
+	switch e.OnFailure {
+	case "", FailStop, FailContinue:
+	default:
+		return nil, fmt.Errorf("invalid failure policy %q", e.OnFailure)
+	}
+
+	results := make(map[string]StepResult)
+	completed := make(map[string]bool) // dependents may proceed
+	settled := make(map[string]bool)   // finished, or blocked by a failed hard need
+	failed := false
+
+	for ctx.Err() == nil && !(failed && e.OnFailure != FailContinue) {
+		var wave []string
+		for _, id := range e.Ritual.readyAfter(completed, settled) {
+			if _, done := results[id]; !done {
+				wave = append(wave, id)
+			}
+		}
+		// One step per concurrency group; the rest wait for a later wave
+		wave = e.Ritual.exclusive(wave, nil)
+		if len(wave) == 0 {
+			break
+		}
+		if e.OnWave != nil {
+			e.OnWave(wave)
+		}
+
+		for id, result := range e.runWave(ctx, wave, runner) {
+			results[id] = result
+			settled[id] = true
+			switch result.Status {
+			case StepCompleted:
+				completed[id] = true
+			case StepFailed:
+				if e.Ritual.IsOptional(id) {
+					completed[id] = true
+				} else {
+					failed = true
+				}
+			}
+		}
+		e.Ritual.settleBlocked(completed, settled)
+	}
+
+	ids := e.Ritual.GetAllIDs()
+	for _, id := range ids {
+		if _, done := results[id]; !done {
+			results[id] = StepResult{Status: StepSkipped}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	for _, id := range ids {
+		if r := results[id]; r.Status == StepFailed && !e.Ritual.IsOptional(id) {
+			return results, fmt.Errorf("step %q failed: %w", id, r.Err)
+		}
+	}
+	return results, nil
+}
+
+// runWave runs the given steps concurrently, honoring ParallelLimit, and
+// returns their results once all of them have finished. Steps not started
+// before the context is cancelled are reported as skipped.
+func (e *Executor) runWave(ctx context.Context, wave []string, runner StepRunner) map[string]StepResult {
+	limit := e.ParallelLimit
+	if limit <= 0 || limit > len(wave) {
+		limit = len(wave)
+	}
+	sem := make(chan struct{}, limit)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]StepResult, len(wave))
+
+	for _, id := range wave {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			mu.Lock()
+			results[id] = StepResult{Status: StepSkipped}
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := StepResult{Status: StepCompleted}
+			if err := runner(ctx, id); err != nil {
+				result = StepResult{Status: StepFailed, Err: err}
+			}
+
+			mu.Lock()
+			results[id] = result
+			mu.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,183 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupRefPrefix marks a needs entry as a reference to a step group.
+const GroupRefPrefix = "group:"
+
+// expandGroups replaces group references in step and template needs with the
+// group's member IDs, so TopologicalSort and ReadySteps see plain dependencies.
+// Groups may contain other groups; membership is resolved transitively.
+// Returns an error for undefined groups or members, duplicate group IDs, or
+// cycles between groups.
+func (f *Ritual) expandGroups() error {
+	items := make(map[string]bool)
+	for _, step := range f.Steps {
+		items[step.ID] = true
+	}
+	for _, tmpl := range f.Template {
+		items[tmpl.ID] = true
+	}
+
+	groups := make(map[string]*Group, len(f.Groups))
+	for i := range f.Groups {
+		g := &f.Groups[i]
+		if g.ID == "" {
+			return fmt.Errorf("group missing required id field")
+		}
+		if groups[g.ID] != nil {
+			return fmt.Errorf("duplicate group id: %s", g.ID)
+		}
+		if len(g.Members) == 0 {
+			return fmt.Errorf("group %q has no members", g.ID)
+		}
+		groups[g.ID] = g
+	}
+
+	// Resolve each group's members to plain IDs (memoized, with cycle detection)
+	resolved := make(map[string][]string)
+	inStack := make(map[string]bool)
+	var resolve func(id string) ([]string, error)
+	resolve = func(id string) ([]string, error) {
+		if members, ok := resolved[id]; ok {
+			return members, nil
+		}
+		if inStack[id] {
+			return nil, fmt.Errorf("cycle detected involving group: %s", id)
+		}
+		inStack[id] = true
+
+		var members []string
+		for _, member := range groups[id].Members {
+			if ref, ok := strings.CutPrefix(member, GroupRefPrefix); ok {
+				if groups[ref] == nil {
+					return nil, fmt.Errorf("group %q references unknown group: %s", id, ref)
+				}
+				nested, err := resolve(ref)
+				if err != nil {
+					return nil, err
+				}
+				members = append(members, nested...)
+				continue
+			}
+			if !items[member] {
+				return nil, fmt.Errorf("group %q references unknown step: %s", id, member)
+			}
+			members = append(members, member)
+		}
+
+		inStack[id] = false
+		resolved[id] = dedupe(members)
+		return resolved[id], nil
+	}
+
+	for _, g := range f.Groups {
+		if _, err := resolve(g.ID); err != nil {
+			return err
+		}
+	}
+
+	expand := func(owner string, needs []string) ([]string, error) {
+		var out []string
+		for _, need := range needs {
+			ref, ok := strings.CutPrefix(need, GroupRefPrefix)
+			if !ok {
+				out = append(out, need)
+				continue
+			}
+			if groups[ref] == nil {
+				return nil, fmt.Errorf("%q needs unknown group: %s", owner, ref)
+			}
+			out = append(out, resolved[ref]...)
+		}
+		return dedupe(out), nil
+	}
+
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		needs, err := expand(step.ID, step.Needs)
+		if err != nil {
+			return fmt.Errorf("step %w", err)
+		}
+		if len(step.SoftNeeds) > 0 {
+			// A step reached through both a hard and a soft reference
+			// stays a hard need.
+			hard, err := expand(step.ID, step.hardNeeds())
+			if err != nil {
+				return fmt.Errorf("step %w", err)
+			}
+			soft, err := expand(step.ID, step.SoftNeeds)
+			if err != nil {
+				return fmt.Errorf("step %w", err)
+			}
+			isHard := make(map[string]bool, len(hard))
+			for _, id := range hard {
+				isHard[id] = true
+			}
+			step.SoftNeeds = nil
+			for _, id := range soft {
+				if !isHard[id] {
+					step.SoftNeeds = append(step.SoftNeeds, id)
+				}
+			}
+		}
+		step.Needs = needs
+	}
+	for i := range f.Template {
+		needs, err := expand(f.Template[i].ID, f.Template[i].Needs)
+		if err != nil {
+			return fmt.Errorf("template %w", err)
+		}
+		f.Template[i].Needs = needs
+	}
+
+	return nil
+}
+
+// GroupMembers returns the expanded step IDs in a group, or nil if the group
+// doesn't exist. Nested group references are resolved.
+func (f *Ritual) GroupMembers(id string) []string {
+	var members []string
+	visited := make(map[string]bool)
+	var walk func(gid string)
+	walk = func(gid string) {
+		if visited[gid] {
+			return
+		}
+		visited[gid] = true
+		for _, g := range f.Groups {
+			if g.ID != gid {
+				continue
+			}
+			for _, member := range g.Members {
+				if ref, ok := strings.CutPrefix(member, GroupRefPrefix); ok {
+					walk(ref)
+				} else {
+					members = append(members, member)
+				}
+			}
+		}
+	}
+	walk(id)
+	return dedupe(members)
+}
+
+// dedupe removes duplicate entries while preserving first-seen order.
+func dedupe(ids []string) []string {
+	if len(ids) == 0 {
+		return ids
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package ritual
+
+import "bytes"
+
+// ContentHash returns the SHA256 hash of a ritual file's canonical form: the
+// TOML that WriteTOML produces after parsing data. Two files that differ only
+// in formatting, comments, key order, or the order of needs lists hash the
+// same, so a reformatted ritual isn't mistaken for a modified one.
+//
+// Content that doesn't parse as a valid ritual has no canonical form and is
+// hashed byte for byte.
+func ContentHash(data []byte) string {
+	f, err := Parse(data)
+	if err != nil {
+		return computeHash(data)
+	}
+	var buf bytes.Buffer
+	if err := f.WriteTOML(&buf); err != nil {
+		return computeHash(data)
+	}
+	return computeHash(buf.Bytes())
+}
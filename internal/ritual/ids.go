@@ -0,0 +1,61 @@
+package ritual
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// safeID matches the step, leg, aspect, and template IDs that can be used
+// as-is in session names, directory names, and bead IDs downstream.
+var safeID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// idPlaceholder matches an expansion placeholder in a template ID, such as
+// {target} or {step.id}.
+var idPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// safeIDRule describes safeID in error messages.
+const safeIDRule = "must start with a letter or digit and contain only letters, digits, '.', '_', and '-'"
+
+// validateIDs checks that every step, leg, aspect, and template ID is safe
+// (see safeID) and that no two IDs differ only in case, since they
+// would collide on case-insensitive file systems. Placeholders in template
+// IDs are checked as if filled in with a safe value. Missing and exactly
+// duplicated IDs are left to the type-specific checks, which report them
+// more specifically.
+func (f *Ritual) validateIDs() error {
+	type node struct{ kind, id string }
+	var nodes []node
+	for _, step := range f.Steps {
+		nodes = append(nodes, node{"step", step.ID})
+	}
+	for _, leg := range f.Legs {
+		nodes = append(nodes, node{"leg", leg.ID})
+	}
+	for _, aspect := range f.Aspects {
+		nodes = append(nodes, node{"aspect", aspect.ID})
+	}
+	for _, tmpl := range f.Template {
+		nodes = append(nodes, node{"template", tmpl.ID})
+	}
+
+	byFold := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		if n.id == "" {
+			continue
+		}
+		check := n.id
+		if n.kind == "template" {
+			check = idPlaceholder.ReplaceAllString(check, "x")
+		}
+		if !safeID.MatchString(check) {
+			return fmt.Errorf("invalid %s id %q: %s", n.kind, n.id, safeIDRule)
+		}
+		folded := strings.ToLower(n.id)
+		if other, ok := byFold[folded]; ok && other != n.id {
+			return fmt.Errorf("%s id %q collides with %q: ids must differ in more than case", n.kind, n.id, other)
+		}
+		byFold[folded] = n.id
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package ritual
+
+import (
+	"fmt"
+	"slices"
+)
+
+// InsertStep adds step to a workflow ritual so that it runs after every
+// step in after and before every step in before: step needs each of after,
+// and each of before gains a need on step. Existing needs are kept, so a
+// direct need from a before step on an after step stays in place (it is
+// now implied, but harmless). Soft needs and depends_on on step are folded
+// into its needs as during parsing.
+//
+// The step is placed ahead of the first before step in ritual order, or
+// last if before is empty, so TopologicalSort keeps the surrounding order.
+// The ritual is validated with the step in place and left unchanged if
+// that fails, e.g. because the insertion would create a cycle (some step in
+// before already runs ahead of one in after).
+func (f *Ritual) InsertStep(step Step, before, after []string) error {
+	if f.Type != TypeWorkflow {
+		return fmt.Errorf("cannot insert a step into a %s ritual", f.Type)
+	}
+	if step.ID == "" {
+		return fmt.Errorf("step missing required id field")
+	}
+	if f.GetStep(step.ID) != nil {
+		return fmt.Errorf("step id already in use: %s", step.ID)
+	}
+	for _, id := range append(slices.Clone(before), after...) {
+		if f.GetStep(id) == nil {
+			return fmt.Errorf("unknown step: %s", id)
+		}
+	}
+
+	step.Needs = append(NeedList(nil), step.Needs...)
+	for _, id := range slices.Concat(step.DependsOn, step.SoftNeeds, after) {
+		if !slices.Contains(step.Needs, id) {
+			step.Needs = append(step.Needs, id)
+		}
+	}
+	step.DependsOn = nil
+	step.SoftNeeds = slices.Clone(step.SoftNeeds)
+
+	g := f.clone()
+	at := len(g.Steps)
+	for i := range g.Steps {
+		s := &g.Steps[i]
+		if !slices.Contains(before, s.ID) {
+			continue
+		}
+		at = min(at, i)
+		if !slices.Contains(s.Needs, step.ID) {
+			s.Needs = append(s.Needs, step.ID)
+		}
+	}
+	g.Steps = slices.Insert(g.Steps, at, step)
+
+	if err := g.Validate(); err != nil {
+		return fmt.Errorf("inserting step %s: %w", step.ID, err)
+	}
+	*f = *g
+	return nil
+}
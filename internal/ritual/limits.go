@@ -0,0 +1,119 @@
+package ritual
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded indicates a ritual is too large or too deeply nested to parse.
+var ErrLimitExceeded = errors.New("ritual exceeds parse limit")
+
+// ParseOptions bounds how much work parsing a ritual may do.
+// A zero field means no limit for that dimension.
+type ParseOptions struct {
+	// MaxSteps caps the number of steps, templates, legs, aspects, and groups.
+	MaxSteps int
+	// MaxDepth caps the longest dependency chain, counted in steps.
+	MaxDepth int
+	// MaxNeeds caps the dependencies of a single step or template, after
+	// group references are expanded.
+	MaxNeeds int
+}
+
+// DefaultParseOptions returns the limits used by Parse and ParseFile.
+// They leave ample room above the largest embedded rituals (the generated
+// towers-of-hanoi ones) while keeping untrusted input cheap.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{
+		MaxSteps: 10000,
+		MaxDepth: 10000,
+		MaxNeeds: 200,
+	}
+}
+
+// checkSizeLimits rejects rituals with too many items.
+func (f *Ritual) checkSizeLimits(opts ParseOptions) error {
+	if opts.MaxSteps <= 0 {
+		return nil
+	}
+	n := len(f.Steps) + len(f.Template) + len(f.Legs) + len(f.Aspects) + len(f.Groups)
+	if n > opts.MaxSteps {
+		return fmt.Errorf("%w: %d steps, max %d", ErrLimitExceeded, n, opts.MaxSteps)
+	}
+	return nil
+}
+
+// checkNeedsLimit rejects steps or templates with too many dependencies.
+func (f *Ritual) checkNeedsLimit(opts ParseOptions) error {
+	if opts.MaxNeeds <= 0 {
+		return nil
+	}
+	for _, step := range f.Steps {
+		if len(step.Needs) > opts.MaxNeeds {
+			return fmt.Errorf("%w: step %q has %d needs, max %d", ErrLimitExceeded, step.ID, len(step.Needs), opts.MaxNeeds)
+		}
+	}
+	for _, tmpl := range f.Template {
+		if len(tmpl.Needs) > opts.MaxNeeds {
+			return fmt.Errorf("%w: template %q has %d needs, max %d", ErrLimitExceeded, tmpl.ID, len(tmpl.Needs), opts.MaxNeeds)
+		}
+	}
+	return nil
+}
+
+// checkDepthLimit rejects workflow and expansion rituals whose longest
+// dependency chain exceeds MaxDepth. Raid and aspect rituals are at most two
+// levels deep (legs/aspects, then synthesis) and are not checked.
+func (f *Ritual) checkDepthLimit(opts ParseOptions) error {
+	if opts.MaxDepth <= 0 {
+		return nil
+	}
+
+	deps := make(map[string][]string)
+	var ids []string
+	switch f.Type {
+	case TypeWorkflow:
+		for _, step := range f.Steps {
+			deps[step.ID] = step.Needs
+			ids = append(ids, step.ID)
+		}
+	case TypeExpansion:
+		for _, tmpl := range f.Template {
+			deps[tmpl.ID] = tmpl.Needs
+			ids = append(ids, tmpl.ID)
+		}
+	default:
+		return nil
+	}
+
+	// Memoized longest-path DFS. Expansion needs aren't cycle-checked by
+	// Validate, so edges back into the current path are ignored.
+	depth := make(map[string]int)
+	inStack := make(map[string]bool)
+	var visit func(id string) int
+	visit = func(id string) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		inStack[id] = true
+		d := 1
+		for _, dep := range deps[id] {
+			if inStack[dep] {
+				continue
+			}
+			if dd := visit(dep) + 1; dd > d {
+				d = dd
+			}
+		}
+		inStack[id] = false
+		depth[id] = d
+		return d
+	}
+
+	for _, id := range ids {
+		if d := visit(id); d > opts.MaxDepth {
+			return fmt.Errorf("%w: dependency chain ending at %q is %d steps deep, max %d", ErrLimitExceeded, id, d, opts.MaxDepth)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// synthesisNodeID is the graph node ID used for a raid or aspect ritual's
+// synthesis, matching the ID GetDependencies accepts.
+const synthesisNodeID = "synthesis"
+
+// RenderMermaid renders the ritual's dependency graph as a Mermaid
+// `graph TD` flowchart, suitable for embedding in markdown. Nodes are
+// labeled with item titles (falling back to IDs) and edges point from a
+// dependency to its dependent. Synthesis and aspect nodes get their own
+// classes so they stand out; optional steps are drawn dashed.
+func (f *Ritual) RenderMermaid() string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+
+	ids := f.GetAllIDs()
+	nodes := make(map[string]string, len(ids)+1)
+	used := make(map[string]bool, len(ids)+1)
+	nodeID := func(id string) string {
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+		n := mermaidNodeID(id)
+		for base, i := n, 2; used[n]; i++ {
+			n = fmt.Sprintf("%s_%d", base, i)
+		}
+		nodes[id] = n
+		used[n] = true
+		return n
+	}
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", nodeID(id), mermaidLabel(f.itemTitle(id), id))
+	}
+
+	if f.hasSynthesisNode() {
+		fmt.Fprintf(&b, "    %s[[\"%s\"]]\n", nodeID(synthesisNodeID), mermaidLabel(f.Synthesis.Title, synthesisNodeID))
+	}
+
+	for _, id := range ids {
+		for _, dep := range f.GetDependencies(id) {
+			fmt.Fprintf(&b, "    %s --> %s\n", nodeID(dep), nodeID(id))
+		}
+	}
+	for _, dep := range f.synthesisDeps() {
+		fmt.Fprintf(&b, "    %s --> %s\n", nodeID(dep), nodeID(synthesisNodeID))
+	}
+
+	if f.Type == TypeAspect && len(ids) > 0 {
+		b.WriteString("    classDef aspect fill:#e8f0fe,stroke:#4a6fa5\n")
+		aspectNodes := make([]string, len(ids))
+		for i, id := range ids {
+			aspectNodes[i] = nodeID(id)
+		}
+		fmt.Fprintf(&b, "    class %s aspect\n", strings.Join(aspectNodes, ","))
+	}
+	if f.hasSynthesisNode() {
+		b.WriteString("    classDef synthesis fill:#fff4d6,stroke:#b8860b,stroke-width:2px\n")
+		fmt.Fprintf(&b, "    class %s synthesis\n", nodeID(synthesisNodeID))
+	}
+
+	var optional []string
+	if f.Type == TypeWorkflow {
+		for _, step := range f.Steps {
+			if step.Optional {
+				optional = append(optional, nodeID(step.ID))
+			}
+		}
+	}
+	if len(optional) > 0 {
+		b.WriteString("    classDef optional stroke-dasharray:5 5\n")
+		fmt.Fprintf(&b, "    class %s optional\n", strings.Join(optional, ","))
+	}
+
+	return b.String()
+}
+
+// itemTitle returns the title of the step, leg, template, or aspect with the
+// given ID, or "" if it has none.
+func (f *Ritual) itemTitle(id string) string {
+	switch f.Type {
+	case TypeWorkflow:
+		if s := f.GetStep(id); s != nil {
+			return s.Title
+		}
+	case TypeExpansion:
+		if t := f.GetTemplate(id); t != nil {
+			return t.Title
+		}
+	case TypeRaid:
+		if l := f.GetLeg(id); l != nil {
+			return l.Title
+		}
+	case TypeAspect:
+		if a := f.GetAspect(id); a != nil {
+			return a.Title
+		}
+	}
+	return ""
+}
+
+// mermaidNodeID reduces id to characters Mermaid accepts in a bare node ID.
+func mermaidNodeID(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	switch n := b.String(); n {
+	case "":
+		return "node"
+	case "end":
+		// "end" is a Mermaid keyword and breaks the flowchart.
+		return "end_"
+	default:
+		return n
+	}
+}
+
+// mermaidLabel returns title (or id if title is empty) escaped for use
+// inside a quoted Mermaid label.
+func mermaidLabel(title, id string) string {
+	if title == "" {
+		title = id
+	}
+	title = strings.ReplaceAll(title, "\n", " ")
+	return strings.ReplaceAll(title, `"`, "#quot;")
+}
@@ -0,0 +1,84 @@
+package ritual
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ParseMulti parses a document holding several rituals as a [[rituals]]
+// array, using DefaultParseOptions:
+//
+//	[[rituals]]
+//	ritual = "build"
+//	[[rituals.steps]]
+//	id = "compile"
+//	title = "Compile"
+//
+//	[[rituals]]
+//	ritual = "deploy"
+//	...
+//
+// A document without [[rituals]] is parsed as a single ritual, so callers
+// can accept either layout.
+func ParseMulti(data []byte) ([]*Ritual, error) {
+	return ParseMultiWithOptions(data, DefaultParseOptions())
+}
+
+// ParseMultiWithOptions is ParseMulti with the given limits, which apply to
+// each ritual separately. Each ritual is validated on its own, as if it were
+// in a file of its own; ritual names must be unique within the document, and
+// no other top-level keys are allowed beside [[rituals]].
+func ParseMultiWithOptions(data []byte, opts ParseOptions) ([]*Ritual, error) {
+	var doc struct {
+		Rituals []Ritual `toml:"rituals"`
+	}
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if !md.IsDefined("rituals") {
+		f, err := ParseWithOptions(data, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []*Ritual{f}, nil
+	}
+	if len(doc.Rituals) == 0 {
+		return nil, fmt.Errorf("[[rituals]] defines no rituals")
+	}
+	for _, key := range md.Undecoded() {
+		if len(key) == 1 {
+			return nil, fmt.Errorf("top-level key %q outside [[rituals]]", key[0])
+		}
+	}
+
+	var soft struct {
+		Rituals []softNeedsDoc `toml:"rituals"`
+	}
+	if _, err := toml.Decode(string(data), &soft); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+
+	rituals := make([]*Ritual, 0, len(doc.Rituals))
+	seen := make(map[string]bool, len(doc.Rituals))
+	for i := range doc.Rituals {
+		f := &doc.Rituals[i]
+		label := fmt.Sprintf("ritual %d", i+1)
+		if f.Name != "" {
+			label = fmt.Sprintf("ritual %q", f.Name)
+		}
+		if err := f.applySoftNeeds(soft.Rituals[i]); err != nil {
+			return nil, fmt.Errorf("%s: parsing TOML: %w", label, err)
+		}
+		if err := f.prepare(opts); err != nil {
+			return nil, fmt.Errorf("%s: %w", label, err)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("duplicate ritual name: %s", f.Name)
+		}
+		seen[f.Name] = true
+		rituals = append(rituals, f)
+	}
+	return rituals, nil
+}
@@ -0,0 +1,153 @@
+package ritual
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Normalize rewrites the ritual into a canonical form for stable, diff-friendly
+// output: IDs, titles, and references are trimmed, and dependency lists
+// (needs, depends_on, group members) are de-duplicated and sorted.
+//
+// Normalization never changes semantics. Step, leg, template, and aspect order
+// is preserved, so TopologicalSort and ReadySteps return the same results
+// before and after.
+func (f *Ritual) Normalize() {
+	f.resolveAliases()
+	f.Name = strings.TrimSpace(f.Name)
+
+	for i := range f.Steps {
+		s := &f.Steps[i]
+		s.ID = strings.TrimSpace(s.ID)
+		s.Title = strings.TrimSpace(s.Title)
+		s.Needs = normalizeRefs(s.Needs)
+		s.SoftNeeds = normalizeRefs(s.SoftNeeds)
+		s.Produces = normalizeRefs(s.Produces)
+		s.Consumes = normalizeRefs(s.Consumes)
+		s.ConcurrencyGroup = strings.TrimSpace(s.ConcurrencyGroup)
+		s.Cwd = strings.TrimSpace(s.Cwd)
+		s.Shell = strings.TrimSpace(s.Shell)
+	}
+	for i := range f.Template {
+		t := &f.Template[i]
+		t.ID = strings.TrimSpace(t.ID)
+		t.Title = strings.TrimSpace(t.Title)
+		t.Needs = normalizeRefs(t.Needs)
+	}
+	for i := range f.Legs {
+		l := &f.Legs[i]
+		l.ID = strings.TrimSpace(l.ID)
+		l.Title = strings.TrimSpace(l.Title)
+	}
+	for i := range f.Aspects {
+		a := &f.Aspects[i]
+		a.ID = strings.TrimSpace(a.ID)
+		a.Title = strings.TrimSpace(a.Title)
+	}
+	for i := range f.Groups {
+		g := &f.Groups[i]
+		g.ID = strings.TrimSpace(g.ID)
+		g.Title = strings.TrimSpace(g.Title)
+		g.Members = normalizeRefs(g.Members)
+	}
+	if f.Synthesis != nil {
+		f.Synthesis.Title = strings.TrimSpace(f.Synthesis.Title)
+		f.Synthesis.DependsOn = normalizeRefs(f.Synthesis.DependsOn)
+		f.Synthesis.Mode = strings.TrimSpace(f.Synthesis.Mode)
+		f.Synthesis.Key = strings.TrimSpace(f.Synthesis.Key)
+	}
+}
+
+// WriteTOML writes the ritual to w in canonical form. The receiver is not
+// modified; a normalized copy is encoded instead.
+//
+// Output is deterministic: top-level keys come first (ritual, description,
+// type, version), followed by sections in a fixed order, with map-valued
+// sections (inputs, prompts, vars) sorted by key.
+func (f *Ritual) WriteTOML(w io.Writer) error {
+	c := f.clone()
+	c.Normalize()
+
+	enc := toml.NewEncoder(w)
+	enc.Indent = ""
+	return enc.Encode(c)
+}
+
+// normalizeRefs trims, de-duplicates, and sorts a list of ID references.
+func normalizeRefs(refs []string) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			out = append(out, ref)
+		}
+	}
+	out = dedupe(out)
+	sort.Strings(out)
+	return out
+}
+
+// clone returns a copy of the ritual that shares no slices or maps with f.
+func (f *Ritual) clone() *Ritual {
+	c := *f
+
+	if f.Inputs != nil {
+		c.Inputs = make(map[string]Input, len(f.Inputs))
+		for k, v := range f.Inputs {
+			v.RequiredUnless = append([]string(nil), v.RequiredUnless...)
+			c.Inputs[k] = v
+		}
+	}
+	if f.Prompts != nil {
+		c.Prompts = make(map[string]string, len(f.Prompts))
+		for k, v := range f.Prompts {
+			c.Prompts[k] = v
+		}
+	}
+	if f.Vars != nil {
+		c.Vars = make(map[string]Var, len(f.Vars))
+		for k, v := range f.Vars {
+			c.Vars[k] = v
+		}
+	}
+	if f.Output != nil {
+		out := *f.Output
+		c.Output = &out
+	}
+	if f.Synthesis != nil {
+		syn := *f.Synthesis
+		syn.DependsOn = append([]string(nil), f.Synthesis.DependsOn...)
+		c.Synthesis = &syn
+	}
+
+	c.Legs = append([]Leg(nil), f.Legs...)
+	c.Aspects = append([]Aspect(nil), f.Aspects...)
+	c.Steps = append([]Step(nil), f.Steps...)
+	for i := range c.Steps {
+		c.Steps[i].Needs = append(NeedList(nil), c.Steps[i].Needs...)
+		c.Steps[i].SoftNeeds = append([]string(nil), c.Steps[i].SoftNeeds...)
+		c.Steps[i].Produces = append([]string(nil), c.Steps[i].Produces...)
+		c.Steps[i].Consumes = append([]string(nil), c.Steps[i].Consumes...)
+		if env := f.Steps[i].Env; env != nil {
+			c.Steps[i].Env = make(map[string]string, len(env))
+			for k, v := range env {
+				c.Steps[i].Env[k] = v
+			}
+		}
+	}
+	c.Template = append([]Template(nil), f.Template...)
+	for i := range c.Template {
+		c.Template[i].Needs = append([]string(nil), c.Template[i].Needs...)
+	}
+	c.Groups = append([]Group(nil), f.Groups...)
+	for i := range c.Groups {
+		c.Groups[i].Members = append([]string(nil), c.Groups[i].Members...)
+	}
+
+	return &c
+}
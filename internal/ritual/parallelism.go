@@ -0,0 +1,75 @@
+package ritual
+
+import "fmt"
+
+// Waves groups the ritual's items into dependency levels: every item in a
+// wave depends only on items in earlier waves. This is the schedule an
+// Executor with no parallel limit follows, except that it spreads steps
+// sharing a concurrency_group over extra waves. Raid and aspect rituals
+// form a single wave.
+func (f *Ritual) Waves() ([][]string, error) {
+	total := len(f.GetAllIDs())
+	completed := make(map[string]bool, total)
+
+	var waves [][]string
+	for len(completed) < total {
+		wave := f.ReadySteps(completed)
+		if len(wave) == 0 {
+			return nil, fmt.Errorf("cycle detected in dependencies")
+		}
+		for _, id := range wave {
+			completed[id] = true
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// WaveIndex maps each item ID to the index of its wave in Waves, i.e. its
+// dependency depth: 0 for items with no dependencies. Callers that color or
+// prioritize steps by depth can use it instead of inverting Waves.
+func (f *Ritual) WaveIndex() (map[string]int, error) {
+	waves, err := f.Waves()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int)
+	for i, wave := range waves {
+		for _, id := range wave {
+			index[id] = i
+		}
+	}
+	return index, nil
+}
+
+// ParallelismEstimate summarizes how much concurrency a ritual can use.
+type ParallelismEstimate struct {
+	TotalSteps int // Number of schedulable items
+	MaxWidth   int // Widest wave: workers needed to never queue a ready step
+	Waves      int // Dependency levels, i.e. rounds with unlimited workers
+	Rounds     int // Scheduling rounds under the given parallel limit
+}
+
+// EstimateParallelism computes ideal and limited throughput from Waves.
+// parallelLimit has the same meaning as Executor.ParallelLimit; zero or
+// negative means unlimited, in which case Rounds equals Waves.
+func (f *Ritual) EstimateParallelism(parallelLimit int) (ParallelismEstimate, error) {
+	waves, err := f.Waves()
+	if err != nil {
+		return ParallelismEstimate{}, err
+	}
+
+	est := ParallelismEstimate{Waves: len(waves)}
+	for _, wave := range waves {
+		est.TotalSteps += len(wave)
+		if len(wave) > est.MaxWidth {
+			est.MaxWidth = len(wave)
+		}
+		if parallelLimit > 0 {
+			est.Rounds += (len(wave) + parallelLimit - 1) / parallelLimit
+		} else {
+			est.Rounds++
+		}
+	}
+	return est, nil
+}
@@ -3,34 +3,90 @@ package ritual
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
 
-// ParseFile reads and parses a ritual.toml file.
+// ParseFile reads and parses a ritual.toml file using DefaultParseOptions.
 func ParseFile(path string) (*Ritual, error) {
+	return ParseFileWithOptions(path, DefaultParseOptions())
+}
+
+// ParseFileWithOptions reads and parses a ritual.toml file with the given limits.
+func ParseFileWithOptions(path string, opts ParseOptions) (*Ritual, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted ritual directory
 	if err != nil {
 		return nil, fmt.Errorf("reading ritual file: %w", err)
 	}
-	return Parse(data)
+	return ParseWithOptions(data, opts)
 }
 
-// Parse parses ritual.toml content from bytes.
+// Parse parses ritual.toml content from bytes using DefaultParseOptions.
 func Parse(data []byte) (*Ritual, error) {
+	return ParseWithOptions(data, DefaultParseOptions())
+}
+
+// ParseWithOptions parses ritual.toml content from bytes with the given limits.
+// Size limits are checked before dependency analysis, so oversized files are
+// rejected without running cycle detection or sorting.
+func ParseWithOptions(data []byte, opts ParseOptions) (*Ritual, error) {
 	var f Ritual
-	if _, err := toml.Decode(string(data), &f); err != nil {
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
 		return nil, fmt.Errorf("parsing TOML: %w", err)
 	}
+	if md.IsDefined("rituals") {
+		return nil, fmt.Errorf("document defines [[rituals]]; parse it with ParseMulti")
+	}
+	if err := f.decodeSoftNeeds(string(data)); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if err := f.prepare(opts); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
 
+// prepare turns a freshly decoded ritual into a parsed one: it infers the
+// type, resolves aliases, groups, and artifacts, and enforces opts and
+// Validate.
+func (f *Ritual) prepare(opts ParseOptions) error {
 	// Infer type from content if not explicitly set
 	f.inferType()
 
+	// Fold depends_on/needs aliases into each type's canonical field
+	f.resolveAliases()
+
+	if err := f.checkSizeLimits(opts); err != nil {
+		return err
+	}
+
+	// Expand group references in needs into member IDs
+	if err := f.expandGroups(); err != nil {
+		return err
+	}
+
+	// Add needs edges implied by produces/consumes
+	if err := f.ResolveArtifacts(); err != nil {
+		return err
+	}
+
+	if err := f.checkNeedsLimit(opts); err != nil {
+		return err
+	}
+
 	if err := f.Validate(); err != nil {
-		return nil, err
+		return err
 	}
 
-	return &f, nil
+	if err := f.checkDepthLimit(opts); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // inferType sets the ritual type based on content when not explicitly set.
@@ -62,50 +118,114 @@ func (f *Ritual) Validate() error {
 		return fmt.Errorf("invalid ritual type %q (must be raid, workflow, expansion, or aspect)", f.Type)
 	}
 
+	if err := f.validateIDs(); err != nil {
+		return err
+	}
+
 	// Type-specific validation
+	var err error
 	switch f.Type {
 	case TypeRaid:
-		return f.validateRaid()
+		err = f.validateRaid()
 	case TypeWorkflow:
-		return f.validateWorkflow()
+		err = f.validateWorkflow()
 	case TypeExpansion:
-		return f.validateExpansion()
+		err = f.validateExpansion()
 	case TypeAspect:
-		return f.validateAspect()
+		err = f.validateAspect()
+	}
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return f.validateFinal()
 }
 
 func (f *Ritual) validateRaid() error {
 	if len(f.Legs) == 0 {
-		return fmt.Errorf("raid ritual requires at least one leg")
+		return fmt.Errorf("raid ritual requires at least one leg (add [[legs]] entries with id, title, and focus)")
 	}
 
-	// Check leg IDs are unique
+	// Check leg IDs are unique and required fields are present
 	seen := make(map[string]bool)
-	for _, leg := range f.Legs {
+	for i, leg := range f.Legs {
 		if leg.ID == "" {
-			return fmt.Errorf("leg missing required id field")
+			return fmt.Errorf("leg %d missing required id field", i+1)
 		}
 		if seen[leg.ID] {
 			return fmt.Errorf("duplicate leg id: %s", leg.ID)
 		}
 		seen[leg.ID] = true
+		if leg.Title == "" {
+			return fmt.Errorf("leg %q missing required title field", leg.ID)
+		}
 	}
 
-	// Validate synthesis depends_on references valid legs
+	// Validate synthesis is titled and depends_on references valid legs
 	if f.Synthesis != nil {
+		if f.Synthesis.Title == "" {
+			return fmt.Errorf("synthesis missing required title field")
+		}
 		for _, dep := range f.Synthesis.DependsOn {
 			if !seen[dep] {
 				return fmt.Errorf("synthesis depends_on references unknown leg: %s", dep)
 			}
 		}
+		if err := f.Synthesis.validateMode(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// Warnings returns non-fatal problems that Validate accepts, such as raid
+// legs without a focus, or legs or aspects that share a focus (usually a
+// copy-paste mistake, though occasionally intentional). Callers that load
+// rituals for display or dispatch can surface these to authors.
+func (f *Ritual) Warnings() []string {
+	var warnings []string
+	switch f.Type {
+	case TypeRaid:
+		ids := make([]string, len(f.Legs))
+		focuses := make([]string, len(f.Legs))
+		for i, leg := range f.Legs {
+			if leg.Focus == "" {
+				warnings = append(warnings, fmt.Sprintf("leg %q has no focus", leg.ID))
+			}
+			ids[i], focuses[i] = leg.ID, leg.Focus
+		}
+		warnings = append(warnings, duplicateFocusWarnings("legs", ids, focuses)...)
+	case TypeAspect:
+		ids := make([]string, len(f.Aspects))
+		focuses := make([]string, len(f.Aspects))
+		for i, aspect := range f.Aspects {
+			ids[i], focuses[i] = aspect.ID, aspect.Focus
+		}
+		warnings = append(warnings, duplicateFocusWarnings("aspects", ids, focuses)...)
+	}
+	return warnings
+}
+
+// duplicateFocusWarnings reports each item whose focus matches an earlier
+// item's, ignoring case and spacing. Empty focuses are not compared.
+func duplicateFocusWarnings(kind string, ids, focuses []string) []string {
+	var warnings []string
+	first := make(map[string]string)
+	for i, focus := range focuses {
+		key := strings.ToLower(strings.Join(strings.Fields(focus), " "))
+		if key == "" {
+			continue
+		}
+		if prev, ok := first[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s %q and %q have the same focus", kind, prev, ids[i]))
+			continue
+		}
+		first[key] = ids[i]
+	}
+	return warnings
+}
+
 func (f *Ritual) validateWorkflow() error {
 	if len(f.Steps) == 0 {
 		return fmt.Errorf("workflow ritual requires at least one step")
@@ -121,6 +241,20 @@ func (f *Ritual) validateWorkflow() error {
 			return fmt.Errorf("duplicate step id: %s", step.ID)
 		}
 		seen[step.ID] = true
+		for name := range step.Env {
+			if !envVarName.MatchString(name) {
+				return fmt.Errorf("step %q env: invalid variable name %q", step.ID, name)
+			}
+		}
+		if err := validateConcurrencyGroup(step); err != nil {
+			return err
+		}
+		if err := validateStepCwd(step.Cwd); err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		if err := validateStepShell(step.Shell); err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
 	}
 
 	// Validate step needs references
@@ -174,7 +308,7 @@ func (f *Ritual) validateAspect() error {
 		return fmt.Errorf("aspect ritual requires at least one aspect")
 	}
 
-	// Check aspect IDs are unique
+	// Check aspect IDs are unique and required fields are present
 	seen := make(map[string]bool)
 	for _, aspect := range f.Aspects {
 		if aspect.ID == "" {
@@ -184,6 +318,24 @@ func (f *Ritual) validateAspect() error {
 			return fmt.Errorf("duplicate aspect id: %s", aspect.ID)
 		}
 		seen[aspect.ID] = true
+		if aspect.Title == "" {
+			return fmt.Errorf("aspect %q missing required title field", aspect.ID)
+		}
+		if aspect.Focus == "" {
+			return fmt.Errorf("aspect %q missing required focus field", aspect.ID)
+		}
+	}
+
+	// Validate synthesis depends_on references valid aspects
+	if f.Synthesis != nil {
+		for _, dep := range f.Synthesis.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("synthesis depends_on references unknown aspect: %s", dep)
+			}
+		}
+		if err := f.Synthesis.validateMode(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -234,6 +386,10 @@ func (f *Ritual) checkCycles() error {
 // TopologicalSort returns steps in dependency order (dependencies before dependents).
 // Only applicable to workflow and expansion rituals.
 // Returns an error if there are cycles.
+//
+// The order is deterministic: whenever several steps are ready at once, the
+// one declared first comes first. A ritual with no dependencies is returned
+// in declaration order.
 func (f *Ritual) TopologicalSort() ([]string, error) {
 	var items []string
 	var deps map[string][]string
@@ -271,10 +427,12 @@ func (f *Ritual) TopologicalSort() ([]string, error) {
 		return nil, fmt.Errorf("unsupported ritual type for topological sort")
 	}
 
-	// Kahn's algorithm
+	// Kahn's algorithm, taking ready items in declaration order
 	inDegree := make(map[string]int)
-	for _, id := range items {
+	index := make(map[string]int, len(items))
+	for i, id := range items {
 		inDegree[id] = 0
+		index[id] = i
 	}
 	for _, id := range items {
 		for _, dep := range deps[id] {
@@ -306,11 +464,13 @@ func (f *Ritual) TopologicalSort() ([]string, error) {
 		queue = queue[1:]
 		result = append(result, id)
 
-		// Reduce in-degree of dependents
+		// Reduce in-degree of dependents, keeping the queue sorted by
+		// declaration index so ties break the same way on every run
 		for _, dependent := range dependents[id] {
 			inDegree[dependent]--
 			if inDegree[dependent] == 0 {
-				queue = append(queue, dependent)
+				i := sort.Search(len(queue), func(i int) bool { return index[queue[i]] > index[dependent] })
+				queue = slices.Insert(queue, i, dependent)
 			}
 		}
 	}
@@ -379,6 +539,14 @@ func (f *Ritual) ReadySteps(completed map[string]bool) []string {
 	return ready
 }
 
+// InitialReady returns the items that can run first: workflow steps and
+// expansion templates with no needs, or every leg/aspect of a raid or aspect
+// ritual (synthesis never is; it waits for its legs). It is ReadySteps with
+// nothing completed.
+func (f *Ritual) InitialReady() []string {
+	return f.ReadySteps(nil)
+}
+
 // GetStep returns a step by ID, or nil if not found.
 func (f *Ritual) GetStep(id string) *Step {
 	for i := range f.Steps {
@@ -418,3 +586,9 @@ func (f *Ritual) GetAspect(id string) *Aspect {
 	}
 	return nil
 }
+
+// AspectByID returns an aspect by ID and whether it was found.
+func (f *Ritual) AspectByID(id string) (*Aspect, bool) {
+	aspect := f.GetAspect(id)
+	return aspect, aspect != nil
+}
@@ -1,7 +1,14 @@
 package ritual
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestParse_Workflow(t *testing.T) {
@@ -100,6 +107,150 @@ depends_on = ["leg1", "leg2"]
 	}
 }
 
+func TestValidate_RaidLegs(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "empty legs",
+			data: `
+ritual = "empty"
+type = "raid"
+`,
+			wantErr: "requires at least one leg",
+		},
+		{
+			name: "leg missing id",
+			data: `
+ritual = "no-id"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+focus = "Code"
+
+[[legs]]
+title = "Dependency Audit"
+focus = "Packages"
+`,
+			wantErr: "leg 2 missing required id field",
+		},
+		{
+			name: "leg missing title",
+			data: `
+ritual = "no-title"
+type = "raid"
+
+[[legs]]
+id = "sast"
+focus = "Code"
+`,
+			wantErr: `leg "sast" missing required title field`,
+		},
+		{
+			name: "synthesis missing title",
+			data: `
+ritual = "no-synth-title"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+
+[synthesis]
+depends_on = ["sast"]
+`,
+			wantErr: "synthesis missing required title field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWarnings_RaidLegWithoutFocus(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "scan"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+focus = "Code"
+
+[[legs]]
+id = "deps"
+title = "Dependency Audit"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	warnings := f.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"deps"`) {
+		t.Errorf("Warnings() = %v, want one warning for leg deps", warnings)
+	}
+}
+
+func TestWarnings_DuplicateFocus(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "security"
+title = "Security"
+focus = "Find vulnerabilities"
+
+[[legs]]
+id = "perf"
+title = "Performance"
+focus = "Hot paths"
+
+[[legs]]
+id = "security-2"
+title = "Security (copy)"
+focus = "find  Vulnerabilities"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	warnings := f.Warnings()
+	want := `legs "security" and "security-2" have the same focus`
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("Warnings() = %v, want [%s]", warnings, want)
+	}
+
+	aspects, err := Parse([]byte(`
+ritual = "analysis"
+type = "aspect"
+
+[[aspects]]
+id = "a"
+title = "A"
+focus = "Same"
+
+[[aspects]]
+id = "b"
+title = "B"
+focus = "Same"
+`))
+	if err != nil {
+		t.Fatalf("Parse aspect failed: %v", err)
+	}
+	if warnings := aspects.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], `"a" and "b"`) {
+		t.Errorf("aspect Warnings() = %v, want one duplicate focus warning", warnings)
+	}
+}
+
 func TestParse_Expansion(t *testing.T) {
 	data := []byte(`
 description = "Test expansion"
@@ -135,6 +286,100 @@ needs = ["{target}.draft"]
 	}
 }
 
+func TestParse_Aspect(t *testing.T) {
+	data := []byte(`
+ritual = "test-aspect"
+type = "aspect"
+version = 1
+
+[[aspects]]
+id = "security"
+title = "Security Review"
+focus = "OWASP Top 10"
+
+[[aspects]]
+id = "performance"
+title = "Performance Review"
+focus = "Complexity and bottlenecks"
+
+[synthesis]
+title = "Combined Review"
+depends_on = ["security", "performance"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if f.Type != TypeAspect {
+		t.Errorf("Type = %q, want %q", f.Type, TypeAspect)
+	}
+	if len(f.Aspects) != 2 {
+		t.Errorf("len(Aspects) = %d, want 2", len(f.Aspects))
+	}
+
+	aspect, ok := f.AspectByID("performance")
+	if !ok {
+		t.Fatal("AspectByID(performance) not found")
+	}
+	if aspect.Focus != "Complexity and bottlenecks" {
+		t.Errorf("Focus = %q, want %q", aspect.Focus, "Complexity and bottlenecks")
+	}
+	if _, ok := f.AspectByID("missing"); ok {
+		t.Error("AspectByID(missing) should not be found")
+	}
+
+	if deps := f.GetDependencies("synthesis"); len(deps) != 2 {
+		t.Errorf("GetDependencies(synthesis) = %v, want 2 aspects", deps)
+	}
+}
+
+func TestValidate_AspectErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"duplicate id", `
+[[aspects]]
+id = "a"
+title = "A"
+focus = "x"
+[[aspects]]
+id = "a"
+title = "A again"
+focus = "y"
+`},
+		{"missing title", `
+[[aspects]]
+id = "a"
+focus = "x"
+`},
+		{"missing focus", `
+[[aspects]]
+id = "a"
+title = "A"
+`},
+		{"synthesis unknown aspect", `
+[[aspects]]
+id = "a"
+title = "A"
+focus = "x"
+[synthesis]
+depends_on = ["b"]
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte("ritual = \"test\"\ntype = \"aspect\"\nversion = 1\n" + tt.body)
+			if _, err := Parse(data); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
 func TestValidate_MissingName(t *testing.T) {
 	data := []byte(`
 type = "workflow"
@@ -222,6 +467,231 @@ needs = ["step1"]
 	}
 }
 
+// chainRitual builds a workflow ritual of n steps, each needing the previous one.
+func chainRitual(n int) []byte {
+	var b strings.Builder
+	b.WriteString("ritual = \"chain\"\ntype = \"workflow\"\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "[[steps]]\nid = \"s%d\"\ntitle = \"Step %d\"\n", i, i)
+		if i > 0 {
+			fmt.Fprintf(&b, "needs = [\"s%d\"]\n", i-1)
+		}
+	}
+	return []byte(b.String())
+}
+
+func TestParseWithOptions_Limits(t *testing.T) {
+	data := chainRitual(10)
+
+	if _, err := Parse(data); err != nil {
+		t.Fatalf("Parse with default limits: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts ParseOptions
+		want string
+	}{
+		{"max steps", ParseOptions{MaxSteps: 5}, "10 steps, max 5"},
+		{"max depth", ParseOptions{MaxDepth: 9}, "10 steps deep, max 9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithOptions(data, tt.opts)
+			if !errors.Is(err, ErrLimitExceeded) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseWithOptions error = %v, want ErrLimitExceeded with %q", err, tt.want)
+			}
+		})
+	}
+
+	// Zero options mean no limits
+	if _, err := ParseWithOptions(chainRitual(DefaultParseOptions().MaxSteps+1), ParseOptions{}); err != nil {
+		t.Errorf("ParseWithOptions with no limits: %v", err)
+	}
+	if _, err := Parse(chainRitual(DefaultParseOptions().MaxSteps + 1)); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Parse over default step limit: error = %v, want ErrLimitExceeded", err)
+	}
+}
+
+func TestParseWithOptions_MaxNeedsCountsGroups(t *testing.T) {
+	data := []byte(`
+ritual = "test"
+type = "workflow"
+[[groups]]
+id = "checks"
+members = ["a", "b", "c"]
+[[steps]]
+id = "a"
+title = "A"
+[[steps]]
+id = "b"
+title = "B"
+[[steps]]
+id = "c"
+title = "C"
+[[steps]]
+id = "ship"
+title = "Ship"
+needs = ["group:checks"]
+`)
+
+	if _, err := ParseWithOptions(data, ParseOptions{MaxNeeds: 3}); err != nil {
+		t.Errorf("ParseWithOptions at needs limit: %v", err)
+	}
+	_, err := ParseWithOptions(data, ParseOptions{MaxNeeds: 2})
+	if !errors.Is(err, ErrLimitExceeded) || !strings.Contains(err.Error(), `"ship" has 3 needs`) {
+		t.Errorf("ParseWithOptions over needs limit: error = %v, want ErrLimitExceeded for ship", err)
+	}
+}
+
+func TestParse_Groups(t *testing.T) {
+	data := []byte(`
+ritual = "release"
+type = "workflow"
+version = 1
+
+[[groups]]
+id = "build"
+members = ["build-linux", "build-darwin"]
+
+[[groups]]
+id = "all"
+members = ["group:build", "test"]
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "build-linux"
+title = "Build Linux"
+needs = ["test"]
+
+[[steps]]
+id = "build-darwin"
+title = "Build Darwin"
+needs = ["test"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["group:build", "build-linux"]
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["group:all"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	publish := f.GetStep("publish")
+	if got := strings.Join(publish.Needs, ","); got != "build-linux,build-darwin" {
+		t.Errorf("publish needs = %q, want %q", got, "build-linux,build-darwin")
+	}
+	announce := f.GetStep("announce")
+	if got := strings.Join(announce.Needs, ","); got != "build-linux,build-darwin,test" {
+		t.Errorf("announce needs = %q, want %q", got, "build-linux,build-darwin,test")
+	}
+
+	ready := f.ReadySteps(map[string]bool{"test": true, "build-linux": true})
+	if len(ready) != 1 || ready[0] != "build-darwin" {
+		t.Errorf("ReadySteps = %v, want [build-darwin]", ready)
+	}
+
+	if got := f.GroupMembers("all"); len(got) != 3 {
+		t.Errorf("GroupMembers(all) = %v, want 3 members", got)
+	}
+}
+
+func TestValidate_GroupErrors(t *testing.T) {
+	header := `
+ritual = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "a"
+title = "A"
+`
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name: "unknown member",
+			body: `
+[[groups]]
+id = "g"
+members = ["missing"]
+`,
+			wantErr: "references unknown step: missing",
+		},
+		{
+			name: "unknown group in needs",
+			body: `
+[[steps]]
+id = "b"
+title = "B"
+needs = ["group:nope"]
+`,
+			wantErr: "needs unknown group: nope",
+		},
+		{
+			name: "duplicate group",
+			body: `
+[[groups]]
+id = "g"
+members = ["a"]
+[[groups]]
+id = "g"
+members = ["a"]
+`,
+			wantErr: "duplicate group id: g",
+		},
+		{
+			name: "group cycle",
+			body: `
+[[groups]]
+id = "x"
+members = ["group:y"]
+[[groups]]
+id = "y"
+members = ["group:x"]
+`,
+			wantErr: "cycle detected involving group",
+		},
+		{
+			name: "step needs its own group",
+			body: `
+[[groups]]
+id = "g"
+members = ["a", "b"]
+[[steps]]
+id = "b"
+title = "B"
+needs = ["group:g"]
+`,
+			wantErr: "cycle detected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(header + tt.body))
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestTopologicalSort(t *testing.T) {
 	data := []byte(`
 ritual = "test"
@@ -301,6 +771,10 @@ needs = ["step2", "step3"]
 		t.Errorf("ReadySteps({}) = %v, want [step1]", ready)
 	}
 
+	if initial := f.InitialReady(); len(initial) != 1 || initial[0] != "step1" {
+		t.Errorf("InitialReady() = %v, want [step1]", initial)
+	}
+
 	// After completing step1, step2 and step3 are ready
 	ready = f.ReadySteps(map[string]bool{"step1": true})
 	if len(ready) != 2 {
@@ -347,9 +821,2486 @@ title = "Leg 3"
 		t.Errorf("ReadySteps({}) = %v, want 3 legs", ready)
 	}
 
+	if initial := f.InitialReady(); len(initial) != 3 {
+		t.Errorf("InitialReady() = %v, want 3 legs", initial)
+	}
+
 	// After completing leg1, leg2 and leg3 still ready
 	ready = f.ReadySteps(map[string]bool{"leg1": true})
 	if len(ready) != 2 {
 		t.Errorf("ReadySteps({leg1}) = %v, want 2 legs", ready)
 	}
 }
+
+func TestExecutor(t *testing.T) {
+	data := []byte(`
+ritual = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "step1"
+title = "Step 1"
+[[steps]]
+id = "step2"
+title = "Step 2"
+needs = ["step1"]
+[[steps]]
+id = "step3"
+title = "Step 3"
+needs = ["step1"]
+[[steps]]
+id = "step4"
+title = "Step 4"
+needs = ["step2"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	t.Run("all steps complete in dependency order", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []string
+		results, err := NewExecutor(f).Execute(context.Background(), func(ctx context.Context, id string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, id)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		for _, id := range f.GetAllIDs() {
+			if results[id].Status != StepCompleted {
+				t.Errorf("results[%s] = %+v, want completed", id, results[id])
+			}
+		}
+		if len(order) != 4 || order[0] != "step1" || order[3] != "step4" {
+			t.Errorf("run order = %v, want step1 first and step4 last", order)
+		}
+	})
+
+	t.Run("stop policy skips later waves", func(t *testing.T) {
+		results, err := NewExecutor(f).Execute(context.Background(), func(ctx context.Context, id string) error {
+			if id == "step3" {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "step3") {
+			t.Fatalf("Execute error = %v, want step3 failure", err)
+		}
+		if results["step2"].Status != StepCompleted {
+			t.Errorf("step2 = %v, want completed (same wave as failure)", results["step2"].Status)
+		}
+		if results["step3"].Status != StepFailed {
+			t.Errorf("step3 = %v, want failed", results["step3"].Status)
+		}
+		if results["step4"].Status != StepSkipped {
+			t.Errorf("step4 = %v, want skipped", results["step4"].Status)
+		}
+	})
+
+	t.Run("continue policy runs independent steps", func(t *testing.T) {
+		e := NewExecutor(f)
+		e.OnFailure = FailContinue
+		results, err := e.Execute(context.Background(), func(ctx context.Context, id string) error {
+			if id == "step3" {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Execute error = nil, want step3 failure")
+		}
+		if results["step4"].Status != StepCompleted {
+			t.Errorf("step4 = %v, want completed (doesn't need step3)", results["step4"].Status)
+		}
+
+		results, _ = e.Execute(context.Background(), func(ctx context.Context, id string) error {
+			if id == "step2" {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if results["step4"].Status != StepSkipped {
+			t.Errorf("step4 = %v, want skipped (needs failed step2)", results["step4"].Status)
+		}
+	})
+
+	t.Run("OnWave reports each wave", func(t *testing.T) {
+		var waves []string
+		e := NewExecutor(f)
+		e.OnWave = func(wave []string) {
+			waves = append(waves, strings.Join(wave, ","))
+		}
+		if _, err := e.Execute(context.Background(), func(ctx context.Context, id string) error { return nil }); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if got, want := strings.Join(waves, " | "), "step1 | step2,step3 | step4"; got != want {
+			t.Errorf("waves = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		e := NewExecutor(f)
+		e.OnFailure = "retry"
+		if _, err := e.Execute(context.Background(), func(ctx context.Context, id string) error { return nil }); err == nil {
+			t.Error("Execute with invalid policy: error = nil, want error")
+		}
+	})
+}
+
+func TestExecutor_ParallelLimit(t *testing.T) {
+	data := []byte(`
+ritual = "test"
+type = "raid"
+version = 1
+[[legs]]
+id = "leg1"
+title = "Leg 1"
+[[legs]]
+id = "leg2"
+title = "Leg 2"
+[[legs]]
+id = "leg3"
+title = "Leg 3"
+[[legs]]
+id = "leg4"
+title = "Leg 4"
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var running, peak int32
+	e := NewExecutor(f)
+	e.ParallelLimit = 2
+	results, err := e.Execute(context.Background(), func(ctx context.Context, id string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(results) != 4 {
+		t.Errorf("len(results) = %d, want 4", len(results))
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+}
+
+func TestExecutor_OptionalStep(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+optional = true
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["lint"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !f.IsOptional("lint") || f.IsOptional("build") {
+		t.Fatalf("IsOptional(lint, build) = %v, %v; want true, false", f.IsOptional("lint"), f.IsOptional("build"))
+	}
+
+	results, err := NewExecutor(f).Execute(context.Background(), func(ctx context.Context, id string) error {
+		if id == "lint" {
+			return errors.New("lint warnings")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Execute error = %v, want nil (only optional step failed)", err)
+	}
+	if results["lint"].Status != StepFailed {
+		t.Errorf("lint = %+v, want failed", results["lint"])
+	}
+	if results["build"].Status != StepCompleted {
+		t.Errorf("build = %+v, want completed despite optional failure", results["build"])
+	}
+
+	if _, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+optional = "yes"
+`)); err == nil {
+		t.Error("Parse accepted non-boolean optional")
+	}
+}
+
+func TestSoftNeeds(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[groups]]
+id = "checks"
+members = ["lint", "vet"]
+
+[[steps]]
+id = "compile"
+title = "Compile"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "vet"
+title = "Vet"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["compile", {id = "compile-docs", type = "soft"}]
+
+[[steps]]
+id = "compile-docs"
+title = "Docs"
+needs = ["compile"]
+
+[[steps]]
+id = "package"
+title = "Package"
+needs = [{id = "test", type = "hard"}, {id = "group:checks", type = "soft"}, "vet"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	pkg := f.GetStep("package")
+	if got := strings.Join(pkg.Needs, ","); got != "test,lint,vet" {
+		t.Errorf("package needs = %q, want test,lint,vet", got)
+	}
+	if got := strings.Join(pkg.SoftNeeds, ","); got != "lint" {
+		t.Errorf("package soft needs = %q, want lint (vet is also a hard need)", got)
+	}
+	if pkg.NeedType("lint") != NeedSoft || pkg.NeedType("test") != NeedHard {
+		t.Errorf("NeedType(lint, test) = %s, %s; want soft, hard", pkg.NeedType("lint"), pkg.NeedType("test"))
+	}
+
+	// Soft edges still order the plan.
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	pos := make(map[string]int)
+	for i, id := range order {
+		pos[id] = i
+	}
+	if pos["compile-docs"] > pos["test"] {
+		t.Errorf("TopologicalSort = %v, want compile-docs before test", order)
+	}
+
+	// WriteTOML keeps the soft marking.
+	var out strings.Builder
+	if err := f.WriteTOML(&out); err != nil {
+		t.Fatalf("WriteTOML failed: %v", err)
+	}
+	g, err := Parse([]byte(out.String()))
+	if err != nil {
+		t.Fatalf("re-Parse failed: %v\n%s", err, out.String())
+	}
+	if got := strings.Join(g.GetStep("test").SoftNeeds, ","); got != "compile-docs" {
+		t.Errorf("round-tripped soft needs = %q, want compile-docs", got)
+	}
+
+	for name, needs := range map[string]string{
+		"invalid type":   `[{id = "a", type = "weak"}]`,
+		"missing id":     `[{type = "soft"}]`,
+		"unknown key":    `[{id = "a", kind = "soft"}]`,
+		"hard and soft":  `["a", {id = "a", type = "soft"}]`,
+		"non-string id":  `[{id = 1}]`,
+		"non-array form": `"a"`,
+	} {
+		_, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "a"
+title = "A"
+
+[[steps]]
+id = "b"
+title = "B"
+needs = ` + needs + `
+`))
+		if err == nil {
+			t.Errorf("%s: Parse accepted needs = %s", name, needs)
+		}
+	}
+}
+
+func TestExecutor_SoftNeed(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "migrate"
+title = "Migrate"
+
+[[steps]]
+id = "seed"
+title = "Seed"
+needs = ["migrate"]
+
+[[steps]]
+id = "report"
+title = "Report"
+needs = [{id = "seed", type = "soft"}]
+
+[[steps]]
+id = "deploy"
+title = "Deploy"
+needs = ["seed"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var ran []string
+	e := &Executor{Ritual: f, OnFailure: FailContinue}
+	results, err := e.Execute(context.Background(), func(ctx context.Context, id string) error {
+		mu.Lock()
+		ran = append(ran, id)
+		mu.Unlock()
+		if id == "migrate" {
+			return errors.New("migration failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Execute error = nil, want migrate failure")
+	}
+
+	want := map[string]StepStatus{
+		"migrate": StepFailed,
+		"seed":    StepSkipped,   // hard need failed
+		"deploy":  StepSkipped,   // hard need skipped
+		"report":  StepCompleted, // soft need settled without running
+	}
+	for id, status := range want {
+		if results[id].Status != status {
+			t.Errorf("%s = %s, want %s", id, results[id].Status, status)
+		}
+	}
+	if got := strings.Join(ran, ","); got != "migrate,report" {
+		t.Errorf("ran = %q, want migrate,report", got)
+	}
+}
+
+func TestExecutor_ContextCancelled(t *testing.T) {
+	data := []byte(`
+ritual = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "step1"
+title = "Step 1"
+[[steps]]
+id = "step2"
+title = "Step 2"
+needs = ["step1"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := NewExecutor(f).Execute(ctx, func(ctx context.Context, id string) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want context.Canceled", err)
+	}
+	if results["step1"].Status != StepCompleted || results["step2"].Status != StepSkipped {
+		t.Errorf("results = %+v, want step1 completed and step2 skipped", results)
+	}
+}
+
+func TestNormalize_PreservesSemantics(t *testing.T) {
+	data := []byte(`
+ritual = " release "
+type = "workflow"
+version = 1
+
+[vars.version]
+description = "Version to release"
+required = true
+
+[[steps]]
+id = "test"
+title = "  Test  "
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["test", "lint", "test"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	before, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	readyBefore := f.ReadySteps(map[string]bool{"test": true})
+
+	f.Normalize()
+
+	if f.Name != "release" {
+		t.Errorf("Name = %q, want %q", f.Name, "release")
+	}
+	if got := f.GetStep("test").Title; got != "Test" {
+		t.Errorf("title = %q, want %q", got, "Test")
+	}
+	if got := strings.Join(f.GetStep("build").Needs, ","); got != "lint,test" {
+		t.Errorf("build needs = %q, want %q", got, "lint,test")
+	}
+
+	after, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort after Normalize failed: %v", err)
+	}
+	if strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Errorf("topological order changed: %v -> %v", before, after)
+	}
+	readyAfter := f.ReadySteps(map[string]bool{"test": true})
+	if strings.Join(readyBefore, ",") != strings.Join(readyAfter, ",") {
+		t.Errorf("ready steps changed: %v -> %v", readyBefore, readyAfter)
+	}
+}
+
+func TestWriteTOML_RoundTrip(t *testing.T) {
+	data := []byte(`
+ritual = "release"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["test", "lint"]
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var first strings.Builder
+	if err := f.WriteTOML(&first); err != nil {
+		t.Fatalf("WriteTOML failed: %v", err)
+	}
+	if got := strings.Join(f.GetStep("build").Needs, ","); got != "test,lint" {
+		t.Errorf("WriteTOML modified receiver: needs = %q", got)
+	}
+
+	f2, err := Parse([]byte(first.String()))
+	if err != nil {
+		t.Fatalf("Parse of WriteTOML output failed: %v\n%s", err, first.String())
+	}
+	var second strings.Builder
+	if err := f2.WriteTOML(&second); err != nil {
+		t.Fatalf("WriteTOML failed: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("WriteTOML not stable:\n%s\n---\n%s", first.String(), second.String())
+	}
+	if !strings.HasPrefix(first.String(), `ritual = "release"`) {
+		t.Errorf("expected ritual key first, got:\n%s", first.String())
+	}
+}
+
+func TestDependencyAliases(t *testing.T) {
+	plan := func(t *testing.T, data string) (*Ritual, []string) {
+		t.Helper()
+		f, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		order, err := f.TopologicalSort()
+		if err != nil {
+			t.Fatalf("TopologicalSort failed: %v", err)
+		}
+		return f, order
+	}
+
+	t.Run("workflow depends_on", func(t *testing.T) {
+		const steps = `
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "build"
+title = "Build"
+%s = ["test"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+%s = ["build"]
+`
+		canonical, want := plan(t, fmt.Sprintf(steps, "needs", "needs"))
+		aliased, got := plan(t, fmt.Sprintf(steps, "depends_on", "needs"))
+
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("order = %v, want %v", got, want)
+		}
+		for _, id := range []string{"build", "publish"} {
+			if a, c := aliased.GetDependencies(id), canonical.GetDependencies(id); strings.Join(a, ",") != strings.Join(c, ",") {
+				t.Errorf("GetDependencies(%q) = %v, want %v", id, a, c)
+			}
+		}
+		if deps := aliased.GetStep("build").DependsOn; deps != nil {
+			t.Errorf("alias not cleared after parsing: %v", deps)
+		}
+		if ready := aliased.ReadySteps(map[string]bool{"test": true}); strings.Join(ready, ",") != "build" {
+			t.Errorf("ReadySteps = %v, want [build]", ready)
+		}
+	})
+
+	t.Run("both spellings merge", func(t *testing.T) {
+		f, _ := plan(t, `
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["test"]
+depends_on = ["lint", "test"]
+`)
+		if got := strings.Join(f.GetStep("build").Needs, ","); got != "test,lint" {
+			t.Errorf("needs = %q, want test,lint", got)
+		}
+	})
+
+	t.Run("synthesis needs", func(t *testing.T) {
+		const raid = `
+ritual = "scan"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+
+[[legs]]
+id = "deps"
+title = "Dependency Audit"
+
+[synthesis]
+title = "Report"
+%s = ["sast", "deps"]
+`
+		canonical, want := plan(t, fmt.Sprintf(raid, "depends_on"))
+		aliased, got := plan(t, fmt.Sprintf(raid, "needs"))
+
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("order = %v, want %v", got, want)
+		}
+		if a, c := aliased.Synthesis.DependsOn, canonical.Synthesis.DependsOn; strings.Join(a, ",") != strings.Join(c, ",") {
+			t.Errorf("synthesis depends_on = %v, want %v", a, c)
+		}
+	})
+
+	t.Run("alias references are validated", func(t *testing.T) {
+		_, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build"
+depends_on = ["missing"]
+`))
+		if err == nil || !strings.Contains(err.Error(), "missing") {
+			t.Errorf("Parse error = %v, want unknown step error", err)
+		}
+	})
+
+	t.Run("WriteTOML emits canonical field", func(t *testing.T) {
+		f, _ := plan(t, `
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "build"
+title = "Build"
+depends_on = ["test"]
+`)
+		var out strings.Builder
+		if err := f.WriteTOML(&out); err != nil {
+			t.Fatalf("WriteTOML failed: %v", err)
+		}
+		if strings.Contains(out.String(), "depends_on") || !strings.Contains(out.String(), "needs") {
+			t.Errorf("WriteTOML output not canonical:\n%s", out.String())
+		}
+	})
+}
+
+func TestEstimateParallelism(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "unit"
+title = "Unit Tests"
+
+[[steps]]
+id = "vet"
+title = "Vet"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["lint", "unit", "vet"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	waves, err := f.Waves()
+	if err != nil {
+		t.Fatalf("Waves failed: %v", err)
+	}
+	var got []string
+	for _, w := range waves {
+		got = append(got, strings.Join(w, ","))
+	}
+	if want := "lint,unit,vet|build|publish"; strings.Join(got, "|") != want {
+		t.Errorf("Waves = %q, want %q", strings.Join(got, "|"), want)
+	}
+
+	index, err := f.WaveIndex()
+	if err != nil {
+		t.Fatalf("WaveIndex failed: %v", err)
+	}
+	wantIndex := map[string]int{"lint": 0, "unit": 0, "vet": 0, "build": 1, "publish": 2}
+	if fmt.Sprint(index) != fmt.Sprint(wantIndex) {
+		t.Errorf("WaveIndex = %v, want %v", index, wantIndex)
+	}
+
+	tests := []struct {
+		limit int
+		want  ParallelismEstimate
+	}{
+		{0, ParallelismEstimate{TotalSteps: 5, MaxWidth: 3, Waves: 3, Rounds: 3}},
+		{2, ParallelismEstimate{TotalSteps: 5, MaxWidth: 3, Waves: 3, Rounds: 4}},
+		{1, ParallelismEstimate{TotalSteps: 5, MaxWidth: 3, Waves: 3, Rounds: 5}},
+	}
+	for _, tt := range tests {
+		est, err := f.EstimateParallelism(tt.limit)
+		if err != nil {
+			t.Fatalf("EstimateParallelism(%d) failed: %v", tt.limit, err)
+		}
+		if est != tt.want {
+			t.Errorf("EstimateParallelism(%d) = %+v, want %+v", tt.limit, est, tt.want)
+		}
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	t.Run("workflow", func(t *testing.T) {
+		f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "test"
+title = "Run \"all\" tests"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+optional = true
+
+[[steps]]
+id = "end"
+needs = ["test", "lint"]
+`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		want := `graph TD
+    test["Run #quot;all#quot; tests"]
+    lint["Lint"]
+    end_["end"]
+    test --> end_
+    lint --> end_
+    classDef optional stroke-dasharray:5 5
+    class lint optional
+`
+		if got := f.RenderMermaid(); got != want {
+			t.Errorf("RenderMermaid() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("aspect with synthesis", func(t *testing.T) {
+		f, err := Parse([]byte(`
+ritual = "review"
+type = "aspect"
+
+[[aspects]]
+id = "sec"
+title = "Security"
+focus = "vulns"
+
+[[aspects]]
+id = "perf"
+title = "Performance"
+focus = "hot paths"
+
+[synthesis]
+title = "Combined Review"
+`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		want := `graph TD
+    sec["Security"]
+    perf["Performance"]
+    synthesis[["Combined Review"]]
+    sec --> synthesis
+    perf --> synthesis
+    classDef aspect fill:#e8f0fe,stroke:#4a6fa5
+    class sec,perf aspect
+    classDef synthesis fill:#fff4d6,stroke:#b8860b,stroke-width:2px
+    class synthesis synthesis
+`
+		if got := f.RenderMermaid(); got != want {
+			t.Errorf("RenderMermaid() =\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestResolveArtifacts(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "package"
+title = "Package"
+consumes = ["binary", "docs"]
+
+[[steps]]
+id = "build"
+title = "Build"
+produces = ["binary"]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+produces = ["docs"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := strings.Join(f.GetStep("package").Needs, ","); got != "build,docs" {
+		t.Errorf("package needs = %q, want build,docs", got)
+	}
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	if order[len(order)-1] != "package" {
+		t.Errorf("order = %v, want package last", order)
+	}
+	if ready := f.ReadySteps(map[string]bool{"build": true}); strings.Join(ready, ",") != "docs" {
+		t.Errorf("ReadySteps = %v, want [docs]", ready)
+	}
+
+	// Resolving again adds nothing new
+	if err := f.ResolveArtifacts(); err != nil {
+		t.Fatalf("ResolveArtifacts failed: %v", err)
+	}
+	if got := strings.Join(f.GetStep("package").Needs, ","); got != "build,docs" {
+		t.Errorf("package needs after re-resolve = %q, want build,docs", got)
+	}
+}
+
+func TestResolveArtifacts_NoProducer(t *testing.T) {
+	_, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "package"
+title = "Package"
+consumes = ["binary"]
+`))
+	if err == nil || !strings.Contains(err.Error(), "no producer for artifact binary") {
+		t.Errorf("Parse error = %v, want no producer for artifact binary", err)
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "merged"
+type = "workflow"
+
+[[steps]]
+id = "docs"
+title = "Build Docs"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "publish-docs"
+title = "Publish Docs"
+needs = ["docs"]
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["test", "lint"]
+
+[[steps]]
+id = "cleanup"
+title = "Cleanup"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	got := fmt.Sprint(f.ConnectedComponents())
+	want := "[[docs publish-docs] [lint test build] [cleanup]]"
+	if got != want {
+		t.Errorf("ConnectedComponents() = %s, want %s", got, want)
+	}
+
+	raid, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "a"
+title = "A"
+
+[[legs]]
+id = "b"
+title = "B"
+
+[synthesis]
+title = "Combine"
+depends_on = ["a", "b"]
+`))
+	if err != nil {
+		t.Fatalf("Parse raid failed: %v", err)
+	}
+	if got := fmt.Sprint(raid.ConnectedComponents()); got != "[[a] [b]]" {
+		t.Errorf("raid ConnectedComponents() = %s, want [[a] [b]]", got)
+	}
+}
+
+func TestSynthesisMode(t *testing.T) {
+	parse := func(synthesis string) (*Ritual, error) {
+		return Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+focus = "Code vulnerabilities"
+
+[synthesis]
+title = "Report"
+` + synthesis))
+	}
+
+	f, err := parse("")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if mode, key := f.SynthesisMode(); mode != SynthesisCombine || key != "" {
+		t.Errorf("default SynthesisMode() = %q, %q, want %q, \"\"", mode, key, SynthesisCombine)
+	}
+
+	f, err = parse("mode = \"group_by\"\nkey = \"severity\"\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if mode, key := f.SynthesisMode(); mode != SynthesisGroupBy || key != "severity" {
+		t.Errorf("SynthesisMode() = %q, %q, want %q, \"severity\"", mode, key, SynthesisGroupBy)
+	}
+
+	for name, tc := range map[string]struct {
+		synthesis string
+		wantErr   string
+	}{
+		"unknown mode":     {"mode = \"vote\"\n", "invalid mode"},
+		"group_by no key":  {"mode = \"group_by\"\n", "requires a key"},
+		"key without mode": {"key = \"severity\"\n", "requires mode"},
+	} {
+		if _, err := parse(tc.synthesis); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: Parse error = %v, want %q", name, err, tc.wantErr)
+		}
+	}
+
+	noSynth, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "sast"
+title = "Static Analysis"
+focus = "Code vulnerabilities"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if mode, key := noSynth.SynthesisMode(); mode != "" || key != "" {
+		t.Errorf("SynthesisMode() without synthesis = %q, %q, want empty", mode, key)
+	}
+}
+
+func TestRenameStep(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[groups]]
+id = "checks"
+members = ["test", "lint"]
+
+[[steps]]
+id = "test"
+title = "Test"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["group:checks"]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+needs = [{ id = "test", type = "soft" }]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build", "docs"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	before, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+
+	if err := f.RenameStep("test", "unit-test"); err != nil {
+		t.Fatalf("RenameStep failed: %v", err)
+	}
+	if f.GetStep("test") != nil || f.GetStep("unit-test") == nil {
+		t.Fatalf("step not renamed: %v", f.GetAllIDs())
+	}
+	if got := fmt.Sprint(f.GetDependencies("build")); got != "[unit-test lint]" {
+		t.Errorf("build needs = %s, want [unit-test lint]", got)
+	}
+	if got := fmt.Sprint(f.GetStep("docs").SoftNeeds); got != "[unit-test]" {
+		t.Errorf("docs soft needs = %s, want [unit-test]", got)
+	}
+	if got := fmt.Sprint(f.GroupMembers("checks")); got != "[unit-test lint]" {
+		t.Errorf("checks members = %s, want [unit-test lint]", got)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate after rename: %v", err)
+	}
+
+	after, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	for i, id := range before {
+		if id == "test" {
+			before[i] = "unit-test"
+		}
+	}
+	if fmt.Sprint(after) != fmt.Sprint(before) {
+		t.Errorf("TopologicalSort after rename = %v, want %v", after, before)
+	}
+
+	for _, tc := range []struct{ old, new string }{
+		{"missing", "other"},
+		{"lint", "build"},
+		{"lint", ""},
+		{"lint", "group:checks"},
+	} {
+		if err := f.RenameStep(tc.old, tc.new); err == nil {
+			t.Errorf("RenameStep(%q, %q) succeeded, want error", tc.old, tc.new)
+		}
+	}
+
+	raid, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "a"
+title = "A"
+
+[[legs]]
+id = "b"
+title = "B"
+
+[synthesis]
+title = "Combine"
+depends_on = ["a", "b"]
+`))
+	if err != nil {
+		t.Fatalf("Parse raid failed: %v", err)
+	}
+	if err := raid.RenameStep("a", "security"); err != nil {
+		t.Fatalf("RenameStep raid leg failed: %v", err)
+	}
+	if got := fmt.Sprint(raid.Synthesis.DependsOn); got != "[security b]" {
+		t.Errorf("synthesis depends_on = %s, want [security b]", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "audit"
+type = "raid"
+description = "Audit {service} in {env:prod}"
+
+[vars.region]
+default = "us-east"
+
+[[legs]]
+id = "security"
+title = "Security ({region})"
+focus = "Audit {env:prod}"
+description = "Run {{.leg.id}} checks; export DIR=${HOME}/{service}; keep {step.id} and {not a placeholder}"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	t.Run("provided value", func(t *testing.T) {
+		r, err := f.Render(map[string]string{"service": "api", "env": "staging", "region": "eu"})
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if r.Description != "Audit api in staging" {
+			t.Errorf("Description = %q", r.Description)
+		}
+		leg := r.GetLeg("security")
+		if leg.Title != "Security (eu)" || leg.Focus != "Audit staging" {
+			t.Errorf("leg = %q / %q", leg.Title, leg.Focus)
+		}
+		want := "Run {{.leg.id}} checks; export DIR=${HOME}/api; keep {step.id} and {not a placeholder}"
+		if leg.Description != want {
+			t.Errorf("leg.Description = %q, want %q", leg.Description, want)
+		}
+		if f.Description != "Audit {service} in {env:prod}" {
+			t.Errorf("Render modified the original: %q", f.Description)
+		}
+	})
+
+	t.Run("default used", func(t *testing.T) {
+		r, err := f.Render(map[string]string{"service": "api"})
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if r.Description != "Audit api in prod" {
+			t.Errorf("Description = %q, want inline default", r.Description)
+		}
+		if got := r.GetLeg("security").Title; got != "Security (us-east)" {
+			t.Errorf("Title = %q, want [vars] default", got)
+		}
+	})
+
+	t.Run("no default", func(t *testing.T) {
+		_, err := f.Render(nil)
+		if !errors.Is(err, ErrUnresolvedPlaceholder) {
+			t.Fatalf("Render(nil) error = %v, want ErrUnresolvedPlaceholder", err)
+		}
+		if !strings.Contains(err.Error(), "service") || strings.Contains(err.Error(), "env") {
+			t.Errorf("error = %q, want only service reported", err)
+		}
+	})
+}
+
+func TestTopologicalSort_StableOrder(t *testing.T) {
+	data := []byte(`
+ritual = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "a1"
+title = "A1"
+[[steps]]
+id = "a2"
+title = "A2"
+needs = ["a1"]
+[[steps]]
+id = "b1"
+title = "B1"
+[[steps]]
+id = "b2"
+title = "B2"
+needs = ["b1"]
+[[steps]]
+id = "join"
+title = "Join"
+needs = ["b2", "a2"]
+[[steps]]
+id = "c1"
+title = "C1"
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Ready steps are taken in declaration order, not the order they became ready
+	want := []string{"a1", "a2", "b1", "b2", "join", "c1"}
+	for i := 0; i < 20; i++ {
+		order, err := f.TopologicalSort()
+		if err != nil {
+			t.Fatalf("TopologicalSort failed: %v", err)
+		}
+		if strings.Join(order, " ") != strings.Join(want, " ") {
+			t.Fatalf("TopologicalSort() = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestStepEnv(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "deploy"
+type = "workflow"
+
+[vars.region]
+default = "us-east-1"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "deploy"
+title = "Deploy"
+needs = ["build"]
+
+[steps.env]
+TARGET = "staging"
+AWS_REGION = "{region}"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if env := f.StepEnv("build"); env != nil {
+		t.Errorf("StepEnv(build) = %v, want nil", env)
+	}
+	if env := f.StepEnv("missing"); env != nil {
+		t.Errorf("StepEnv(missing) = %v, want nil", env)
+	}
+
+	env := f.StepEnv("deploy")
+	if fmt.Sprint(env) != "map[AWS_REGION:{region} TARGET:staging]" {
+		t.Errorf("StepEnv(deploy) = %v", env)
+	}
+	env["TARGET"] = "changed"
+	if f.StepEnv("deploy")["TARGET"] != "staging" {
+		t.Error("StepEnv returned the step's own map")
+	}
+
+	rendered, err := f.Render(map[string]string{"region": "eu-west-1"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := rendered.StepEnv("deploy")["AWS_REGION"]; got != "eu-west-1" {
+		t.Errorf("rendered AWS_REGION = %q, want eu-west-1", got)
+	}
+	if got := f.StepEnv("deploy")["AWS_REGION"]; got != "{region}" {
+		t.Errorf("Render modified the original env: AWS_REGION = %q", got)
+	}
+
+	_, err = Parse([]byte(`
+ritual = "bad"
+type = "workflow"
+
+[[steps]]
+id = "a"
+title = "A"
+
+[steps.env]
+"NOT-VALID" = "x"
+`))
+	if err == nil || !strings.Contains(err.Error(), "NOT-VALID") {
+		t.Errorf("Parse with invalid env name: err = %v, want invalid variable name error", err)
+	}
+}
+
+func TestSkippedGiven(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+optional = true
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["build"]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+needs = ["lint"]
+
+[[steps]]
+id = "report"
+title = "Report"
+needs = [{id = "test", type = "soft"}]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["test", "docs"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		failed []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"build"}, "test,publish"},   // transitive; soft dependent still runs
+		{[]string{"lint"}, ""},                // optional failure doesn't block
+		{[]string{"test", "lint"}, "publish"}, // failed steps aren't reported
+		{[]string{"publish"}, ""},             // nothing downstream
+	}
+	for _, tt := range tests {
+		failed := make(map[string]bool)
+		for _, id := range tt.failed {
+			failed[id] = true
+		}
+		if got := strings.Join(f.SkippedGiven(failed), ","); got != tt.want {
+			t.Errorf("SkippedGiven(%v) = %q, want %q", tt.failed, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulableSteps_ConcurrencyGroup(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "e2e"
+title = "E2E on staging"
+needs = ["build"]
+concurrency_group = "staging"
+
+[[steps]]
+id = "load"
+title = "Load test on staging"
+needs = ["build"]
+concurrency_group = "staging"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["build"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	completed := map[string]bool{"build": true}
+	// Both staging steps are ready, but only the first may start
+	if got := fmt.Sprint(f.ReadySteps(completed)); got != "[e2e load lint]" {
+		t.Errorf("ReadySteps = %s, want [e2e load lint]", got)
+	}
+	if got := fmt.Sprint(f.SchedulableSteps(completed, nil)); got != "[e2e lint]" {
+		t.Errorf("SchedulableSteps = %s, want [e2e lint]", got)
+	}
+
+	// While e2e holds the group, load still waits
+	running := map[string]bool{"e2e": true, "lint": true}
+	if got := f.SchedulableSteps(completed, running); len(got) != 0 {
+		t.Errorf("SchedulableSteps with e2e running = %v, want none", got)
+	}
+
+	completed["e2e"] = true
+	if got := fmt.Sprint(f.SchedulableSteps(completed, map[string]bool{"lint": true})); got != "[load]" {
+		t.Errorf("SchedulableSteps after e2e = %s, want [load]", got)
+	}
+}
+
+func TestExecutor_ConcurrencyGroup(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "deploy-a"
+title = "Deploy A"
+concurrency_group = "staging"
+
+[[steps]]
+id = "deploy-b"
+title = "Deploy B"
+concurrency_group = "staging"
+
+[[steps]]
+id = "deploy-c"
+title = "Deploy C"
+concurrency_group = "staging"
+
+[[steps]]
+id = "docs"
+title = "Docs"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var staging, peak int32
+	var waves []string
+	e := NewExecutor(f)
+	e.OnWave = func(wave []string) { waves = append(waves, fmt.Sprint(wave)) }
+	_, err = e.Execute(context.Background(), func(ctx context.Context, id string) error {
+		if id == "docs" {
+			return nil
+		}
+		n := atomic.AddInt32(&staging, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&staging, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if peak != 1 {
+		t.Errorf("peak staging concurrency = %d, want 1", peak)
+	}
+	if got := strings.Join(waves, " "); got != "[deploy-a docs] [deploy-b] [deploy-c]" {
+		t.Errorf("waves = %s", got)
+	}
+}
+
+func TestParse_InvalidConcurrencyGroup(t *testing.T) {
+	_, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "deploy"
+title = "Deploy"
+concurrency_group = "staging env"
+`))
+	if err == nil || !strings.Contains(err.Error(), "invalid concurrency_group") {
+		t.Errorf("Parse error = %v, want invalid concurrency_group", err)
+	}
+}
+
+func TestRequiredFor(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["build"]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["test", "docs"]
+soft_needs = ["lint"]
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["publish"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"publish", "[build test docs publish]"}, // soft need on lint isn't followed
+		{"test", "[build test]"},
+		{"lint", "[lint]"},
+		{"announce", "[build test docs publish announce]"},
+	}
+	for _, tt := range tests {
+		got, err := f.RequiredFor(tt.target)
+		if err != nil {
+			t.Errorf("RequiredFor(%s): %v", tt.target, err)
+			continue
+		}
+		if fmt.Sprint(got) != tt.want {
+			t.Errorf("RequiredFor(%s) = %v, want %s", tt.target, got, tt.want)
+		}
+	}
+
+	if _, err := f.RequiredFor("deploy"); err == nil {
+		t.Error("RequiredFor(unknown): expected error")
+	}
+}
+
+func TestRequiredFor_RaidSynthesis(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "security"
+title = "Security"
+
+[[legs]]
+id = "perf"
+title = "Performance"
+
+[synthesis]
+title = "Summary"
+depends_on = ["security", "perf"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got, err := f.RequiredFor("synthesis"); err != nil || fmt.Sprint(got) != "[security perf synthesis]" {
+		t.Errorf("RequiredFor(synthesis) = %v, %v", got, err)
+	}
+	if got, err := f.RequiredFor("perf"); err != nil || fmt.Sprint(got) != "[perf]" {
+		t.Errorf("RequiredFor(perf) = %v, %v", got, err)
+	}
+}
+
+func TestStepDirAndShell(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "build"
+type = "workflow"
+
+[[steps]]
+id = "frontend"
+title = "Frontend"
+cwd = "web/./app"
+shell = "bash"
+
+[[steps]]
+id = "root"
+title = "Root"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if dir, err := f.StepDir("frontend", "/repo"); err != nil || dir != "/repo/web/app" {
+		t.Errorf("StepDir(frontend) = %q, %v; want /repo/web/app", dir, err)
+	}
+	if dir, err := f.StepDir("root", "/repo"); err != nil || dir != "/repo" {
+		t.Errorf("StepDir(root) = %q, %v; want /repo", dir, err)
+	}
+	if _, err := f.StepDir("missing", "/repo"); err == nil {
+		t.Error("StepDir(missing): expected error")
+	}
+	if got := f.StepShell("frontend"); got != "bash" {
+		t.Errorf("StepShell(frontend) = %q, want bash", got)
+	}
+	if got := f.StepShell("root"); got != DefaultShell {
+		t.Errorf("StepShell(root) = %q, want %q", got, DefaultShell)
+	}
+}
+
+func TestParse_InvalidStepCwdAndShell(t *testing.T) {
+	tests := []struct {
+		field   string
+		wantErr string
+	}{
+		{`cwd = "../elsewhere"`, "escapes the execution root"},
+		{`cwd = "sub/../../elsewhere"`, "escapes the execution root"},
+		{`cwd = "/etc"`, "must be relative"},
+		{`shell = "bash -e"`, "invalid shell"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "step"
+title = "Step"
+` + tt.field + "\n"))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Parse error = %v, want containing %q", tt.field, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCommonAncestorsAndDiamondPoints(t *testing.T) {
+	// setup → (build, lint); build → (unit, e2e); (unit, e2e, lint) → release
+	f, err := Parse([]byte(`
+ritual = "ci"
+type = "workflow"
+
+[[steps]]
+id = "setup"
+title = "Setup"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["setup"]
+
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["setup"]
+
+[[steps]]
+id = "unit"
+title = "Unit"
+needs = ["build"]
+
+[[steps]]
+id = "e2e"
+title = "E2E"
+needs = ["build"]
+
+[[steps]]
+id = "release"
+title = "Release"
+needs = ["unit", "e2e", "lint"]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"unit", "e2e", "[setup build]"},
+		{"unit", "lint", "[setup]"},
+		{"build", "unit", "[setup]"}, // build is unit's ancestor, not its own
+		{"unit", "docs", "[]"},
+		{"unit", "missing", "[]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(f.CommonAncestors(tt.a, tt.b)); got != tt.want {
+			t.Errorf("CommonAncestors(%s, %s) = %s, want %s", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprint(f.DiamondPoints()); got != "[release]" {
+		t.Errorf("DiamondPoints() = %s, want [release]", got)
+	}
+}
+
+func TestExecutor_Simulate(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "setup"
+title = "Setup"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["setup"]
+
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["setup"]
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["setup"]
+
+[[steps]]
+id = "deploy-a"
+title = "Deploy A"
+needs = ["build"]
+concurrency_group = "staging"
+
+[[steps]]
+id = "deploy-b"
+title = "Deploy B"
+needs = ["build"]
+concurrency_group = "staging"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	durations := map[string]time.Duration{
+		"setup":    time.Minute,
+		"build":    4 * time.Minute,
+		"lint":     time.Minute,
+		"test":     2 * time.Minute,
+		"deploy-a": 3 * time.Minute,
+		"deploy-b": 3 * time.Minute,
+	}
+	describe := func(r SimulationResult) string {
+		var parts []string
+		for _, s := range r.Steps {
+			parts = append(parts, fmt.Sprintf("%s@%d:%v-%v", s.ID, s.Worker, s.Start, s.Finish))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	e := NewExecutor(f)
+	got := e.Simulate(durations)
+	want := "setup@0:0s-1m0s build@0:1m0s-5m0s lint@1:1m0s-2m0s test@2:1m0s-3m0s deploy-a@0:5m0s-8m0s deploy-b@0:8m0s-11m0s"
+	if describe(got) != want {
+		t.Errorf("unlimited schedule:\n got %s\nwant %s", describe(got), want)
+	}
+	if got.Makespan != 11*time.Minute || got.Workers != 3 {
+		t.Errorf("unlimited: makespan %v with %d workers, want 11m0s with 3", got.Makespan, got.Workers)
+	}
+
+	// Two workers: test waits for lint's worker, but still finishes before build
+	e.ParallelLimit = 2
+	got = e.Simulate(durations)
+	want = "setup@0:0s-1m0s build@0:1m0s-5m0s lint@1:1m0s-2m0s test@1:2m0s-4m0s deploy-a@0:5m0s-8m0s deploy-b@0:8m0s-11m0s"
+	if describe(got) != want {
+		t.Errorf("limited schedule:\n got %s\nwant %s", describe(got), want)
+	}
+	if got.Makespan != 11*time.Minute || got.Workers != 2 {
+		t.Errorf("limited: makespan %v with %d workers, want 11m0s with 2", got.Makespan, got.Workers)
+	}
+
+	if got := e.Simulate(nil); got.Makespan != 0 || len(got.Steps) != 6 {
+		t.Errorf("no durations: makespan %v over %d steps, want 0s over 6", got.Makespan, len(got.Steps))
+	}
+}
+
+func TestToCIJobs(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "test"
+type = "workflow"
+
+[[steps]]
+id = "build.linux"
+title = "Build Linux"
+
+[[steps]]
+id = "lint"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["build.linux", {id = "lint", type = "soft"}]
+
+[[steps]]
+id = "deploy"
+title = "Deploy"
+needs = ["test"]
+optional = true
+concurrency_group = "staging"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	jobs, err := f.ToCIJobs()
+	if err != nil {
+		t.Fatalf("ToCIJobs: %v", err)
+	}
+	var got []string
+	for _, j := range jobs {
+		got = append(got, fmt.Sprintf("%s(%s %q) needs=%v soft=%v wave=%d optional=%v group=%q",
+			j.ID, j.Step, j.Name, j.Needs, j.SoftNeeds, j.Wave, j.Optional, j.Group))
+	}
+	want := []string{
+		`build_linux(build.linux "Build Linux") needs=[] soft=[] wave=0 optional=false group=""`,
+		`lint(lint "lint") needs=[] soft=[] wave=0 optional=false group=""`,
+		`test(test "Test") needs=[build_linux lint] soft=[lint] wave=1 optional=false group=""`,
+		`deploy(deploy "Deploy") needs=[test] soft=[] wave=2 optional=true group="staging"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ToCIJobs:\n got %s\nwant %s", strings.Join(got, "\n     "), strings.Join(want, "\n     "))
+	}
+}
+
+func TestToCIJobs_RaidSynthesis(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "security"
+title = "Security"
+
+[[legs]]
+id = "perf"
+title = "Performance"
+
+[synthesis]
+title = "Summary"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	jobs, err := f.ToCIJobs()
+	if err != nil {
+		t.Fatalf("ToCIJobs: %v", err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3 (two legs and synthesis)", len(jobs))
+	}
+	synth := jobs[2]
+	if synth.ID != "synthesis" || synth.Name != "Summary" || fmt.Sprint(synth.Needs) != "[security perf]" || synth.Wave != 1 {
+		t.Errorf("synthesis job = %+v", synth)
+	}
+}
+
+func TestCIJobID(t *testing.T) {
+	for id, want := range map[string]string{
+		"build":       "build",
+		"build.linux": "build_linux",
+		"deploy-prod": "deploy-prod",
+		"2fa":         "_2fa",
+		"-x":          "_-x",
+		"":            "_",
+	} {
+		if got := ciJobID(id); got != want {
+			t.Errorf("ciJobID(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	t.Run("workflow", func(t *testing.T) {
+		f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "test"
+title = "Run \"all\" tests"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+optional = true
+
+[[steps]]
+id = "publish"
+needs = ["test", {id = "lint", type = "soft"}]
+`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		want := `digraph "release" {
+    rankdir=TB;
+    node [shape=box];
+    "test" [label="Run \"all\" tests"];
+    "lint" [label="Lint", style=dashed];
+    "publish" [label="publish"];
+    "test" -> "publish";
+    "lint" -> "publish" [style=dashed];
+}
+`
+		if got := f.RenderDOT(); got != want {
+			t.Errorf("RenderDOT() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("raid with synthesis", func(t *testing.T) {
+		f, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "sec"
+title = "Security"
+
+[[legs]]
+id = "perf"
+title = "Performance"
+
+[synthesis]
+title = "Summary"
+`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		got := f.RenderDOT()
+		for _, line := range []string{
+			`"synthesis" [label="Summary", peripheries=2, style=filled, fillcolor="#fff4d6"];`,
+			`"sec" -> "synthesis";`,
+			`"perf" -> "synthesis";`,
+		} {
+			if !strings.Contains(got, line) {
+				t.Errorf("RenderDOT() missing %q:\n%s", line, got)
+			}
+		}
+	})
+}
+
+func TestSubgraph(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+type = "workflow"
+
+[[steps]]
+id = "setup"
+title = "Setup"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["setup", {id = "lint", type = "soft"}]
+
+[[steps]]
+id = "docs"
+title = "Docs"
+needs = ["setup"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build", "docs"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	g, err := f.Subgraph("build")
+	if err != nil {
+		t.Fatalf("Subgraph(build): %v", err)
+	}
+	if got := fmt.Sprint(g.GetAllIDs()); got != "[setup build]" {
+		t.Errorf("Subgraph(build) steps = %s, want [setup build]", got)
+	}
+	if build := g.GetStep("build"); fmt.Sprint(build.Needs) != "[setup]" || len(build.SoftNeeds) != 0 {
+		t.Errorf("build needs = %v (soft %v), want [setup] with the soft need on lint dropped", build.Needs, build.SoftNeeds)
+	}
+	if got := fmt.Sprint(f.GetStep("build").Needs); got != "[setup lint]" {
+		t.Errorf("Subgraph modified the original ritual: build needs = %s", got)
+	}
+
+	if _, err := f.Subgraph("nope"); err == nil {
+		t.Error("Subgraph(nope): error = nil, want unknown step")
+	}
+}
+
+func TestParseMulti(t *testing.T) {
+	rituals, err := ParseMulti([]byte(`
+[[rituals]]
+ritual = "build"
+
+[[rituals.steps]]
+id = "compile"
+title = "Compile"
+
+[[rituals.steps]]
+id = "package"
+title = "Package"
+needs = [{id = "compile", type = "soft"}]
+
+[[rituals]]
+ritual = "review"
+
+[[rituals.legs]]
+id = "security"
+title = "Security"
+`))
+	if err != nil {
+		t.Fatalf("ParseMulti failed: %v", err)
+	}
+	if len(rituals) != 2 {
+		t.Fatalf("got %d rituals, want 2", len(rituals))
+	}
+	build, review := rituals[0], rituals[1]
+	if build.Name != "build" || build.Type != TypeWorkflow || review.Name != "review" || review.Type != TypeRaid {
+		t.Errorf("got %s (%s) and %s (%s), want build (workflow) and review (raid)",
+			build.Name, build.Type, review.Name, review.Type)
+	}
+	if got := build.GetStep("package").NeedType("compile"); got != NeedSoft {
+		t.Errorf("package's need on compile = %s, want soft", got)
+	}
+
+	// A single-ritual document parses as one ritual
+	single, err := ParseMulti([]byte(`
+ritual = "solo"
+[[steps]]
+id = "a"
+title = "A"
+`))
+	if err != nil || len(single) != 1 || single[0].Name != "solo" {
+		t.Errorf("ParseMulti(single) = %v, %v, want [solo]", single, err)
+	}
+}
+
+func TestParseMulti_Errors(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{
+			name: "duplicate name",
+			doc: `
+[[rituals]]
+ritual = "build"
+[[rituals.steps]]
+id = "a"
+title = "A"
+
+[[rituals]]
+ritual = "build"
+[[rituals.steps]]
+id = "b"
+title = "B"
+`,
+			want: "duplicate ritual name: build",
+		},
+		{
+			name: "invalid member",
+			doc: `
+[[rituals]]
+ritual = "build"
+[[rituals.steps]]
+id = "a"
+title = "A"
+needs = ["missing"]
+`,
+			want: `ritual "build":`,
+		},
+		{
+			name: "stray top-level key",
+			doc: `
+description = "mine"
+
+[[rituals]]
+ritual = "build"
+[[rituals.steps]]
+id = "a"
+title = "A"
+`,
+			want: `top-level key "description" outside [[rituals]]`,
+		},
+		{
+			name: "empty",
+			doc:  `rituals = []`,
+			want: "defines no rituals",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMulti([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseMulti error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	_, err := Parse([]byte("[[rituals]]\nritual = \"build\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "ParseMulti") {
+		t.Errorf("Parse of a multi-ritual document: error = %v, want a hint to use ParseMulti", err)
+	}
+}
+
+func TestValidateIDs(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{
+			name: "slash in step id",
+			doc: `
+ritual = "build"
+[[steps]]
+id = "db/migrate"
+title = "Migrate"
+`,
+			want: `invalid step id "db/migrate"`,
+		},
+		{
+			name: "space in leg id",
+			doc: `
+ritual = "review"
+type = "raid"
+[[legs]]
+id = "api review"
+title = "API"
+`,
+			want: `invalid leg id "api review"`,
+		},
+		{
+			name: "colon in aspect id",
+			doc: `
+ritual = "audit"
+type = "aspect"
+[[aspects]]
+id = "sec:scan"
+title = "Scan"
+focus = "security"
+`,
+			want: `invalid aspect id "sec:scan"`,
+		},
+		{
+			name: "leading dash",
+			doc: `
+ritual = "build"
+[[steps]]
+id = "-x"
+title = "X"
+`,
+			want: `invalid step id "-x"`,
+		},
+		{
+			name: "unsafe template id outside placeholder",
+			doc: `
+ritual = "expand"
+type = "expansion"
+[[template]]
+id = "{target}/draft"
+title = "Draft"
+`,
+			want: `invalid template id "{target}/draft"`,
+		},
+		{
+			name: "case collision",
+			doc: `
+ritual = "build"
+[[steps]]
+id = "build"
+title = "Build"
+[[steps]]
+id = "Build"
+title = "Build again"
+`,
+			want: `step id "Build" collides with "build"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	ok := `
+ritual = "expand"
+type = "expansion"
+[[template]]
+id = "{target}.draft"
+title = "Draft"
+[[template]]
+id = "{step.id}-security_scan"
+title = "Scan"
+`
+	if _, err := Parse([]byte(ok)); err != nil {
+		t.Errorf("Parse() with placeholder template ids: %v", err)
+	}
+
+	for id, valid := range map[string]bool{
+		"gt-abc.draft":    true,
+		"merge_requested": true,
+		"Step2":           true,
+		"":                false,
+		"a b":             false,
+		"gt-abc/draft":    false,
+		".hidden":         false,
+	} {
+		if got := safeID.MatchString(id); got != valid {
+			t.Errorf("safeID.MatchString(%q) = %v, want %v", id, got, valid)
+		}
+	}
+}
+
+func TestInsertStep(t *testing.T) {
+	doc := `
+ritual = "release"
+
+[[steps]]
+id = "fetch"
+title = "Fetch"
+
+[[steps]]
+id = "build-api"
+title = "Build API"
+needs = ["fetch"]
+
+[[steps]]
+id = "build-web"
+title = "Build web"
+needs = ["fetch"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build-api", "build-web"]
+`
+	f, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	err = f.InsertStep(Step{ID: "scan", Title: "Scan"}, []string{"build-api", "build-web"}, []string{"fetch"})
+	if err != nil {
+		t.Fatalf("InsertStep: %v", err)
+	}
+	if got := strings.Join(f.GetStep("scan").Needs, ","); got != "fetch" {
+		t.Errorf("scan needs = %s, want fetch", got)
+	}
+	for _, id := range []string{"build-api", "build-web"} {
+		if got := strings.Join(f.GetStep(id).Needs, ","); got != "fetch,scan" {
+			t.Errorf("%s needs = %s, want fetch,scan", id, got)
+		}
+	}
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "fetch,scan,build-api,build-web,publish" {
+		t.Errorf("order = %s", got)
+	}
+
+	// No before steps: the step goes last, after its needs.
+	if err := f.InsertStep(Step{ID: "notify", Title: "Notify"}, nil, []string{"publish"}); err != nil {
+		t.Fatalf("InsertStep(notify): %v", err)
+	}
+	if last := f.Steps[len(f.Steps)-1].ID; last != "notify" {
+		t.Errorf("last step = %s, want notify", last)
+	}
+
+	errCases := []struct {
+		name          string
+		step          Step
+		before, after []string
+		want          string
+	}{
+		{"cycle", Step{ID: "gate", Title: "Gate"}, []string{"fetch"}, []string{"publish"}, "cycle"},
+		{"duplicate", Step{ID: "scan", Title: "Scan"}, nil, nil, "step id already in use: scan"},
+		{"unknown before", Step{ID: "gate", Title: "Gate"}, []string{"missing"}, nil, "unknown step: missing"},
+		{"unknown after", Step{ID: "gate", Title: "Gate"}, nil, []string{"missing"}, "unknown step: missing"},
+		{"empty id", Step{Title: "Gate"}, nil, nil, "missing required id"},
+		{"unsafe id", Step{ID: "a/b", Title: "Gate"}, nil, nil, `invalid step id "a/b"`},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			steps := len(f.Steps)
+			fetchNeeds := len(f.GetStep("fetch").Needs)
+			err := f.InsertStep(tc.step, tc.before, tc.after)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("InsertStep() error = %v, want containing %q", err, tc.want)
+			}
+			if len(f.Steps) != steps || len(f.GetStep("fetch").Needs) != fetchNeeds {
+				t.Error("failed InsertStep modified the ritual")
+			}
+		})
+	}
+
+	raid := &Ritual{Name: "r", Type: TypeRaid, Legs: []Leg{{ID: "a", Title: "A"}}}
+	if err := raid.InsertStep(Step{ID: "x", Title: "X"}, nil, nil); err == nil {
+		t.Error("InsertStep on a raid ritual should fail")
+	}
+}
+
+func TestSinks(t *testing.T) {
+	workflow := `
+ritual = "release"
+%s
+
+[[steps]]
+id = "build"
+title = "Build"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+
+[[steps]]
+id = "docs"
+title = "Docs"
+needs = ["build"]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["build", "lint"]
+`
+	f, err := Parse([]byte(fmt.Sprintf(workflow, "")))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := strings.Join(f.Sinks(), ","); got != "docs,publish" {
+		t.Errorf("Sinks() = %s, want docs,publish", got)
+	}
+
+	_, err = Parse([]byte(fmt.Sprintf(workflow, `final = "publish"`)))
+	if err == nil || !strings.Contains(err.Error(), `final step "publish" must be the only sink, but nothing depends on: docs`) {
+		t.Errorf("Parse() with dangling docs: error = %v", err)
+	}
+	_, err = Parse([]byte(fmt.Sprintf(workflow, `final = "ship"`)))
+	if err == nil || !strings.Contains(err.Error(), "final references unknown step: ship") {
+		t.Errorf("Parse() with unknown final: error = %v", err)
+	}
+
+	fixed := strings.Replace(fmt.Sprintf(workflow, `final = "publish"`), `needs = ["build", "lint"]`, `needs = ["docs", "lint"]`, 1)
+	f, err = Parse([]byte(fixed))
+	if err != nil {
+		t.Fatalf("Parse() with single sink: %v", err)
+	}
+	if err := f.RenameStep("publish", "ship"); err != nil || f.Final != "ship" {
+		t.Errorf("RenameStep: err = %v, final = %q, want ship", err, f.Final)
+	}
+
+	raid := `
+ritual = "review"
+type = "raid"
+final = "synthesis"
+
+[[legs]]
+id = "api"
+title = "API"
+
+[[legs]]
+id = "ui"
+title = "UI"
+
+[synthesis]
+title = "Summary"
+%s
+`
+	f, err = Parse([]byte(fmt.Sprintf(raid, "")))
+	if err != nil {
+		t.Fatalf("Parse raid: %v", err)
+	}
+	if got := strings.Join(f.Sinks(), ","); got != "synthesis" {
+		t.Errorf("raid Sinks() = %s, want synthesis", got)
+	}
+	_, err = Parse([]byte(fmt.Sprintf(raid, `depends_on = ["api"]`)))
+	if err == nil || !strings.Contains(err.Error(), "nothing depends on: ui") {
+		t.Errorf("Parse() raid with leg left out of synthesis: error = %v", err)
+	}
+}
+
+func TestInvalidates(t *testing.T) {
+	f, err := Parse([]byte(`
+ritual = "release"
+
+[[steps]]
+id = "fetch"
+title = "Fetch"
+
+[[steps]]
+id = "build"
+title = "Build"
+needs = ["fetch"]
+
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["fetch"]
+
+[[steps]]
+id = "package"
+title = "Package"
+needs = ["build"]
+
+[[steps]]
+id = "report"
+title = "Report"
+needs = [{ id = "build", type = "soft" }]
+
+[[steps]]
+id = "publish"
+title = "Publish"
+needs = ["package", "lint"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := map[string]string{
+		"fetch":   "build,lint,package,publish",
+		"build":   "package,publish",
+		"publish": "",
+	}
+	for id, want := range tests {
+		if got := strings.Join(f.Invalidates(id), ","); got != want {
+			t.Errorf("Invalidates(%s) = %s, want %s", id, got, want)
+		}
+	}
+	if got := f.Invalidates("missing"); got != nil {
+		t.Errorf("Invalidates(missing) = %v, want nil", got)
+	}
+
+	// Resuming after re-running build: drop it and what it invalidates.
+	completed := map[string]bool{"fetch": true, "build": true, "lint": true, "package": true, "report": true}
+	delete(completed, "build")
+	for _, id := range f.Invalidates("build") {
+		delete(completed, id)
+	}
+	if got := strings.Join(f.ReadySteps(completed), ","); got != "build" {
+		t.Errorf("ReadySteps after invalidation = %s, want build", got)
+	}
+
+	raid, err := Parse([]byte(`
+ritual = "review"
+type = "raid"
+
+[[legs]]
+id = "api"
+title = "API"
+
+[[legs]]
+id = "ui"
+title = "UI"
+
+[synthesis]
+title = "Summary"
+depends_on = ["api"]
+`))
+	if err != nil {
+		t.Fatalf("Parse raid: %v", err)
+	}
+	if got := strings.Join(raid.Invalidates("api"), ","); got != "synthesis" {
+		t.Errorf("raid Invalidates(api) = %s, want synthesis", got)
+	}
+	if got := raid.Invalidates("ui"); len(got) != 0 {
+		t.Errorf("raid Invalidates(ui) = %v, want none", got)
+	}
+}
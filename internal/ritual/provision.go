@@ -0,0 +1,155 @@
+package ritual
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProvisionAction is what provisioning would do to one embedded ritual.
+type ProvisionAction string
+
+const (
+	// ActionCreate writes a ritual that isn't on disk (new, or deleted by the user).
+	ActionCreate ProvisionAction = "create"
+	// ActionUpdate overwrites an outdated or untracked ritual with the embedded version.
+	ActionUpdate ProvisionAction = "update"
+	// ActionSkip leaves a ritual alone because the user modified it.
+	ActionSkip ProvisionAction = "skip"
+	// ActionError leaves a ritual alone because the file on disk can't be read.
+	ActionError ProvisionAction = "error"
+	// ActionNone means the ritual on disk already matches the embedded version.
+	ActionNone ProvisionAction = "none"
+)
+
+// PlannedFormula is one embedded ritual in a ProvisionPlan.
+type PlannedFormula struct {
+	Name   string          // ritual filename
+	Status string          // as in FormulaStatus: "new", "missing", "outdated", "untracked", "modified", "ok", or "error"
+	Action ProvisionAction // what UpdateFormulas would do
+	Hash   string          // hash of the embedded version
+	Err    error           // why the file on disk couldn't be read (ActionError only)
+}
+
+// ProvisionPlan describes what provisioning would do to a relics workspace,
+// computed without writing anything. ProvisionFormulas applies only the
+// ActionCreate entries; UpdateFormulas applies ActionCreate and ActionUpdate.
+type ProvisionPlan struct {
+	Dir     string           // the workspace's .relics/rituals directory
+	Rituals []PlannedFormula // sorted by name
+}
+
+// PlanProvision compares the embedded rituals against relicsPath's
+// .relics/rituals directory and reports, per ritual, whether it would be
+// created, updated, skipped as user-modified, or left alone as unreadable.
+// Nothing is written, so it is safe to run as a preview before
+// ProvisionFormulas or UpdateFormulas.
+func PlanProvision(relicsPath string) (ProvisionPlan, error) {
+	embedded, err := getEmbeddedFormulas()
+	if err != nil {
+		return ProvisionPlan{}, err
+	}
+
+	plan := ProvisionPlan{Dir: filepath.Join(relicsPath, ".relics", "rituals")}
+	installed, err := loadInstalledRecord(plan.Dir)
+	if err != nil {
+		return ProvisionPlan{}, err
+	}
+
+	for filename, embeddedHash := range embedded {
+		p := PlannedFormula{Name: filename, Hash: embeddedHash}
+		p.Status, _, p.Err = classifyFormula(plan.Dir, filename, embeddedHash, installed)
+		switch p.Status {
+		case "missing", "new":
+			p.Action = ActionCreate
+		case "outdated", "untracked":
+			p.Action = ActionUpdate
+		case "ok":
+			p.Action = ActionNone
+		case "error":
+			p.Action = ActionError
+		default:
+			p.Action = ActionSkip
+		}
+		plan.Rituals = append(plan.Rituals, p)
+	}
+
+	sort.Slice(plan.Rituals, func(i, j int) bool {
+		return plan.Rituals[i].Name < plan.Rituals[j].Name
+	})
+	return plan, nil
+}
+
+// Count returns how many rituals the plan assigns the given action.
+func (p ProvisionPlan) Count(action ProvisionAction) int {
+	n := 0
+	for _, f := range p.Rituals {
+		if f.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary returns a one-line description of the plan,
+// e.g. "2 rituals to create, 1 skipped (locally modified)".
+func (p ProvisionPlan) Summary() string {
+	var parts []string
+	if n := p.Count(ActionCreate); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s to create", n, pluralRituals(n)))
+	}
+	if n := p.Count(ActionUpdate); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s to update", n, pluralRituals(n)))
+	}
+	if n := p.Count(ActionSkip); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped (locally modified)", n))
+	}
+	if n := p.Count(ActionError); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped (unreadable)", n))
+	}
+	if len(parts) == 0 {
+		return "all rituals up to date"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// apply writes the planned rituals whose action is in actions and records
+// their hashes in .installed.json. onWrite is called after each write.
+// Writing stops at the first error.
+func (p ProvisionPlan) apply(actions []ProvisionAction, onWrite func(PlannedFormula)) error {
+	if err := os.MkdirAll(p.Dir, 0755); err != nil {
+		return fmt.Errorf("creating rituals directory: %w", err)
+	}
+	installed, err := loadInstalledRecord(p.Dir)
+	if err != nil {
+		return err
+	}
+
+	var applyErr error
+	for _, f := range p.Rituals {
+		if !slices.Contains(actions, f.Action) {
+			continue
+		}
+		content, err := formulasFS.ReadFile("rituals/" + f.Name)
+		if err != nil {
+			applyErr = fmt.Errorf("reading %s: %w", f.Name, err)
+			break
+		}
+		if err := os.WriteFile(filepath.Join(p.Dir, f.Name), content, 0644); err != nil {
+			applyErr = fmt.Errorf("writing %s: %w", f.Name, err)
+			break
+		}
+		installed.Rituals[f.Name] = f.Hash
+		installed.Content[f.Name] = ContentHash(content)
+		onWrite(f)
+	}
+
+	// Record what was written, even after a partial failure
+	if err := saveInstalledRecord(p.Dir, installed); err != nil && applyErr == nil {
+		applyErr = fmt.Errorf("saving installed record: %w", err)
+	}
+	return applyErr
+}
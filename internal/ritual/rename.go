@@ -0,0 +1,77 @@
+package ritual
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RenameStep changes the ID of a step, template, leg, or aspect from
+// oldID to newID and rewrites every reference to it: needs, soft needs,
+// and depends_on of steps and templates, synthesis depends_on, group
+// members, and final. Item order is unchanged, so TopologicalSort returns
+// the same order with newID in place of oldID.
+//
+// Returns an error if oldID doesn't exist, newID is empty, a group
+// reference, or already in use.
+func (f *Ritual) RenameStep(oldID, newID string) error {
+	ids := f.GetAllIDs()
+	if !slices.Contains(ids, oldID) {
+		return fmt.Errorf("unknown step: %s", oldID)
+	}
+	if newID == "" {
+		return fmt.Errorf("new step id is empty")
+	}
+	if strings.HasPrefix(newID, GroupRefPrefix) {
+		return fmt.Errorf("step id %q cannot start with %q", newID, GroupRefPrefix)
+	}
+	if newID == oldID {
+		return nil
+	}
+	if slices.Contains(ids, newID) {
+		return fmt.Errorf("step id already in use: %s", newID)
+	}
+
+	switch f.Type {
+	case TypeWorkflow:
+		f.GetStep(oldID).ID = newID
+	case TypeExpansion:
+		f.GetTemplate(oldID).ID = newID
+	case TypeRaid:
+		f.GetLeg(oldID).ID = newID
+	case TypeAspect:
+		f.GetAspect(oldID).ID = newID
+	}
+
+	for i := range f.Steps {
+		s := &f.Steps[i]
+		renameRef(s.Needs, oldID, newID)
+		renameRef(s.SoftNeeds, oldID, newID)
+		renameRef(s.DependsOn, oldID, newID)
+	}
+	for i := range f.Template {
+		renameRef(f.Template[i].Needs, oldID, newID)
+		renameRef(f.Template[i].DependsOn, oldID, newID)
+	}
+	if f.Synthesis != nil {
+		renameRef(f.Synthesis.DependsOn, oldID, newID)
+		renameRef(f.Synthesis.Needs, oldID, newID)
+	}
+	for i := range f.Groups {
+		renameRef(f.Groups[i].Members, oldID, newID)
+	}
+	if f.Final == oldID {
+		f.Final = newID
+	}
+	return nil
+}
+
+// renameRef replaces oldID with newID in refs, in place. Group references
+// ("group:<id>") name groups, not items, and are left alone.
+func renameRef(refs []string, oldID, newID string) {
+	for i, ref := range refs {
+		if ref == oldID {
+			refs[i] = newID
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package ritual
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnresolvedPlaceholder is returned by Render when a placeholder has no
+// value and no default.
+var ErrUnresolvedPlaceholder = errors.New("unresolved placeholder")
+
+// placeholderName matches the names Render substitutes. Dotted names such as
+// {step.id} are left for the executor to fill in at run time.
+var placeholderName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Render returns a copy of the ritual with {name} placeholders in its
+// descriptive text replaced: the ritual description, prompts, and the
+// titles, descriptions, and focus of steps, templates, legs, aspects,
+// groups, and synthesis. IDs and dependencies are not rendered.
+//
+// A placeholder takes its value from vars, then from an inline default
+// ({name:default}), then from the default of the matching [vars] or
+// [inputs] entry. Placeholders left without a value are reported together
+// in an error wrapping ErrUnresolvedPlaceholder. Go template actions
+// ({{...}}) and shell expansions (${...}) are not placeholders and are
+// copied unchanged.
+func (f *Ritual) Render(vars map[string]string) (*Ritual, error) {
+	fallback := func(name string) (string, bool) {
+		if v, ok := f.Vars[name]; ok && v.Default != "" {
+			return v.Default, true
+		}
+		if in, ok := f.Inputs[name]; ok && in.Default != "" {
+			return in.Default, true
+		}
+		return "", false
+	}
+
+	unresolved := make(map[string]bool)
+	render := func(s *string) {
+		*s = expandPlaceholders(*s, func(name, def string, hasDef bool) (string, bool) {
+			if v, ok := vars[name]; ok {
+				return v, true
+			}
+			if hasDef {
+				return def, true
+			}
+			if v, ok := fallback(name); ok {
+				return v, true
+			}
+			unresolved[name] = true
+			return "", false
+		})
+	}
+
+	c := f.clone()
+	render(&c.Description)
+	for k, v := range c.Prompts {
+		render(&v)
+		c.Prompts[k] = v
+	}
+	for i := range c.Steps {
+		render(&c.Steps[i].Title)
+		render(&c.Steps[i].Description)
+		for k, v := range c.Steps[i].Env {
+			render(&v)
+			c.Steps[i].Env[k] = v
+		}
+	}
+	for i := range c.Template {
+		render(&c.Template[i].Title)
+		render(&c.Template[i].Description)
+	}
+	for i := range c.Legs {
+		render(&c.Legs[i].Title)
+		render(&c.Legs[i].Focus)
+		render(&c.Legs[i].Description)
+	}
+	for i := range c.Aspects {
+		render(&c.Aspects[i].Title)
+		render(&c.Aspects[i].Focus)
+		render(&c.Aspects[i].Description)
+	}
+	for i := range c.Groups {
+		render(&c.Groups[i].Title)
+	}
+	if c.Synthesis != nil {
+		render(&c.Synthesis.Title)
+		render(&c.Synthesis.Description)
+	}
+
+	if len(unresolved) > 0 {
+		names := make([]string, 0, len(unresolved))
+		for name := range unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w: %s", ErrUnresolvedPlaceholder, strings.Join(names, ", "))
+	}
+	return c, nil
+}
+
+// expandPlaceholders replaces each {name} or {name:default} in s with the
+// value resolve returns. Placeholders resolve can't fill are left as-is.
+func expandPlaceholders(s string, resolve func(name, def string, hasDef bool) (string, bool)) string {
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		// Copy template actions ({{...}}) and ${...} through untouched.
+		if strings.HasPrefix(s[open:], "{{") || (open > 0 && s[open-1] == '$') {
+			end := strings.Index(s[open:], "}")
+			if end < 0 {
+				b.WriteString(s)
+				return b.String()
+			}
+			for end+open+1 < len(s) && s[open+end+1] == '}' {
+				end++
+			}
+			b.WriteString(s[:open+end+1])
+			s = s[open+end+1:]
+			continue
+		}
+
+		end := strings.IndexAny(s[open+1:], "{}")
+		if end < 0 || s[open+1+end] != '}' {
+			b.WriteString(s[:open+1])
+			s = s[open+1:]
+			continue
+		}
+		inner := s[open+1 : open+1+end]
+		name, def, hasDef := strings.Cut(inner, ":")
+		value, ok := "", false
+		if placeholderName.MatchString(name) {
+			value, ok = resolve(name, def, hasDef)
+		}
+		b.WriteString(s[:open])
+		if ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString("{" + inner + "}")
+		}
+		s = s[open+1+end+1:]
+	}
+}
@@ -0,0 +1,52 @@
+package ritual
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RequiredFor returns the minimal set of items that must run to complete
+// target: target itself plus everything it transitively needs, in
+// TopologicalSort order. Soft needs only order steps, so they are not
+// followed. For a raid or aspect ritual, "synthesis" requires the items it
+// depends on; any other item requires nothing but itself.
+//
+// Returns an error if target doesn't exist.
+func (f *Ritual) RequiredFor(target string) ([]string, error) {
+	isSynthesis := target == "synthesis" && f.Synthesis != nil
+	if !isSynthesis && !slices.Contains(f.GetAllIDs(), target) {
+		return nil, fmt.Errorf("unknown step: %s", target)
+	}
+
+	required := map[string]bool{target: true}
+	queue := []string{target}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		deps := f.GetDependencies(id)
+		if step := f.GetStep(id); step != nil && f.Type == TypeWorkflow {
+			deps = step.hardNeeds()
+		}
+		for _, dep := range deps {
+			if !required[dep] {
+				required[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, id := range order {
+		if required[id] {
+			result = append(result, id)
+		}
+	}
+	if isSynthesis {
+		result = append(result, target)
+	}
+	return result, nil
+}
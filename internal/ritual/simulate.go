@@ -0,0 +1,87 @@
+package ritual
+
+import "time"
+
+// SimulatedStep is one step's place in a simulated run. Times are offsets
+// from the start of the run.
+type SimulatedStep struct {
+	ID     string
+	Wave   int // 0-based index of the wave that dispatched the step
+	Worker int // 0-based worker slot, for drawing one Gantt row per worker
+	Start  time.Duration
+	Finish time.Duration
+}
+
+// SimulationResult is the schedule Simulate predicts.
+type SimulationResult struct {
+	Steps    []SimulatedStep // in dispatch order
+	Makespan time.Duration   // time until the last step finishes
+	Workers  int             // most steps running at once
+}
+
+// Simulate dry-runs the executor's scheduler with virtual time, assuming
+// every step succeeds and takes durations[id] (zero if absent). It dispatches
+// the same waves Execute would, one step per concurrency_group per wave and
+// at most ParallelLimit steps at a time, and each wave starts only when the
+// previous one has finished. Within a wave a step starts as soon as a worker
+// frees up, in wave order.
+//
+// Items that can never become ready, as in a cyclic ritual, are left out.
+func (e *Executor) Simulate(durations map[string]time.Duration) SimulationResult {
+	var result SimulationResult
+	completed := make(map[string]bool)
+
+	var now time.Duration
+	for waveIndex := 0; ; waveIndex++ {
+		var wave []string
+		for _, id := range e.Ritual.readyAfter(completed, completed) {
+			if !completed[id] {
+				wave = append(wave, id)
+			}
+		}
+		wave = e.Ritual.exclusive(wave, nil)
+		if len(wave) == 0 {
+			break
+		}
+
+		limit := e.ParallelLimit
+		if limit <= 0 || limit > len(wave) {
+			limit = len(wave)
+		}
+		if limit > result.Workers {
+			result.Workers = limit
+		}
+
+		// free[w] is when worker w can take its next step
+		free := make([]time.Duration, limit)
+		for i := range free {
+			free[i] = now
+		}
+		end := now
+		for _, id := range wave {
+			worker := 0
+			for w := range free {
+				if free[w] < free[worker] {
+					worker = w
+				}
+			}
+			step := SimulatedStep{
+				ID:     id,
+				Wave:   waveIndex,
+				Worker: worker,
+				Start:  free[worker],
+				Finish: free[worker] + durations[id],
+			}
+			free[worker] = step.Finish
+			if step.Finish > end {
+				end = step.Finish
+			}
+			result.Steps = append(result.Steps, step)
+			completed[id] = true
+		}
+		now = end
+	}
+
+	result.Makespan = now
+	return result
+}
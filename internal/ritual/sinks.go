@@ -0,0 +1,81 @@
+package ritual
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Sinks returns the items nothing depends on, in ritual order: the steps or
+// templates that end each branch of a workflow or expansion. For a raid or
+// aspect ritual with a synthesis, they are the synthesis plus any leg or
+// aspect its depends_on leaves out; without one, every leg or aspect is a
+// sink.
+func (f *Ritual) Sinks() []string {
+	ids := f.GetAllIDs()
+	depended := make(map[string]bool)
+	for _, id := range ids {
+		for _, dep := range f.GetDependencies(id) {
+			depended[dep] = true
+		}
+	}
+	for _, dep := range f.synthesisDeps() {
+		depended[dep] = true
+	}
+
+	var sinks []string
+	for _, id := range ids {
+		if !depended[id] {
+			sinks = append(sinks, id)
+		}
+	}
+	if f.hasSynthesisNode() {
+		sinks = append(sinks, synthesisNodeID)
+	}
+	return sinks
+}
+
+// hasSynthesisNode reports whether the ritual's graph includes a synthesis
+// node after its legs or aspects.
+func (f *Ritual) hasSynthesisNode() bool {
+	return f.Synthesis != nil && (f.Type == TypeRaid || f.Type == TypeAspect)
+}
+
+// synthesisDeps returns the legs or aspects the synthesis combines: the ones
+// its depends_on lists, or all of them if it lists none. It returns nil if
+// the ritual has no synthesis node.
+func (f *Ritual) synthesisDeps() []string {
+	if !f.hasSynthesisNode() {
+		return nil
+	}
+	if len(f.Synthesis.DependsOn) > 0 {
+		return f.Synthesis.DependsOn
+	}
+	return f.GetAllIDs()
+}
+
+// validateFinal checks the ritual's final item, if declared: it must exist
+// and be the only sink, so every other item feeds into it. Another sink is
+// usually a step that lost the dependency connecting it to the end of the
+// ritual.
+func (f *Ritual) validateFinal() error {
+	if f.Final == "" {
+		return nil
+	}
+	exists := slices.Contains(f.GetAllIDs(), f.Final) ||
+		(f.Final == synthesisNodeID && f.hasSynthesisNode())
+	if !exists {
+		return fmt.Errorf("final references unknown step: %s", f.Final)
+	}
+
+	var dangling []string
+	for _, id := range f.Sinks() {
+		if id != f.Final {
+			dangling = append(dangling, id)
+		}
+	}
+	if len(dangling) > 0 {
+		return fmt.Errorf("final step %q must be the only sink, but nothing depends on: %s", f.Final, strings.Join(dangling, ", "))
+	}
+	return nil
+}
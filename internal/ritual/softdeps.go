@@ -0,0 +1,252 @@
+package ritual
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Dependency types for workflow step needs.
+const (
+	// NeedHard is a prerequisite: the step runs only after the dependency
+	// completes. This is the default.
+	NeedHard = "hard"
+	// NeedSoft is an ordering hint: the step runs after the dependency has
+	// finished, whether or not it succeeded.
+	NeedSoft = "soft"
+)
+
+// NeedList is the needs field of a workflow step. In TOML each entry is
+// either a step ID, which is a hard dependency, or a table giving the
+// dependency type:
+//
+//	needs = ["build", {id = "lint", type = "soft"}]
+//
+// The list holds only IDs; soft entries are recorded in Step.SoftNeeds
+// during parsing.
+type NeedList []string
+
+// needEntry is a single needs entry as written in TOML.
+type needEntry struct {
+	ID   string
+	Type string
+}
+
+// UnmarshalTOML implements toml.Unmarshaler, accepting both entry forms.
+func (n *NeedList) UnmarshalTOML(data any) error {
+	entries, err := parseNeedEntries(data)
+	if err != nil {
+		return err
+	}
+	ids := make(NeedList, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	*n = ids
+	return nil
+}
+
+// parseNeedEntries decodes a raw TOML needs array, validating table entries.
+func parseNeedEntries(data any) ([]needEntry, error) {
+	raw, ok := data.([]any)
+	if !ok {
+		return nil, fmt.Errorf("needs must be an array, got %T", data)
+	}
+
+	entries := make([]needEntry, 0, len(raw))
+	for _, item := range raw {
+		switch v := item.(type) {
+		case string:
+			entries = append(entries, needEntry{ID: v, Type: NeedHard})
+		case map[string]any:
+			e := needEntry{Type: NeedHard}
+			for key, val := range v {
+				s, ok := val.(string)
+				if !ok {
+					return nil, fmt.Errorf("needs entry %s must be a string, got %T", key, val)
+				}
+				switch key {
+				case "id":
+					e.ID = s
+				case "type":
+					e.Type = s
+				default:
+					return nil, fmt.Errorf("needs entry has unknown key %q", key)
+				}
+			}
+			if strings.TrimSpace(e.ID) == "" {
+				return nil, fmt.Errorf("needs entry missing required id field")
+			}
+			if e.Type != NeedHard && e.Type != NeedSoft {
+				return nil, fmt.Errorf("needs entry %q has invalid type %q (want %s or %s)", e.ID, e.Type, NeedHard, NeedSoft)
+			}
+			entries = append(entries, e)
+		default:
+			return nil, fmt.Errorf("needs entry must be a string or table, got %T", item)
+		}
+	}
+	return entries, nil
+}
+
+// rawNeeds keeps needs entries with their types, for decodeSoftNeeds.
+type rawNeeds []needEntry
+
+// UnmarshalTOML implements toml.Unmarshaler.
+func (r *rawNeeds) UnmarshalTOML(data any) error {
+	entries, err := parseNeedEntries(data)
+	if err != nil {
+		return err
+	}
+	*r = entries
+	return nil
+}
+
+// softNeedsDoc is the part of a ritual definition decodeSoftNeeds reads.
+type softNeedsDoc struct {
+	Steps []struct {
+		Needs rawNeeds `toml:"needs"`
+	} `toml:"steps"`
+}
+
+// decodeSoftNeeds records the needs entries declared with type = "soft" in
+// each step's SoftNeeds. NeedList keeps only IDs, so the types are read in a
+// second pass over the same document.
+func (f *Ritual) decodeSoftNeeds(data string) error {
+	var doc softNeedsDoc
+	if _, err := toml.Decode(data, &doc); err != nil {
+		return err
+	}
+	return f.applySoftNeeds(doc)
+}
+
+// applySoftNeeds is the recording half of decodeSoftNeeds, for a definition
+// already decoded.
+func (f *Ritual) applySoftNeeds(doc softNeedsDoc) error {
+	for i, raw := range doc.Steps {
+		if i >= len(f.Steps) {
+			break
+		}
+		step := &f.Steps[i]
+
+		types := make(map[string]string, len(raw.Needs))
+		for _, e := range raw.Needs {
+			if prev, ok := types[e.ID]; ok && prev != e.Type {
+				return fmt.Errorf("step %q: %s listed as both a hard and a soft need", step.ID, e.ID)
+			}
+			types[e.ID] = e.Type
+		}
+		for _, e := range raw.Needs {
+			if e.Type == NeedSoft {
+				step.SoftNeeds = mergeRefs(step.SoftNeeds, []string{e.ID})
+			}
+		}
+	}
+	return nil
+}
+
+// NeedType returns NeedSoft if need is one of the step's soft dependencies,
+// and NeedHard otherwise.
+func (s *Step) NeedType(need string) string {
+	for _, soft := range s.SoftNeeds {
+		if soft == need {
+			return NeedSoft
+		}
+	}
+	return NeedHard
+}
+
+// hardNeeds returns the step's needs that are not soft.
+func (s *Step) hardNeeds() []string {
+	var hard []string
+	for _, need := range s.Needs {
+		if s.NeedType(need) == NeedHard {
+			hard = append(hard, need)
+		}
+	}
+	return hard
+}
+
+// readyAfter is ReadySteps for an execution in which steps can fail.
+// completed holds the steps whose dependents may proceed; settled holds the
+// steps that won't run (again), whether they succeeded, failed, or were
+// blocked. A hard need must be completed; a soft need only settled.
+// Non-workflow rituals have no soft needs and fall back to ReadySteps.
+func (f *Ritual) readyAfter(completed, settled map[string]bool) []string {
+	if f.Type != TypeWorkflow {
+		return f.ReadySteps(completed)
+	}
+
+	var ready []string
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if settled[step.ID] {
+			continue
+		}
+		allMet := true
+		for _, need := range step.Needs {
+			met := completed[need]
+			if step.NeedType(need) == NeedSoft {
+				met = settled[need]
+			}
+			if !met {
+				allMet = false
+				break
+			}
+		}
+		if allMet {
+			ready = append(ready, step.ID)
+		}
+	}
+	return ready
+}
+
+// settleBlocked marks as settled every unsettled step that can never run
+// because a hard need settled without completing. It repeats until nothing
+// changes, so blocking propagates down chains; a soft dependent of a blocked
+// step is then released by readyAfter.
+func (f *Ritual) settleBlocked(completed, settled map[string]bool) {
+	for changed := true; changed; {
+		changed = false
+		for i := range f.Steps {
+			step := &f.Steps[i]
+			if settled[step.ID] {
+				continue
+			}
+			for _, need := range step.hardNeeds() {
+				if settled[need] && !completed[need] {
+					settled[step.ID] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// SkippedGiven returns the steps that can never run once the steps in failed
+// have failed, in declaration order: every step with a hard need on a failed
+// required step, directly or through other skipped steps. Failed optional
+// steps and soft needs don't propagate, matching the Executor under
+// FailContinue.
+//
+// Only workflow steps have dependencies; other ritual types return nil.
+func (f *Ritual) SkippedGiven(failed map[string]bool) []string {
+	completed := make(map[string]bool)
+	settled := make(map[string]bool, len(failed))
+	for id := range failed {
+		settled[id] = true
+		if f.IsOptional(id) {
+			completed[id] = true
+		}
+	}
+	f.settleBlocked(completed, settled)
+
+	var skipped []string
+	for _, step := range f.Steps {
+		if settled[step.ID] && !failed[step.ID] {
+			skipped = append(skipped, step.ID)
+		}
+	}
+	return skipped
+}
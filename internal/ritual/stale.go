@@ -0,0 +1,54 @@
+package ritual
+
+import "slices"
+
+// Invalidates returns the items that become stale if id is re-run: every
+// item that transitively hard-needs it, in TopologicalSort order, without
+// id itself. When resuming a partial run, drop these from the completed set
+// along with id before calling ReadySteps, so that work built on the old
+// result runs again. Soft needs only order steps and don't consume the
+// result, so they are not followed. For a raid or aspect ritual, re-running
+// a leg or aspect the synthesis combines invalidates "synthesis".
+//
+// Returns nil if id doesn't exist.
+func (f *Ritual) Invalidates(id string) []string {
+	ids := f.GetAllIDs()
+	if !slices.Contains(ids, id) {
+		return nil
+	}
+
+	stale := make(map[string]bool)
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, other := range ids {
+			if other == id || stale[other] {
+				continue
+			}
+			deps := f.GetDependencies(other)
+			if step := f.GetStep(other); step != nil && f.Type == TypeWorkflow {
+				deps = step.hardNeeds()
+			}
+			if slices.Contains(deps, current) {
+				stale[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return nil
+	}
+	var result []string
+	for _, other := range order {
+		if stale[other] {
+			result = append(result, other)
+		}
+	}
+	if slices.Contains(f.synthesisDeps(), id) {
+		result = append(result, synthesisNodeID)
+	}
+	return result
+}
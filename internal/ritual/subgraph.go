@@ -0,0 +1,72 @@
+package ritual
+
+// Subgraph returns a copy of the ritual reduced to target and the items it
+// requires (see RequiredFor), e.g. to draw or inspect one slice of a large
+// workflow. Needs on removed items, which can only be soft needs, are
+// dropped, as are groups, and final unless it is kept. A raid or aspect
+// ritual keeps its synthesis only when it is the target.
+//
+// Returns an error if target doesn't exist.
+func (f *Ritual) Subgraph(target string) (*Ritual, error) {
+	required, err := f.RequiredFor(target)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(required))
+	for _, id := range required {
+		keep[id] = true
+	}
+	kept := func(refs []string) []string {
+		var out []string
+		for _, ref := range refs {
+			if keep[ref] {
+				out = append(out, ref)
+			}
+		}
+		return out
+	}
+
+	g := f.clone()
+	g.Groups = nil
+	if !keep[g.Final] {
+		g.Final = ""
+	}
+
+	steps := g.Steps
+	g.Steps = nil
+	for _, step := range steps {
+		if keep[step.ID] {
+			step.Needs = kept(step.Needs)
+			step.SoftNeeds = kept(step.SoftNeeds)
+			step.DependsOn = kept(step.DependsOn)
+			g.Steps = append(g.Steps, step)
+		}
+	}
+	templates := g.Template
+	g.Template = nil
+	for _, tmpl := range templates {
+		if keep[tmpl.ID] {
+			tmpl.Needs = kept(tmpl.Needs)
+			tmpl.DependsOn = kept(tmpl.DependsOn)
+			g.Template = append(g.Template, tmpl)
+		}
+	}
+	legs := g.Legs
+	g.Legs = nil
+	for _, leg := range legs {
+		if keep[leg.ID] {
+			g.Legs = append(g.Legs, leg)
+		}
+	}
+	aspects := g.Aspects
+	g.Aspects = nil
+	for _, aspect := range aspects {
+		if keep[aspect.ID] {
+			g.Aspects = append(g.Aspects, aspect)
+		}
+	}
+	if !keep[synthesisNodeID] {
+		g.Synthesis = nil
+	}
+	return g, nil
+}
@@ -0,0 +1,45 @@
+package ritual
+
+import "fmt"
+
+// Synthesis modes. The ritual package only records the mode; the executor
+// running the synthesis step does the aggregation.
+const (
+	// SynthesisCombine merges all leg or aspect results into one. This is
+	// the default.
+	SynthesisCombine = "combine"
+	// SynthesisGroupBy buckets results by the value of the synthesis key
+	// (e.g., severity) before combining each bucket.
+	SynthesisGroupBy = "group_by"
+)
+
+// SynthesisMode returns how the ritual's synthesis aggregates results and,
+// for SynthesisGroupBy, the key to group by. It returns empty strings if the
+// ritual has no synthesis.
+func (f *Ritual) SynthesisMode() (mode, key string) {
+	if f.Synthesis == nil {
+		return "", ""
+	}
+	if f.Synthesis.Mode == "" {
+		return SynthesisCombine, ""
+	}
+	return f.Synthesis.Mode, f.Synthesis.Key
+}
+
+// validateMode checks that the mode is known and that a key is given
+// exactly when the mode needs one.
+func (s *Synthesis) validateMode() error {
+	switch s.Mode {
+	case "", SynthesisCombine:
+		if s.Key != "" {
+			return fmt.Errorf("synthesis key %q requires mode = %q", s.Key, SynthesisGroupBy)
+		}
+	case SynthesisGroupBy:
+		if s.Key == "" {
+			return fmt.Errorf("synthesis mode %q requires a key", SynthesisGroupBy)
+		}
+	default:
+		return fmt.Errorf("synthesis has invalid mode %q (want %s or %s)", s.Mode, SynthesisCombine, SynthesisGroupBy)
+	}
+	return nil
+}
@@ -25,89 +25,159 @@ const (
 // Ritual represents a parsed ritual.toml file.
 type Ritual struct {
 	// Common fields
-	Name        string      `toml:"ritual"`
-	Description string      `toml:"description"`
-	Type        FormulaType `toml:"type"`
-	Version     int         `toml:"version"`
-
-	// Raid-specific
-	Inputs    map[string]Input `toml:"inputs"`
-	Prompts   map[string]string `toml:"prompts"`
-	Output    *Output           `toml:"output"`
-	Legs      []Leg             `toml:"legs"`
-	Synthesis *Synthesis        `toml:"synthesis"`
+	Name        string      `toml:"ritual,omitempty"`
+	Description string      `toml:"description,omitempty"`
+	Type        FormulaType `toml:"type,omitempty"`
+	Version     int         `toml:"version,omitempty"`
+
+	// Final, if set, names the step (or "synthesis") the ritual must end
+	// in: every other item has to feed into it. See Sinks.
+	Final string `toml:"final,omitempty"`
+
+	// Raid-specific (Synthesis is also used by aspect rituals)
+	Inputs    map[string]Input `toml:"inputs,omitempty"`
+	Prompts   map[string]string `toml:"prompts,omitempty"`
+	Output    *Output           `toml:"output,omitempty"`
+	Legs      []Leg             `toml:"legs,omitempty"`
+	Synthesis *Synthesis        `toml:"synthesis,omitempty"`
 
 	// Workflow-specific
-	Steps []Step           `toml:"steps"`
-	Vars  map[string]Var   `toml:"vars"`
+	Vars   map[string]Var `toml:"vars,omitempty"`
+	Groups []Group        `toml:"groups,omitempty"`
+	Steps  []Step         `toml:"steps,omitempty"`
 
 	// Expansion-specific
-	Template []Template `toml:"template"`
+	Template []Template `toml:"template,omitempty"`
 
-	// Aspect-specific (similar to raid but for analysis)
-	Aspects []Aspect `toml:"aspects"`
+	// Aspect-specific (similar to raid but for analysis).
+	// Aspects may be combined by an optional [synthesis] section whose
+	// depends_on lists aspect IDs.
+	Aspects []Aspect `toml:"aspects,omitempty"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect ritual.
+// ID, Title, and Focus are required.
 type Aspect struct {
-	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	ID          string `toml:"id,omitempty"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Input represents an input parameter for a ritual.
 type Input struct {
-	Description    string   `toml:"description"`
-	Type           string   `toml:"type"`
-	Required       bool     `toml:"required"`
-	RequiredUnless []string `toml:"required_unless"`
-	Default        string   `toml:"default"`
+	Description    string   `toml:"description,omitempty"`
+	Type           string   `toml:"type,omitempty"`
+	Required       bool     `toml:"required,omitempty"`
+	RequiredUnless []string `toml:"required_unless,omitempty"`
+	Default        string   `toml:"default,omitempty"`
 }
 
 // Output configures where ritual outputs are written.
 type Output struct {
-	Directory  string `toml:"directory"`
-	LegPattern string `toml:"leg_pattern"`
-	Synthesis  string `toml:"synthesis"`
+	Directory  string `toml:"directory,omitempty"`
+	LegPattern string `toml:"leg_pattern,omitempty"`
+	Synthesis  string `toml:"synthesis,omitempty"`
 }
 
 // Leg represents a parallel execution unit in a raid ritual.
 type Leg struct {
-	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	ID          string `toml:"id,omitempty"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Synthesis represents the synthesis step that combines leg outputs.
 type Synthesis struct {
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	DependsOn   []string `toml:"depends_on"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	DependsOn   []string `toml:"depends_on,omitempty"`
+
+	// Needs is accepted as an alias for DependsOn and merged into it
+	// during parsing.
+	Needs []string `toml:"needs,omitempty"`
+
+	// Mode says how the synthesis aggregates results: SynthesisCombine
+	// (the default) or SynthesisGroupBy, which buckets them by Key.
+	// See Ritual.SynthesisMode.
+	Mode string `toml:"mode,omitempty"`
+	Key  string `toml:"key,omitempty"`
 }
 
 // Step represents a sequential step in a workflow ritual.
 type Step struct {
-	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	ID          string   `toml:"id,omitempty"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       NeedList `toml:"needs,omitempty"`
+
+	// SoftNeeds lists the entries of Needs that are soft (ordering-only)
+	// dependencies; see NeedList. Listing an ID here also adds it to Needs.
+	SoftNeeds []string `toml:"soft_needs,omitempty"`
+
+	// DependsOn is accepted as an alias for Needs and merged into it
+	// during parsing.
+	DependsOn []string `toml:"depends_on,omitempty"`
+
+	// Produces and Consumes declare named artifacts. A step that consumes an
+	// artifact implicitly needs every step that produces it; see
+	// ResolveArtifacts.
+	Produces []string `toml:"produces,omitempty"`
+	Consumes []string `toml:"consumes,omitempty"`
+
+	// Optional marks a step whose failure doesn't block its dependents.
+	// The Executor still reports it as failed.
+	Optional bool `toml:"optional,omitempty"`
+
+	// Env is extra environment for the step, merged over the base
+	// environment when it is dispatched. See StepEnv.
+	Env map[string]string `toml:"env,omitempty"`
+
+	// ConcurrencyGroup names a shared resource the step holds while it
+	// runs. Steps in the same group never run at the same time, even when
+	// no needs order them. See SchedulableSteps.
+	ConcurrencyGroup string `toml:"concurrency_group,omitempty"`
+
+	// Cwd is the directory the step's command runs in, relative to the
+	// execution root; it may not escape it. Shell is the interpreter the
+	// command runs under (default "sh"). See StepDir and StepShell.
+	Cwd   string `toml:"cwd,omitempty"`
+	Shell string `toml:"shell,omitempty"`
+}
+
+// Group is a named set of steps that can be referenced as a unit in needs
+// (e.g., needs = ["group:build"] means "all steps in the build group").
+// Members are step IDs or other groups ("group:<id>").
+type Group struct {
+	ID      string   `toml:"id,omitempty"`
+	Title   string   `toml:"title,omitempty"`
+	Members []string `toml:"members,omitempty"`
 }
 
 // Template represents a template step in an expansion ritual.
 type Template struct {
-	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	ID          string   `toml:"id,omitempty"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
+
+	// DependsOn is accepted as an alias for Needs and merged into it
+	// during parsing.
+	DependsOn []string `toml:"depends_on,omitempty"`
 }
 
 // Var represents a variable definition for rituals.
 type Var struct {
-	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Description string `toml:"description,omitempty"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
+}
+
+// IsOptional returns true if id names a workflow step marked optional.
+func (f *Ritual) IsOptional(id string) bool {
+	step := f.GetStep(id)
+	return step != nil && step.Optional
 }
 
 // IsValid returns true if the ritual type is recognized.
@@ -137,8 +207,8 @@ func (f *Ritual) GetDependencies(id string) []string {
 				return tmpl.Needs
 			}
 		}
-	case TypeRaid:
-		// Legs are parallel; synthesis depends on all legs
+	case TypeRaid, TypeAspect:
+		// Legs/aspects are parallel; synthesis depends on them
 		if f.Synthesis != nil && id == "synthesis" {
 			return f.Synthesis.DependsOn
 		}
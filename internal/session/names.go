@@ -46,6 +46,15 @@ func RaiderSessionName(warband, name string) string {
 	return fmt.Sprintf("%s%s-%s", Prefix, warband, name)
 }
 
+// SessionName returns the session name for any agent: warchief and shaman
+// ignore warband and name, witness and forge ignore name, and clan and
+// raider use both. It returns "" for an unknown role. ParseSessionName is
+// the inverse.
+func SessionName(role, warband, name string) string {
+	id := AgentIdentity{Role: Role(role), Warband: warband, Name: name}
+	return id.SessionName()
+}
+
 // PropulsionNudge generates the GUPP (Horde Universal Propulsion Principle) signal.
 // This is sent after the beacon to trigger autonomous work execution.
 // The agent receives this as user input, triggering the propulsion principle:
@@ -169,3 +169,37 @@ func TestPropulsionNudgeForRole_AllRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionName(t *testing.T) {
+	tests := []struct {
+		role, warband, name string
+		want                string
+	}{
+		{"warchief", "horde", "max", "hq-warchief"},
+		{"shaman", "", "", "hq-shaman"},
+		{"witness", "horde", "ignored", "hd-horde-witness"},
+		{"forge", "relics", "", "hd-relics-forge"},
+		{"clan", "horde", "max", "hd-horde-clan-max"},
+		{"raider", "horde", "Toast", "hd-horde-Toast"},
+		{"unknown", "horde", "x", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			got := SessionName(tt.role, tt.warband, tt.name)
+			if got != tt.want {
+				t.Errorf("SessionName(%q, %q, %q) = %q, want %q", tt.role, tt.warband, tt.name, got, tt.want)
+			}
+			if got == "" {
+				return
+			}
+			// ParseSessionName is the inverse
+			id, err := ParseSessionName(got)
+			if err != nil {
+				t.Fatalf("ParseSessionName(%q): %v", got, err)
+			}
+			if id.SessionName() != got || string(id.Role) != tt.role {
+				t.Errorf("ParseSessionName(%q) = %+v, does not round-trip", got, id)
+			}
+		})
+	}
+}
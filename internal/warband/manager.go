@@ -201,7 +201,93 @@ func resolveLocalRepo(path, gitURL string) (string, string) {
 	return absPath, ""
 }
 
+// AddRigReport records which AddRig steps were performed and which were
+// skipped because an earlier, interrupted run had already completed them.
+type AddRigReport struct {
+	Resumed   bool     // An existing partially-created warband was found
+	Performed []string // Steps run by this invocation
+	Skipped   []string // Steps already complete and left untouched
+}
+
+// Perform records a step as performed.
+func (r *AddRigReport) Perform(step string) {
+	r.Performed = append(r.Performed, step)
+}
+
+// Skip records a step as skipped.
+func (r *AddRigReport) Skip(step string) {
+	r.Skipped = append(r.Skipped, step)
+}
+
+// isGitCheckout reports whether path is the root of a usable git clone or
+// worktree. The .git check keeps rev-parse from matching an enclosing repo.
+func isGitCheckout(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return false
+	}
+	return git.NewGit(path).IsRepo()
+}
+
+// RelicsLocation returns where a warband's relics database lives: the path
+// routes.jsonl records for it (relative to the encampment root) and the
+// working directory for rl commands against it. A source repo that tracks
+// .relics/ keeps its database in the warchief clone; otherwise it is the one
+// initRelics creates at the warband root.
+func RelicsLocation(townRoot, name string) (routePath, workDir string) {
+	warchiefRigPath := filepath.Join(townRoot, name, "warchief", "warband")
+	if _, err := os.Stat(filepath.Join(warchiefRigPath, ".relics")); err == nil {
+		return name + "/warchief/warband", warchiefRigPath
+	}
+	return name, filepath.Join(townRoot, name)
+}
+
+// missingRigSteps lists the setup steps an existing warband directory still
+// lacks, covering both AddRig's own steps and the route and identity bead
+// that hd warband add creates afterwards. An empty result means the warband
+// was fully created.
+func (m *Manager) missingRigSteps(name, rigPath string, cfg *RigConfig) []string {
+	var missing []string
+	if !m.RigExists(name) {
+		missing = append(missing, "registration")
+	}
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); err != nil || !git.NewGitWithDir(bareRepoPath, "").IsRepo() {
+		missing = append(missing, "bare repo")
+	}
+	if !isGitCheckout(filepath.Join(rigPath, "warchief", "warband")) {
+		missing = append(missing, "warchief clone")
+	}
+	if !isGitCheckout(filepath.Join(rigPath, "forge", "warband")) {
+		missing = append(missing, "forge worktree")
+	}
+
+	var prefix string
+	if cfg.Relics != nil {
+		prefix = cfg.Relics.Prefix
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, ".relics")); err != nil || prefix == "" {
+		// Without a relics database there is no route or identity bead to check.
+		return append(missing, "relics init")
+	}
+
+	routePath, workDir := RelicsLocation(m.townRoot, name)
+	if !relics.RouteExists(m.townRoot, relics.Route{Prefix: prefix + "-", Path: routePath}) {
+		missing = append(missing, "route")
+	}
+	if _, err := relics.New(workDir).Show(relics.RigBeadIDWithPrefix(prefix, name)); err != nil {
+		missing = append(missing, "identity bead")
+	}
+	return missing
+}
+
 // AddRig creates a new warband as a container with clones for each agent.
+// See AddRigWithReport for resume behavior.
+func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
+	r, _, err := m.AddRigWithReport(opts)
+	return r, err
+}
+
+// AddRigWithReport creates a new warband as a container with clones for each agent.
 // The warband structure is:
 //
 //	<name>/                    # Container (NOT a git clone)
@@ -212,30 +298,58 @@ func resolveLocalRepo(path, gitURL string) (string, string) {
 //	├── witness/               # Witness agent (no clone)
 //	├── raiders/              # Worker directories (empty)
 //	└── clan/<clan>/           # Default human workspace
-func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
-	if m.RigExists(opts.Name) {
-		return nil, ErrRigExists
-	}
+//
+// If the directory already holds a warband for the same repository that is
+// unregistered or missing a setup step (e.g. a previous run failed partway),
+// the missing steps are resumed: existing clones and worktrees are kept, and
+// the remaining setup is re-applied idempotently. The returned report lists
+// performed vs. skipped steps. A fully created warband returns ErrRigExists.
+func (m *Manager) AddRigWithReport(opts AddRigOptions) (*Warband, *AddRigReport, error) {
+	report := &AddRigReport{}
 
 	// Validate warband name: reject characters that break agent ID parsing
 	// Agent IDs use format <prefix>-<warband>-<role>[-<name>] with hyphens as delimiters
 	if strings.ContainsAny(opts.Name, "-. ") {
 		sanitized := strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(opts.Name)
 		sanitized = strings.ToLower(sanitized)
-		return nil, fmt.Errorf("warband name %q contains invalid characters; hyphens, dots, and spaces are reserved for agent ID parsing. Try %q instead (underscores are allowed)", opts.Name, sanitized)
+		return nil, nil, fmt.Errorf("warband name %q contains invalid characters; hyphens, dots, and spaces are reserved for agent ID parsing. Try %q instead (underscores are allowed)", opts.Name, sanitized)
 	}
 
 	rigPath := filepath.Join(m.townRoot, opts.Name)
 
-	// Check if directory already exists
+	// An existing directory is only acceptable if it is a partially-created
+	// warband for the same repository; anything else, including a complete
+	// warband, is left alone.
+	var existing *RigConfig
 	if _, err := os.Stat(rigPath); err == nil {
-		return nil, fmt.Errorf("directory already exists: %s", rigPath)
+		cfg, cfgErr := LoadRigConfig(rigPath)
+		if cfgErr != nil {
+			return nil, nil, fmt.Errorf("directory already exists: %s", rigPath)
+		}
+		if cfg.GitURL != opts.GitURL {
+			return nil, nil, fmt.Errorf("directory already exists: %s (configured for %s)", rigPath, cfg.GitURL)
+		}
+		missing := m.missingRigSteps(opts.Name, rigPath, cfg)
+		if len(missing) == 0 {
+			return nil, nil, ErrRigExists
+		}
+		existing = cfg
+		report.Resumed = true
+		fmt.Printf("  Resuming partially-created warband at %s (missing: %s)\n", rigPath, strings.Join(missing, ", "))
+	} else if m.RigExists(opts.Name) {
+		return nil, nil, ErrRigExists
 	}
 
 	// Track whether user explicitly provided --prefix (before deriving)
 	userProvidedPrefix := opts.RelicsPrefix != ""
 
-	// Derive defaults
+	// Derive defaults, preferring what an earlier run already recorded
+	if existing != nil && existing.Relics != nil && existing.Relics.Prefix != "" {
+		if userProvidedPrefix && opts.RelicsPrefix != existing.Relics.Prefix {
+			return nil, nil, fmt.Errorf("prefix mismatch: existing warband uses '%s' but --prefix '%s' was provided", existing.Relics.Prefix, opts.RelicsPrefix)
+		}
+		opts.RelicsPrefix = existing.Relics.Prefix
+	}
 	if opts.RelicsPrefix == "" {
 		opts.RelicsPrefix = deriveRelicsPrefix(opts.Name)
 	}
@@ -247,59 +361,80 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 
 	// Create container directory
 	if err := os.MkdirAll(rigPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating warband directory: %w", err)
+		return nil, nil, fmt.Errorf("creating warband directory: %w", err)
 	}
 
-	// Track cleanup on failure (best-effort cleanup)
+	// Track cleanup on failure (best-effort cleanup). A resumed warband is
+	// never removed: it holds work from the earlier run.
 	cleanup := func() { _ = os.RemoveAll(rigPath) }
 	success := false
 	defer func() {
-		if !success {
+		if !success && !report.Resumed {
 			cleanup()
 		}
 	}()
 
-	// Create warband config
-	rigConfig := &RigConfig{
-		Type:      "warband",
-		Version:   CurrentRigConfigVersion,
-		Name:      opts.Name,
-		GitURL:    opts.GitURL,
-		LocalRepo: localRepo,
-		CreatedAt: time.Now(),
-		Relics: &RelicsConfig{
-			Prefix: opts.RelicsPrefix,
-		},
+	// Create warband config (or reuse the one from an earlier run)
+	rigConfig := existing
+	if rigConfig != nil {
+		if rigConfig.Relics == nil {
+			rigConfig.Relics = &RelicsConfig{}
+		}
+		rigConfig.Relics.Prefix = opts.RelicsPrefix
+		report.Skip("config")
+	} else {
+		rigConfig = &RigConfig{
+			Type:      "warband",
+			Version:   CurrentRigConfigVersion,
+			Name:      opts.Name,
+			GitURL:    opts.GitURL,
+			LocalRepo: localRepo,
+			CreatedAt: time.Now(),
+			Relics: &RelicsConfig{
+				Prefix: opts.RelicsPrefix,
+			},
+		}
+		report.Perform("config")
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("saving warband config: %w", err)
+		return nil, nil, fmt.Errorf("saving warband config: %w", err)
 	}
 
 	// Create shared bare repo as source of truth for forge and raiders.
 	// This allows forge to see raider branches without pushing to remote.
 	// Warchief remains a separate clone (doesn't need branch visibility).
-	fmt.Printf("  Cloning repository (this may take a moment)...\n")
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
-	if localRepo != "" {
-		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
-			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
-			_ = os.RemoveAll(bareRepoPath)
+	bareGit := git.NewGitWithDir(bareRepoPath, "")
+	if _, err := os.Stat(bareRepoPath); err == nil && bareGit.IsRepo() {
+		fmt.Printf("   ✓ Reusing existing shared bare repo\n")
+		report.Skip("bare repo")
+	} else {
+		fmt.Printf("  Cloning repository (this may take a moment)...\n")
+		_ = os.RemoveAll(bareRepoPath) // Discard any incomplete clone
+		if localRepo != "" {
+			if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
+				fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
+				_ = os.RemoveAll(bareRepoPath)
+				if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+					return nil, nil, fmt.Errorf("creating bare repo: %w", err)
+				}
+			}
+		} else {
 			if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-				return nil, fmt.Errorf("creating bare repo: %w", err)
+				return nil, nil, fmt.Errorf("creating bare repo: %w", err)
 			}
 		}
-	} else {
-		if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-			return nil, fmt.Errorf("creating bare repo: %w", err)
-		}
+		fmt.Printf("   ✓ Created shared bare repo\n")
+		report.Perform("bare repo")
 	}
-	fmt.Printf("   ✓ Created shared bare repo\n")
-	bareGit := git.NewGitWithDir(bareRepoPath, "")
 
-	// Determine default branch: use provided value or auto-detect from remote
+	// Determine default branch: use provided value, the value recorded by an
+	// earlier run, or auto-detect from remote
 	var defaultBranch string
 	if opts.DefaultBranch != "" {
 		defaultBranch = opts.DefaultBranch
+	} else if rigConfig.DefaultBranch != "" {
+		defaultBranch = rigConfig.DefaultBranch
 	} else {
 		// Try to get default branch from remote first, fall back to local detection
 		defaultBranch = bareGit.RemoteDefaultBranch()
@@ -310,37 +445,44 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 	rigConfig.DefaultBranch = defaultBranch
 	// Re-save config with default branch
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("updating warband config with default branch: %w", err)
+		return nil, nil, fmt.Errorf("updating warband config with default branch: %w", err)
 	}
 
 	// Create warchief as regular clone (separate from bare repo).
 	// Warchief doesn't need to see raider branches - that's forge's job.
 	// This also allows warchief to stay on the default branch without conflicting with forge.
-	fmt.Printf("  Creating warchief clone...\n")
 	warchiefRigPath := filepath.Join(rigPath, "warchief", "warband")
-	if err := os.MkdirAll(filepath.Dir(warchiefRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating warchief dir: %w", err)
-	}
-	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, warchiefRigPath, localRepo); err != nil {
-			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
-			_ = os.RemoveAll(warchiefRigPath)
+	if isGitCheckout(warchiefRigPath) {
+		fmt.Printf("   ✓ Reusing existing warchief clone\n")
+		report.Skip("warchief clone")
+	} else {
+		fmt.Printf("  Creating warchief clone...\n")
+		if err := os.MkdirAll(filepath.Dir(warchiefRigPath), 0755); err != nil {
+			return nil, nil, fmt.Errorf("creating warchief dir: %w", err)
+		}
+		_ = os.RemoveAll(warchiefRigPath) // Discard any incomplete clone
+		if localRepo != "" {
+			if err := m.git.CloneWithReference(opts.GitURL, warchiefRigPath, localRepo); err != nil {
+				fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
+				_ = os.RemoveAll(warchiefRigPath)
+				if err := m.git.Clone(opts.GitURL, warchiefRigPath); err != nil {
+					return nil, nil, fmt.Errorf("cloning for warchief: %w", err)
+				}
+			}
+		} else {
 			if err := m.git.Clone(opts.GitURL, warchiefRigPath); err != nil {
-				return nil, fmt.Errorf("cloning for warchief: %w", err)
+				return nil, nil, fmt.Errorf("cloning for warchief: %w", err)
 			}
 		}
-	} else {
-		if err := m.git.Clone(opts.GitURL, warchiefRigPath); err != nil {
-			return nil, fmt.Errorf("cloning for warchief: %w", err)
-		}
-	}
 
-	// Checkout the default branch for warchief (clone defaults to remote's HEAD, not our configured branch)
-	warchiefGit := git.NewGitWithDir("", warchiefRigPath)
-	if err := warchiefGit.Checkout(defaultBranch); err != nil {
-		return nil, fmt.Errorf("checking out default branch for warchief: %w", err)
+		// Checkout the default branch for warchief (clone defaults to remote's HEAD, not our configured branch)
+		warchiefGit := git.NewGitWithDir("", warchiefRigPath)
+		if err := warchiefGit.Checkout(defaultBranch); err != nil {
+			return nil, nil, fmt.Errorf("checking out default branch for warchief: %w", err)
+		}
+		fmt.Printf("   ✓ Created warchief clone\n")
+		report.Perform("warchief clone")
 	}
-	fmt.Printf("   ✓ Created warchief clone\n")
 
 	// Check if source repo has tracked .relics/ directory.
 	// If so, we need to initialize the database (relics.db is gitignored so it doesn't exist after clone).
@@ -353,14 +495,14 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 			fmt.Printf("  Detected existing relics prefix '%s' from source repo\n", sourcePrefix)
 			// Only error on mismatch if user explicitly provided --prefix
 			if userProvidedPrefix && opts.RelicsPrefix != sourcePrefix {
-				return nil, fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, opts.RelicsPrefix, sourcePrefix)
+				return nil, nil, fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, opts.RelicsPrefix, sourcePrefix)
 			}
 			// Use detected prefix (overrides derived prefix)
 			opts.RelicsPrefix = sourcePrefix
 			rigConfig.Relics.Prefix = sourcePrefix
 			// Re-save warband config with detected prefix
 			if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-				return nil, fmt.Errorf("updating warband config with detected prefix: %w", err)
+				return nil, nil, fmt.Errorf("updating warband config with detected prefix: %w", err)
 			}
 		} else {
 			// Detection failed (no issues yet) - use derived/provided prefix
@@ -385,14 +527,14 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 
 	// Create warchief CLAUDE.md (overrides any from cloned repo)
 	if err := m.createRoleCLAUDEmd(warchiefRigPath, "warchief", opts.Name, ""); err != nil {
-		return nil, fmt.Errorf("creating warchief CLAUDE.md: %w", err)
+		return nil, nil, fmt.Errorf("creating warchief CLAUDE.md: %w", err)
 	}
 
 	// Initialize relics at warband level BEFORE creating worktrees.
 	// This ensures warband/.relics exists so worktree redirects can point to it.
 	fmt.Printf("  Initializing relics database...\n")
 	if err := m.initRelics(rigPath, opts.RelicsPrefix); err != nil {
-		return nil, fmt.Errorf("initializing relics: %w", err)
+		return nil, nil, fmt.Errorf("initializing relics: %w", err)
 	}
 	fmt.Printf("   ✓ Initialized relics (prefix: %s)\n", opts.RelicsPrefix)
 
@@ -408,22 +550,31 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 	// Create forge as worktree from bare repo on default branch.
 	// Forge needs to see raider branches (shared .repo.git) and merges them.
 	// Being on the default branch allows direct merge workflow.
-	fmt.Printf("  Creating forge worktree...\n")
 	forgeRigPath := filepath.Join(rigPath, "forge", "warband")
-	if err := os.MkdirAll(filepath.Dir(forgeRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating forge dir: %w", err)
-	}
-	if err := bareGit.WorktreeAddExisting(forgeRigPath, defaultBranch); err != nil {
-		return nil, fmt.Errorf("creating forge worktree: %w", err)
+	if isGitCheckout(forgeRigPath) {
+		fmt.Printf("   ✓ Reusing existing forge worktree\n")
+		report.Skip("forge worktree")
+	} else {
+		fmt.Printf("  Creating forge worktree...\n")
+		if err := os.MkdirAll(filepath.Dir(forgeRigPath), 0755); err != nil {
+			return nil, nil, fmt.Errorf("creating forge dir: %w", err)
+		}
+		// Drop any stale worktree registration left by an interrupted run
+		_ = os.RemoveAll(forgeRigPath)
+		_ = bareGit.WorktreePrune()
+		if err := bareGit.WorktreeAddExisting(forgeRigPath, defaultBranch); err != nil {
+			return nil, nil, fmt.Errorf("creating forge worktree: %w", err)
+		}
+		fmt.Printf("   ✓ Created forge worktree\n")
+		report.Perform("forge worktree")
 	}
-	fmt.Printf("   ✓ Created forge worktree\n")
 	// Set up relics redirect for forge (points to warband-level .relics)
 	if err := relics.SetupRedirect(m.townRoot, forgeRigPath); err != nil {
 		fmt.Printf("  Warning: Could not set up forge relics redirect: %v\n", err)
 	}
 	// Create forge CLAUDE.md (overrides any from cloned repo)
 	if err := m.createRoleCLAUDEmd(forgeRigPath, "forge", opts.Name, ""); err != nil {
-		return nil, fmt.Errorf("creating forge CLAUDE.md: %w", err)
+		return nil, nil, fmt.Errorf("creating forge CLAUDE.md: %w", err)
 	}
 	// Create forge hooks for scout triggering (at forge/ level, not warband/)
 	forgePath := filepath.Dir(forgeRigPath)
@@ -435,7 +586,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Warband, error) {
 	// Create empty clan directory with README (clan members added via hd clan add)
 	crewPath := filepath.Join(rigPath, "clan")
 	if err := os.MkdirAll(crewPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating clan dir: %w", err)
+		return nil, nil, fmt.Errorf("creating clan dir: %w", err)
 	}
 	// Create README with instructions
 	readmePath := filepath.Join(crewPath, "README.md")
@@ -457,13 +608,13 @@ gt clan add <name>    # Creates clan/<name>/ with a git clone
 Use clan for your own workspace. Raiders are for batch work dispatch.
 `
 	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
-		return nil, fmt.Errorf("creating clan README: %w", err)
+		return nil, nil, fmt.Errorf("creating clan README: %w", err)
 	}
 
 	// Create witness directory (no clone needed)
 	witnessPath := filepath.Join(rigPath, "witness")
 	if err := os.MkdirAll(witnessPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating witness dir: %w", err)
+		return nil, nil, fmt.Errorf("creating witness dir: %w", err)
 	}
 	// Create witness hooks for scout triggering
 	if err := m.createPatrolHooks(witnessPath, runtimeConfig); err != nil {
@@ -473,7 +624,7 @@ Use clan for your own workspace. Raiders are for batch work dispatch.
 	// Create raiders directory (empty)
 	raidersPath := filepath.Join(rigPath, "raiders")
 	if err := os.MkdirAll(raidersPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating raiders dir: %w", err)
+		return nil, nil, fmt.Errorf("creating raiders dir: %w", err)
 	}
 
 	// Install Claude settings for all agent directories.
@@ -499,7 +650,7 @@ Use clan for your own workspace. Raiders are for batch work dispatch.
 	// Initialize relics at warband level
 	fmt.Printf("  Initializing relics database...\n")
 	if err := m.initRelics(rigPath, opts.RelicsPrefix); err != nil {
-		return nil, fmt.Errorf("initializing relics: %w", err)
+		return nil, nil, fmt.Errorf("initializing relics: %w", err)
 	}
 	fmt.Printf("   ✓ Initialized relics (prefix: %s)\n", opts.RelicsPrefix)
 
@@ -522,18 +673,29 @@ Use clan for your own workspace. Raiders are for batch work dispatch.
 		fmt.Fprintf(os.Stderr, "  Warning: Could not create plugin directories: %v\n", err)
 	}
 
-	// Register in encampment config
+	// Register in encampment config (keeping the original registration time)
+	addedAt := time.Now()
+	if entry, ok := m.config.Warbands[opts.Name]; ok && !entry.AddedAt.IsZero() {
+		addedAt = entry.AddedAt
+		report.Skip("registration")
+	} else {
+		report.Perform("registration")
+	}
 	m.config.Warbands[opts.Name] = config.RigEntry{
 		GitURL:    opts.GitURL,
 		LocalRepo: localRepo,
-		AddedAt:   time.Now(),
+		AddedAt:   addedAt,
 		RelicsConfig: &config.RelicsConfig{
 			Prefix: opts.RelicsPrefix,
 		},
 	}
 
 	success = true
-	return m.loadRig(opts.Name, m.config.Warbands[opts.Name])
+	r, err := m.loadRig(opts.Name, m.config.Warbands[opts.Name])
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, report, nil
 }
 
 // saveRigConfig writes the warband configuration to config.json.
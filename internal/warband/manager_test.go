@@ -2,6 +2,7 @@ package warband
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/deeklead/horde/internal/config"
 	"github.com/deeklead/horde/internal/git"
+	"github.com/deeklead/horde/internal/relics"
 )
 
 func setupTestTown(t *testing.T) (string, *config.RigsConfig) {
@@ -217,6 +219,77 @@ func TestAddRig_RejectsInvalidNames(t *testing.T) {
 	}
 }
 
+func TestAddRig_FullyCreatedRigExists(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Warbands["demo"] = config.RigEntry{GitURL: "git@github.com:test/demo.git"}
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+
+	// Lay out a warband that AddRig and hd warband add completed.
+	rigPath := filepath.Join(root, "demo")
+	if err := os.MkdirAll(filepath.Join(rigPath, ".relics"), 0755); err != nil {
+		t.Fatalf("mkdir warband relics: %v", err)
+	}
+	if err := manager.saveRigConfig(rigPath, &RigConfig{
+		Type:   "warband",
+		Name:   "demo",
+		GitURL: "git@github.com:test/demo.git",
+		Relics: &RelicsConfig{Prefix: "dm"},
+	}); err != nil {
+		t.Fatalf("save warband config: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "--bare", filepath.Join(rigPath, ".repo.git")},
+		{"init", filepath.Join(rigPath, "warchief", "warband")},
+		{"init", filepath.Join(rigPath, "forge", "warband")},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v (%s)", strings.Join(args, " "), err, out)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".relics"), 0755); err != nil {
+		t.Fatalf("mkdir encampment relics: %v", err)
+	}
+	if err := relics.AppendRoute(root, relics.Route{Prefix: "dm-", Path: "demo"}); err != nil {
+		t.Fatalf("append route: %v", err)
+	}
+
+	// The identity bead exists.
+	script := `#!/usr/bin/env bash
+while [[ "$1" == --* ]]; do
+  shift
+done
+if [[ "$1" == "show" ]]; then
+  printf '[{"id":"%s","title":"demo"}]' "$2"
+  exit 0
+fi
+echo "unexpected command: $1" >&2
+exit 1
+`
+	binDir := writeFakeBD(t, script)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if missing := manager.missingRigSteps("demo", rigPath, &RigConfig{Relics: &RelicsConfig{Prefix: "dm"}}); len(missing) != 0 {
+		t.Fatalf("missingRigSteps = %v, want none", missing)
+	}
+
+	_, err := manager.AddRig(AddRigOptions{
+		Name:   "demo",
+		GitURL: "git@github.com:test/demo.git",
+	})
+	if err != ErrRigExists {
+		t.Fatalf("AddRig = %v, want ErrRigExists", err)
+	}
+
+	// Dropping the route makes the warband resumable again.
+	if err := relics.RemoveRoute(root, "dm-"); err != nil {
+		t.Fatalf("remove route: %v", err)
+	}
+	missing := manager.missingRigSteps("demo", rigPath, &RigConfig{Relics: &RelicsConfig{Prefix: "dm"}})
+	if !slices.Equal(missing, []string{"route"}) {
+		t.Errorf("missingRigSteps = %v, want [route]", missing)
+	}
+}
+
 func TestListRigNames(t *testing.T) {
 	root, rigsConfig := setupTestTown(t)
 	rigsConfig.Warbands["rig1"] = config.RigEntry{}